@@ -0,0 +1,55 @@
+package activities
+
+import (
+	"mint-redeem-workflow/config"
+	"mint-redeem-workflow/infra/brale"
+	"mint-redeem-workflow/infra/sanctions"
+	"mint-redeem-workflow/repository"
+	"mint-redeem-workflow/webhooks"
+	"net/http"
+	"time"
+
+	"go.uber.org/cadence/activity"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// RegisterOptions registers the methods of an Activities under the names the
+// activities had as package functions, like
+// "mint-redeem-workflow/activities.UpdateStatusActivity", so workflows that
+// scheduled them before still replay.
+var RegisterOptions = activity.RegisterOptions{Name: "mint-redeem-workflow/activities."}
+
+// Activities holds what the activities run against. The worker builds one at
+// startup and registers it with RegisterOptions. Workflows schedule its
+// methods through a nil *Activities, which Cadence resolves by name.
+type Activities struct {
+	BraleClient brale.BraleClient
+	DB          *gorm.DB
+
+	// Requests is where requests are read and their status changes saved.
+	Requests repository.RequestRepository
+
+	// Sanctions is the list recipients are screened against.
+	Sanctions *sanctions.List
+
+	// HTTPClient posts webhook deliveries.
+	HTTPClient *http.Client
+
+	Logger *zap.Logger
+	Config *config.ServiceConfig
+}
+
+// New returns the Activities of a worker. Webhooks are posted with a 10 second
+// timeout, and only to public addresses.
+func New(braleClient brale.BraleClient, db *gorm.DB, requests repository.RequestRepository, sanctionsList *sanctions.List, logger *zap.Logger, cfg *config.ServiceConfig) *Activities {
+	return &Activities{
+		BraleClient: braleClient,
+		DB:          db,
+		Requests:    requests,
+		Sanctions:   sanctionsList,
+		HTTPClient:  webhooks.NewHTTPClient(time.Second * 10),
+		Logger:      logger,
+		Config:      cfg,
+	}
+}
@@ -0,0 +1,136 @@
+package activities
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/repository"
+
+	"gorm.io/gorm"
+)
+
+type VerifyApprovalActivityResponse struct {
+	Valid  bool
+	Reason string
+}
+
+type signedApprovalEvent struct {
+	Approver  string `json:"approver"`
+	Approved  bool   `json:"approved"`
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+}
+
+// VerifyApprovalDecisionActivity checks the signature of an approval decision
+// against the approver's registered key and the request's decision payload,
+// which includes the decision and the approver. The key also has to be bound
+// to the client that sent the decision.
+func (a *Activities) VerifyApprovalDecisionActivity(ctx context.Context, requestID string, approver string, client string, approved bool, signature string) (VerifyApprovalActivityResponse, error) {
+	request, err := a.getRequest(requestID)
+	if err != nil {
+		return VerifyApprovalActivityResponse{}, err
+	}
+
+	var key models.ApproverKey
+	if err := a.DB.First(&key, "approver = ?", approver).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return VerifyApprovalActivityResponse{Reason: "approver has no registered key"}, nil
+		}
+		return VerifyApprovalActivityResponse{}, err
+	}
+
+	if key.Client != client {
+		return VerifyApprovalActivityResponse{Reason: "approver key is bound to another client"}, nil
+	}
+
+	if err := key.Verify(request.ApprovalDecisionPayload(approver, approved), signature); err != nil {
+		return VerifyApprovalActivityResponse{Reason: err.Error()}, nil
+	}
+
+	return VerifyApprovalActivityResponse{Valid: true}, nil
+}
+
+// VerifyApprovalActivity checks the approval signature against the approver's
+// registered key and the request's canonical approval payload. It is what
+// workflows started before VerifyApprovalDecisionActivity verify with, and
+// stays registered until none of those workflows are open.
+func (a *Activities) VerifyApprovalActivity(ctx context.Context, requestID string, approver string, signature string) (VerifyApprovalActivityResponse, error) {
+	request, err := a.getRequest(requestID)
+	if err != nil {
+		return VerifyApprovalActivityResponse{}, err
+	}
+
+	var key models.ApproverKey
+	if err := a.DB.First(&key, "approver = ?", approver).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return VerifyApprovalActivityResponse{Reason: "approver has no registered key"}, nil
+		}
+		return VerifyApprovalActivityResponse{}, err
+	}
+
+	if err := key.Verify(request.ApprovalPayload(), signature); err != nil {
+		return VerifyApprovalActivityResponse{Reason: err.Error()}, nil
+	}
+
+	return VerifyApprovalActivityResponse{Valid: true}, nil
+}
+
+// RecordApprovalActivity appends an approval decision to the request's
+// approval trail. Signed decisions also keep the signed payload and signature
+// in the request's event history. That is the decision payload, or the
+// request's approval payload for a decision signed before decisions were.
+func (a *Activities) RecordApprovalActivity(ctx context.Context, requestID string, approver string, approved bool, reason string, signature string) error {
+	request, err := a.getRequest(requestID)
+	if err != nil {
+		return err
+	}
+
+	return a.Requests.Transaction(func(requests repository.RequestRepository) error {
+		approval := models.Approval{
+			RequestID: request.ID,
+			Approver:  approver,
+			Approved:  approved,
+			Reason:    reason,
+		}
+		if err := requests.CreateApproval(&approval); err != nil {
+			return err
+		}
+
+		if signature == "" {
+			return nil
+		}
+
+		data, err := json.Marshal(signedApprovalEvent{
+			Approver:  approver,
+			Approved:  approved,
+			Payload:   string(a.signedPayload(request, approver, approved, signature)),
+			Signature: signature,
+		})
+		if err != nil {
+			return err
+		}
+
+		return requests.AppendEvent(&models.RequestEvent{
+			RequestID: request.ID,
+			Type:      "approval_signed",
+			Data:      string(data),
+		})
+	})
+}
+
+// signedPayload is the payload the approver's signature is over: the decision
+// payload, unless the signature only verifies against the approval payload.
+func (a *Activities) signedPayload(request *models.Request, approver string, approved bool, signature string) []byte {
+	payload := request.ApprovalDecisionPayload(approver, approved)
+
+	var key models.ApproverKey
+	if err := a.DB.First(&key, "approver = ?", approver).Error; err != nil || key.Verify(payload, signature) == nil {
+		return payload
+	}
+	if key.Verify(request.ApprovalPayload(), signature) == nil {
+		return request.ApprovalPayload()
+	}
+
+	return payload
+}
@@ -0,0 +1,19 @@
+package activities
+
+import (
+	"context"
+	"fmt"
+	"mint-redeem-workflow/models"
+)
+
+func (a *Activities) UpdateBatchStatusActivity(ctx context.Context, batchID string, status string) error {
+	result := a.DB.Model(&models.Batch{}).Where("id = ?", batchID).Update("status", status)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("batch with ID %s not found", batchID)
+	}
+
+	return nil
+}
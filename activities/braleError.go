@@ -0,0 +1,14 @@
+package activities
+
+import (
+	"mint-redeem-workflow/infra/brale"
+
+	"go.uber.org/cadence"
+)
+
+// braleError turns a classified Brale error into a cadence custom error so
+// the activity retry policies can tell retryable failures from terminal ones
+// by reason.
+func braleError(err *brale.Error) error {
+	return cadence.NewCustomError(err.Reason, err.Detail)
+}
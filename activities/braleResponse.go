@@ -0,0 +1,22 @@
+package activities
+
+import "mint-redeem-workflow/infra/brale"
+
+// orderID returns the Brale order ID (data.id) of an order response.
+func orderID(resp *brale.APIResponse) string {
+	if resp.Data == nil {
+		return ""
+	}
+	return resp.Data.ID
+}
+
+// txHash returns the on-chain transaction hash of the first included
+// transaction that has one. It is empty while the order is still pending.
+func txHash(resp *brale.APIResponse) string {
+	for _, included := range resp.Included {
+		if included.Attributes.Hash != "" {
+			return included.Attributes.Hash
+		}
+	}
+	return ""
+}
@@ -0,0 +1,23 @@
+package activities
+
+import (
+	"context"
+	"mint-redeem-workflow/operations"
+)
+
+// PostLedgerEntryActivity posts a completed request to the ledger. Requests
+// that end in any other terminal status moved no funds and aren't posted.
+// Posting is idempotent, so a retry after a timeout doesn't post twice.
+func (a *Activities) PostLedgerEntryActivity(ctx context.Context, requestID string) error {
+	request, err := a.getRequest(requestID)
+	if err != nil {
+		return err
+	}
+
+	if request.Status != "completed" {
+		return nil
+	}
+
+	_, err = operations.PostRequest(a.DB, *request)
+	return err
+}
@@ -0,0 +1,93 @@
+package activities
+
+import (
+	"context"
+	"mint-redeem-workflow/infra/brale"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/operations"
+	"mint-redeem-workflow/repository"
+	"time"
+
+	"go.uber.org/cadence"
+	"go.uber.org/zap"
+)
+
+type OperationActivityResponse struct {
+	RequestId string
+	OrderID   string
+	TxHash    string
+}
+
+// ExecuteOperationActivity places the request's order with Brale through the
+// operation registered for its type and saves the order ID on the request.
+// A request that was canceled before its order was sent fails with a
+// "canceled" error instead.
+func (a *Activities) ExecuteOperationActivity(ctx context.Context, opType string, amount float64, recipient string, token string, chain string, requestId string) (OperationActivityResponse, error) {
+	response := OperationActivityResponse{RequestId: requestId}
+
+	op, err := operations.Lookup(opType)
+	if err != nil {
+		return response, err
+	}
+
+	// The request is marked as sent before the order is, in the same way a
+	// cancel moves it, so either the cancel lands first and stops the order
+	// here or it finds the mark and is refused.
+	if err := a.updateStatus(requestId, repository.StatusUpdate{
+		Check: func(request *models.Request) error {
+			if request.Status == "canceled" {
+				return cadence.NewCustomError(operations.ReasonCanceled, "request was canceled before its order was sent")
+			}
+			return nil
+		},
+		Apply: func(request *models.Request) {
+			if request.OrderSentAt == nil {
+				now := time.Now()
+				request.OrderSentAt = &now
+			}
+		},
+	}); err != nil {
+		return response, err
+	}
+
+	params := operations.Params{Amount: amount, Recipient: recipient, Token: token, Chain: chain}
+	resp, err := op.Execute(a.BraleClient, params, requestId)
+	if err != nil {
+		a.Logger.Warn("Brale order failed.", zap.String("RequestID", requestId), zap.Error(err))
+		return response, braleError(brale.NewNetworkError(err))
+	}
+
+	if len(resp.Errors) > 0 {
+		a.Logger.Warn("Brale rejected order.", zap.String("RequestID", requestId), zap.Int("Errors", len(resp.Errors)))
+		return response, braleError(brale.NewResponseError(resp))
+	}
+
+	response.OrderID = orderID(resp)
+	response.TxHash = txHash(resp)
+
+	// The order ID is saved as soon as Brale has the order, so the request
+	// can't be canceled from under it. If saving fails, the retry gets the
+	// same order back under the idempotency key.
+	if response.OrderID != "" {
+		if err := a.updateStatus(requestId, repository.StatusUpdate{
+			Apply: func(request *models.Request) {
+				request.ProviderOrderID = response.OrderID
+			},
+		}); err != nil {
+			return response, err
+		}
+	}
+
+	return response, nil
+}
+
+// MintActivity and RedeemActivity are what workflows started before
+// ExecuteOperationActivity schedule. They stay registered until none of those
+// workflows are open.
+func (a *Activities) MintActivity(ctx context.Context, amount float64, recipient string, token string, chain string, requestId string) (OperationActivityResponse, error) {
+	return a.ExecuteOperationActivity(ctx, "mint", amount, recipient, token, chain, requestId)
+}
+
+func (a *Activities) RedeemActivity(ctx context.Context, amount float64, recipient string, token string, chain string, requestId string) (OperationActivityResponse, error) {
+	return a.ExecuteOperationActivity(ctx, "redeem", amount, recipient, token, chain, requestId)
+}
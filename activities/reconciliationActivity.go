@@ -0,0 +1,186 @@
+package activities
+
+import (
+	"context"
+	"fmt"
+	"mint-redeem-workflow/infra/brale"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/reconciliation"
+	"time"
+
+	"go.uber.org/cadence/activity"
+	"gorm.io/gorm"
+)
+
+// ReconcileActivityResponse counts what ReconcileActivity pulled from Brale.
+// The items themselves are saved on the report rather than returned, so the
+// result stays small however busy the range was.
+type ReconcileActivityResponse struct {
+	Orders    int
+	Transfers int
+}
+
+// ReconcileActivity pulls Brale's orders and transfers for the report's range,
+// reconciles them against the requests created in the range that reached
+// Brale, and saves the items and totals on the report. Running it again
+// replaces the previous items.
+func (a *Activities) ReconcileActivity(ctx context.Context, reportID string) (ReconcileActivityResponse, error) {
+	report, err := a.reconciliationReport(reportID)
+	if err != nil {
+		return ReconcileActivityResponse{}, err
+	}
+
+	orders, err := a.listOrders(ctx, report.From, report.To)
+	if err != nil {
+		return ReconcileActivityResponse{}, err
+	}
+	transfers, err := a.listTransfers(ctx, report.From, report.To)
+	if err != nil {
+		return ReconcileActivityResponse{}, err
+	}
+
+	requests, err := a.reconciledRequests(report)
+	if err != nil {
+		return ReconcileActivityResponse{}, err
+	}
+
+	items := append(reconciliation.Reconcile(requests, orders), reconciliation.ReconcileTransfers(requests, transfers)...)
+	if err := a.storeReconciliation(&report, items); err != nil {
+		return ReconcileActivityResponse{}, err
+	}
+
+	return ReconcileActivityResponse{Orders: len(orders), Transfers: len(transfers)}, nil
+}
+
+// FetchBraleOrdersActivity and StoreReconciliationActivity are what
+// reconciliations started before ReconcileActivity schedule. They stay
+// registered until none of those workflows are open.
+func (a *Activities) FetchBraleOrdersActivity(ctx context.Context, from time.Time, to time.Time) ([]brale.Order, error) {
+	return a.listOrders(ctx, from, to)
+}
+
+func (a *Activities) StoreReconciliationActivity(ctx context.Context, reportID string, orders []brale.Order) error {
+	report, err := a.reconciliationReport(reportID)
+	if err != nil {
+		return err
+	}
+
+	requests, err := a.reconciledRequests(report)
+	if err != nil {
+		return err
+	}
+
+	return a.storeReconciliation(&report, reconciliation.Reconcile(requests, orders))
+}
+
+// listOrders pages through every Brale order created in [from, to).
+func (a *Activities) listOrders(ctx context.Context, from time.Time, to time.Time) ([]brale.Order, error) {
+	var orders []brale.Order
+	cursor := ""
+	for {
+		page, err := a.BraleClient.ListOrders(from, to, cursor)
+		if err != nil {
+			return nil, braleError(brale.NewNetworkError(err))
+		}
+		if len(page.Errors) > 0 {
+			return nil, braleError(brale.NewResponseError(&brale.APIResponse{Errors: page.Errors}))
+		}
+
+		orders = append(orders, page.Data...)
+		activity.RecordHeartbeat(ctx, len(orders))
+		if page.Links.Next == "" {
+			return orders, nil
+		}
+		cursor = page.Links.Next
+	}
+}
+
+// listTransfers pages through every Brale transfer created in [from, to).
+func (a *Activities) listTransfers(ctx context.Context, from time.Time, to time.Time) ([]brale.Transfer, error) {
+	var transfers []brale.Transfer
+	cursor := ""
+	for {
+		page, err := a.BraleClient.ListTransfers(from, to, cursor)
+		if err != nil {
+			return nil, braleError(brale.NewNetworkError(err))
+		}
+		if len(page.Errors) > 0 {
+			return nil, braleError(brale.NewResponseError(&brale.APIResponse{Errors: page.Errors}))
+		}
+
+		transfers = append(transfers, page.Data...)
+		activity.RecordHeartbeat(ctx, len(transfers))
+		if page.Links.Next == "" {
+			return transfers, nil
+		}
+		cursor = page.Links.Next
+	}
+}
+
+func (a *Activities) reconciliationReport(reportID string) (models.ReconciliationReport, error) {
+	var report models.ReconciliationReport
+	if err := a.DB.First(&report, "id = ?", reportID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return report, fmt.Errorf("reconciliation report with ID %s not found", reportID)
+		}
+		return report, err
+	}
+	return report, nil
+}
+
+// reconciledRequests returns the requests created in the report's range that
+// reached Brale: completed or refunded ones, or ones with an order ID.
+func (a *Activities) reconciledRequests(report models.ReconciliationReport) ([]models.Request, error) {
+	var requests []models.Request
+	err := a.DB.
+		Where("created_at >= ? AND created_at < ?", report.From, report.To).
+		Where("status IN ? OR provider_order_id <> ''", []string{"completed", "refunded"}).
+		Order("created_at").
+		Find(&requests).Error
+	return requests, err
+}
+
+// storeReconciliation replaces the report's items and saves their totals.
+func (a *Activities) storeReconciliation(report *models.ReconciliationReport, items []models.ReconciliationItem) error {
+	reconciliation.Summarize(report, items)
+	now := time.Now()
+
+	return a.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("report_id = ?", report.ID).Delete(&models.ReconciliationItem{}).Error; err != nil {
+			return err
+		}
+
+		for i := range items {
+			items[i].ReportID = report.ID
+		}
+		if len(items) > 0 {
+			if err := tx.Create(&items).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(report).Updates(map[string]interface{}{
+			"status":           "completed",
+			"matched":          report.Matched,
+			"missing_locally":  report.MissingLocally,
+			"missing_remotely": report.MissingRemotely,
+			"amount_mismatch":  report.AmountMismatch,
+			"completed_at":     &now,
+		}).Error
+	})
+}
+
+func (a *Activities) FailReconciliationActivity(ctx context.Context, reportID string, detail string) error {
+	result := a.DB.Model(&models.ReconciliationReport{}).Where("id = ?", reportID).Updates(map[string]interface{}{
+		"status": "failed",
+		"error":  detail,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("reconciliation report with ID %s not found", reportID)
+	}
+
+	return nil
+}
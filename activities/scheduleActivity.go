@@ -0,0 +1,87 @@
+package activities
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CreateScheduledRequestActivityResponse struct {
+	RequestId string
+	Type      string
+	Amount    float64
+	Recipient string
+	Token     string
+	Chain     string
+	Submitter string
+	Skipped   bool
+	Reason    string
+}
+
+// CreateScheduledRequestActivity creates the request for one firing of a
+// schedule. The request ID is derived from the schedule and occurrence IDs, so
+// a retried or replayed firing gets back the request it already created
+// instead of a second one. Firings of a schedule that isn't active are skipped.
+func (a *Activities) CreateScheduledRequestActivity(ctx context.Context, scheduleID string, occurrenceID string) (CreateScheduledRequestActivityResponse, error) {
+	var schedule models.Schedule
+	if err := a.DB.First(&schedule, "id = ?", scheduleID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return CreateScheduledRequestActivityResponse{}, fmt.Errorf("schedule with ID %s not found", scheduleID)
+		}
+		return CreateScheduledRequestActivityResponse{}, err
+	}
+
+	if schedule.Status != "active" {
+		return CreateScheduledRequestActivityResponse{Skipped: true, Reason: "schedule is " + schedule.Status}, nil
+	}
+
+	request, err := a.createScheduledRequest(&schedule, uuid.NewSHA1(schedule.ID, []byte(occurrenceID)))
+	if err != nil {
+		return CreateScheduledRequestActivityResponse{}, err
+	}
+
+	return CreateScheduledRequestActivityResponse{
+		RequestId: request.ID.String(),
+		Type:      request.Type,
+		Amount:    request.Amount,
+		Recipient: request.Recipient,
+		Token:     request.Token,
+		Chain:     request.Chain,
+		Submitter: request.Submitter,
+	}, nil
+}
+
+// createScheduledRequest creates the request with the ID, or returns the one a
+// previous run of the activity already created.
+func (a *Activities) createScheduledRequest(schedule *models.Schedule, requestID uuid.UUID) (*models.Request, error) {
+	request, err := a.Requests.Get(requestID.String())
+	if !errors.Is(err, repository.ErrRequestNotFound) {
+		return request, err
+	}
+
+	request = &models.Request{
+		ID:         requestID,
+		Type:       schedule.Type,
+		Amount:     schedule.Amount,
+		Recipient:  schedule.Recipient,
+		Token:      schedule.Token,
+		Chain:      schedule.Chain,
+		Submitter:  schedule.Submitter,
+		Status:     "started",
+		ScheduleID: &schedule.ID,
+	}
+	if err := a.Requests.Create(request); err != nil {
+		// A concurrent run of the activity may have created it first.
+		if existing, getErr := a.Requests.Get(requestID.String()); getErr == nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+
+	return request, nil
+}
@@ -0,0 +1,33 @@
+package activities
+
+import (
+	"context"
+	"fmt"
+)
+
+type ScreeningActivityResponse struct {
+	RequestId string
+	Blocked   bool
+	Reason    string
+}
+
+func (a *Activities) ScreeningActivity(ctx context.Context, recipient string, requestId string) (ScreeningActivityResponse, error) {
+	if a.Sanctions == nil {
+		return ScreeningActivityResponse{
+			RequestId: requestId,
+		}, fmt.Errorf("sanctions list not loaded")
+	}
+
+	entry, matched := a.Sanctions.Match(recipient)
+	if !matched {
+		return ScreeningActivityResponse{
+			RequestId: requestId,
+		}, nil
+	}
+
+	return ScreeningActivityResponse{
+		RequestId: requestId,
+		Blocked:   true,
+		Reason:    fmt.Sprintf("recipient matches sanctioned %s address of %s (SDN uid %s)", entry.Currency, entry.Name, entry.UID),
+	}, nil
+}
@@ -2,26 +2,136 @@ package activities
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"mint-redeem-workflow/db"
 	"mint-redeem-workflow/models"
-
-	"gorm.io/gorm"
+	"mint-redeem-workflow/repository"
+	"time"
 )
 
-func UpdateStatusActivity(ctx context.Context, requestID string, status string) error {
-	var request models.Request
-	if err := db.Db.First(&request, "id = ?", requestID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return fmt.Errorf("request with ID %s not found", requestID)
-		}
-		return err
+func (a *Activities) UpdateStatusActivity(ctx context.Context, requestID string, status string) error {
+	return a.UpdateStatusWithReasonActivity(ctx, requestID, status, "")
+}
+
+func (a *Activities) UpdateStatusWithReasonActivity(ctx context.Context, requestID string, status string, reason string) error {
+	return a.updateStatus(requestID, repository.StatusUpdate{
+		To:     status,
+		Reason: reason,
+		Apply: func(request *models.Request) {
+			request.Reason = reason
+		},
+	})
+}
+
+// CompleteRequestActivity marks the request as completed with the Brale order
+// ID and transaction hash of the order that completed it.
+func (a *Activities) CompleteRequestActivity(ctx context.Context, requestID string, orderID string, txHash string) error {
+	return a.updateStatus(requestID, repository.StatusUpdate{
+		To: "completed",
+		Apply: func(request *models.Request) {
+			now := time.Now()
+			request.ProviderOrderID = orderID
+			request.TxHash = txHash
+			request.CompletedAt = &now
+		},
+	})
+}
+
+// FailRequestActivity marks the request as failed with the error code and
+// detail of the failure that ended the workflow.
+func (a *Activities) FailRequestActivity(ctx context.Context, requestID string, errorCode string, errorDetail string) error {
+	return a.updateStatus(requestID, repository.StatusUpdate{
+		To:     "failed",
+		Reason: errorCode,
+		Apply: func(request *models.Request) {
+			now := time.Now()
+			request.ErrorCode = errorCode
+			request.ErrorDetail = errorDetail
+			request.FailedAt = &now
+		},
+	})
+}
+
+// updateStatus saves the status change, which records it in the request's
+// event history. The webhook dispatcher delivers it from there.
+func (a *Activities) updateStatus(requestID string, update repository.StatusUpdate) error {
+	_, err := a.Requests.UpdateStatus(requestID, update)
+	return requestError(requestID, err)
+}
+
+// getRequest returns the request, with an error that names it if it doesn't
+// exist.
+func (a *Activities) getRequest(requestID string) (*models.Request, error) {
+	request, err := a.Requests.Get(requestID)
+	if err != nil {
+		return nil, requestError(requestID, err)
 	}
 
-	request.Status = status
-	if err := db.Db.Save(&request).Error; err != nil {
-		return err
+	return request, nil
+}
+
+func requestError(requestID string, err error) error {
+	if errors.Is(err, repository.ErrRequestNotFound) {
+		return fmt.Errorf("request with ID %s not found", requestID)
+	}
+	return err
+}
+
+// StartScheduledRequestActivityResponse is what a scheduled request's workflow
+// does when its timer fires. Canceled means the request was canceled, and a
+// non-zero ExecuteAt is a later time it was rescheduled to. Otherwise the
+// request was started.
+type StartScheduledRequestActivityResponse struct {
+	Canceled  bool
+	ExecuteAt time.Time
+}
+
+// errNotStarted stops StartScheduledRequestActivity's update when the request
+// was canceled or rescheduled.
+var errNotStarted = errors.New("scheduled request was not started")
+
+// StartScheduledRequestActivity moves a request whose executeAt timer fired
+// to "started", but only while it is still scheduled for that time. The
+// cancel and reschedule endpoints save their change on the request before
+// signaling, so one that lands as the timer fires is found here.
+func (a *Activities) StartScheduledRequestActivity(ctx context.Context, requestID string, executeAt time.Time) (StartScheduledRequestActivityResponse, error) {
+	var response StartScheduledRequestActivityResponse
+	err := a.updateStatus(requestID, repository.StatusUpdate{
+		From: "scheduled",
+		To:   "started",
+		Check: func(request *models.Request) error {
+			if request.ExecuteAt != nil && request.ExecuteAt.After(executeAt) {
+				response.ExecuteAt = *request.ExecuteAt
+				return errNotStarted
+			}
+			return nil
+		},
+	})
+	if errors.Is(err, errNotStarted) {
+		return response, nil
+	}
+	if errors.Is(err, repository.ErrStatusConflict) {
+		request, err := a.getRequest(requestID)
+		if err != nil {
+			return response, err
+		}
+		// A canceled request stays canceled. Any other status means an
+		// earlier attempt of this activity already started it.
+		response.Canceled = request.Status == "canceled"
+		return response, nil
 	}
 
-	return nil
+	return response, err
+}
+
+// RescheduleRequestActivity records the new execute_at time of a scheduled
+// request.
+func (a *Activities) RescheduleRequestActivity(ctx context.Context, requestID string, executeAt time.Time) error {
+	_, err := a.Requests.UpdateStatus(requestID, repository.StatusUpdate{
+		Apply: func(request *models.Request) {
+			request.ExecuteAt = &executeAt
+		},
+	})
+
+	return requestError(requestID, err)
 }
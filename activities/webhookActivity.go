@@ -0,0 +1,63 @@
+package activities
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/webhooks"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DeliverWebhookActivity makes one attempt at posting the delivery and records
+// it. A failed attempt is returned as an error so that the retry policy backs
+// off and tries again.
+func (a *Activities) DeliverWebhookActivity(ctx context.Context, deliveryID string) error {
+	var delivery models.WebhookDelivery
+	if err := a.DB.Preload("Webhook").First(&delivery, "id = ?", deliveryID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("webhook delivery with ID %s not found", deliveryID)
+		}
+		return err
+	}
+	if delivery.Status == "delivered" {
+		return nil
+	}
+
+	now := time.Now()
+	statusCode, deliverErr := webhooks.Deliver(a.HTTPClient, delivery.Webhook, delivery, now)
+
+	updates := map[string]interface{}{
+		"attempts":         gorm.Expr("attempts + 1"),
+		"last_status_code": statusCode,
+		"last_error":       "",
+		"last_attempt_at":  &now,
+	}
+	if deliverErr != nil {
+		updates["last_error"] = deliverErr.Error()
+	} else {
+		updates["status"] = "delivered"
+		updates["delivered_at"] = &now
+	}
+	if err := a.DB.Model(&delivery).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	return deliverErr
+}
+
+// FailWebhookDeliveryActivity marks a delivery as failed once its retries are
+// used up. It can still be redelivered.
+func (a *Activities) FailWebhookDeliveryActivity(ctx context.Context, deliveryID string) error {
+	result := a.DB.Model(&models.WebhookDelivery{}).Where("id = ?", deliveryID).Update("status", "failed")
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("webhook delivery with ID %s not found", deliveryID)
+	}
+
+	return nil
+}
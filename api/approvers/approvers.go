@@ -0,0 +1,98 @@
+package approvers
+
+import (
+	"errors"
+	"mint-redeem-workflow/api/auth"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Service is what the approver handlers need from the service layer.
+type Service interface {
+	RegisterApproverKey(approver string, client string, publicKey string) (*models.ApproverKey, error)
+	RotateApproverKey(approver string, publicKey string, signature string) (*models.ApproverKey, error)
+}
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) RegisterRoutes(r gin.IRouter) {
+	r.POST("/approvers/keys", auth.RequireOperator, h.HandleRegisterKey)
+	r.POST("/approvers/keys/rotate", h.HandleRotateKey)
+}
+
+// RegisterKeyRequest binds the approver's key to the API client the approver
+// sends decisions as.
+type RegisterKeyRequest struct {
+	Approver  string `json:"approver" binding:"required"`
+	Client    string `json:"client" binding:"required"`
+	PublicKey string `json:"public_key" binding:"required"`
+}
+
+type RotateKeyRequest struct {
+	Approver  string `json:"approver" binding:"required"`
+	PublicKey string `json:"public_key" binding:"required"`
+
+	// Signature is the base64 signature of the rotation payload made with
+	// the approver's current key.
+	Signature string `json:"signature" binding:"required"`
+}
+
+// HandleRegisterKey registers the first key of an approver. Only operators
+// can call it.
+func (h *Handler) HandleRegisterKey(c *gin.Context) {
+	var req RegisterKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	key, err := h.service.RegisterApproverKey(req.Approver, req.Client, req.PublicKey)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"approver": key.Approver, "client": key.Client, "public_key": key.PublicKey})
+}
+
+// HandleRotateKey replaces an approver's key with a new one signed over by
+// the current key.
+func (h *Handler) HandleRotateKey(c *gin.Context) {
+	var req RotateKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	key, err := h.service.RotateApproverKey(req.Approver, req.PublicKey, req.Signature)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"approver": key.Approver, "client": key.Client, "public_key": key.PublicKey})
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidPublicKey), errors.Is(err, service.ErrUnknownClient):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidRotationSignature):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUnknownApprover):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrApproverKeyExists), errors.Is(err, service.ErrClientHasApproverKey):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,124 @@
+package approvers
+
+import (
+	"bytes"
+	"encoding/json"
+	"mint-redeem-workflow/api/auth"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeService struct {
+	registerApproverKey func(approver string, client string, publicKey string) (*models.ApproverKey, error)
+	rotateApproverKey   func(approver string, publicKey string, signature string) (*models.ApproverKey, error)
+}
+
+func (f *fakeService) RegisterApproverKey(approver string, client string, publicKey string) (*models.ApproverKey, error) {
+	return f.registerApproverKey(approver, client, publicKey)
+}
+
+func (f *fakeService) RotateApproverKey(approver string, publicKey string, signature string) (*models.ApproverKey, error) {
+	return f.rotateApproverKey(approver, publicKey, signature)
+}
+
+func performRegisterKey(svc Service, body interface{}) *httptest.ResponseRecorder {
+	return post(svc, auth.Client{Name: "operator", Operator: true}, "/approvers/keys", body)
+}
+
+func post(svc Service, client auth.Client, path string, body interface{}) *httptest.ResponseRecorder {
+	r := gin.New()
+	r.Use(func(c *gin.Context) { auth.SetClient(c, client) })
+	NewHandler(svc).RegisterRoutes(r)
+
+	reqBody, _ := json.Marshal(body)
+	req, _ := http.NewRequest(http.MethodPost, path, bytes.NewBuffer(reqBody))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestHandleRegisterKey_SuccessReturns200(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{registerApproverKey: func(approver string, client string, publicKey string) (*models.ApproverKey, error) {
+		return &models.ApproverKey{Approver: approver, Client: client, PublicKey: publicKey}, nil
+	}}
+
+	rec := performRegisterKey(svc, RegisterKeyRequest{Approver: "alice", Client: "globex", PublicKey: "a2V5"})
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp map[string]string
+	err := json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", resp["approver"])
+	assert.Equal(t, "globex", resp["client"])
+}
+
+func TestHandleRegisterKey_ServiceErrorsMapToStatusCodes(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{registerApproverKey: func(approver string, client string, publicKey string) (*models.ApproverKey, error) {
+		return nil, service.ErrInvalidPublicKey
+	}}
+	rec := performRegisterKey(svc, RegisterKeyRequest{Approver: "alice", Client: "globex", PublicKey: "a2V5"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	svc.registerApproverKey = func(approver string, client string, publicKey string) (*models.ApproverKey, error) {
+		return nil, service.ErrApproverKeyExists
+	}
+	rec = performRegisterKey(svc, RegisterKeyRequest{Approver: "alice", Client: "globex", PublicKey: "a2V5"})
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	svc.registerApproverKey = func(approver string, client string, publicKey string) (*models.ApproverKey, error) {
+		return nil, service.ErrUnknownClient
+	}
+	rec = performRegisterKey(svc, RegisterKeyRequest{Approver: "alice", Client: "nobody", PublicKey: "a2V5"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = performRegisterKey(svc, RegisterKeyRequest{Approver: "alice", PublicKey: "a2V5"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandleRegisterKey_NonOperatorReturns403(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{registerApproverKey: func(approver string, client string, publicKey string) (*models.ApproverKey, error) {
+		t.Fatal("registered a key for a client that isn't an operator")
+		return nil, nil
+	}}
+
+	rec := post(svc, auth.Client{Name: "acme"}, "/approvers/keys", RegisterKeyRequest{Approver: "alice", Client: "globex", PublicKey: "a2V5"})
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestHandleRotateKey_ServiceErrorsMapToStatusCodes(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{rotateApproverKey: func(approver string, publicKey string, signature string) (*models.ApproverKey, error) {
+		assert.Equal(t, "c2ln", signature)
+		return &models.ApproverKey{Approver: approver, PublicKey: publicKey}, nil
+	}}
+	body := RotateKeyRequest{Approver: "alice", PublicKey: "a2V5", Signature: "c2ln"}
+
+	rec := post(svc, auth.Client{Name: "acme"}, "/approvers/keys/rotate", body)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	svc.rotateApproverKey = func(approver string, publicKey string, signature string) (*models.ApproverKey, error) {
+		return nil, service.ErrInvalidRotationSignature
+	}
+	rec = post(svc, auth.Client{Name: "acme"}, "/approvers/keys/rotate", body)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	svc.rotateApproverKey = func(approver string, publicKey string, signature string) (*models.ApproverKey, error) {
+		return nil, service.ErrUnknownApprover
+	}
+	rec = post(svc, auth.Client{Name: "acme"}, "/approvers/keys/rotate", body)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = post(svc, auth.Client{Name: "acme"}, "/approvers/keys/rotate", RotateKeyRequest{Approver: "alice", PublicKey: "a2V5"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
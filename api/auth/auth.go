@@ -0,0 +1,94 @@
+// Package auth identifies API callers by the key they send as a bearer token.
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"mint-redeem-workflow/config"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const clientKey = "auth.client"
+
+// Client is the caller of a request.
+type Client struct {
+	Name     string
+	Operator bool
+}
+
+// Middleware looks up the caller by the `Authorization: Bearer <key>` header
+// and stores it for ClientFrom. Requests without a known key get 401.
+func Middleware(clients []config.APIClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		key := strings.TrimPrefix(header, "Bearer ")
+		if key == header || key == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing API key"})
+			return
+		}
+
+		client, ok := lookup(clients, key)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unknown API key"})
+			return
+		}
+
+		SetClient(c, client)
+		c.Next()
+	}
+}
+
+// RequireOperator rejects callers that aren't operators with 403.
+func RequireOperator(c *gin.Context) {
+	if !ClientFrom(c).Operator {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "only operators can do this"})
+		return
+	}
+
+	c.Next()
+}
+
+// SetClient records the caller of the request.
+func SetClient(c *gin.Context, client Client) {
+	c.Set(clientKey, client)
+}
+
+// ClientFrom returns the caller of the request, or an empty Client when it
+// wasn't authenticated.
+func ClientFrom(c *gin.Context) Client {
+	client, _ := c.Get(clientKey)
+	caller, _ := client.(Client)
+	return caller
+}
+
+// Scope returns the client whose data the caller may see: its own name, or
+// empty for operators, who see every client's.
+func Scope(c *gin.Context) string {
+	client := ClientFrom(c)
+	if client.Operator {
+		return ""
+	}
+
+	return client.Name
+}
+
+// HashKey returns the hex SHA-256 of an API key, as the config stores it.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func lookup(clients []config.APIClient, key string) (Client, bool) {
+	hash := []byte(HashKey(key))
+	for _, client := range clients {
+		if subtle.ConstantTimeCompare(hash, []byte(client.KeyHash)) == 1 {
+			return Client{Name: client.Name, Operator: client.Operator}, true
+		}
+	}
+
+	return Client{}, false
+}
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"mint-redeem-workflow/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var clients = []config.APIClient{
+	{Name: "acme", KeyHash: HashKey("acme-key")},
+	{Name: "operator", KeyHash: HashKey("operator-key"), Operator: true},
+}
+
+func serve(authorization string, handlers ...gin.HandlerFunc) *httptest.ResponseRecorder {
+	r := gin.New()
+	r.Use(Middleware(clients))
+	r.GET("/", append(handlers, func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"client": ClientFrom(c).Name, "scope": Scope(c)})
+	})...)
+
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestMiddleware_KnownKeyIdentifiesClient(t *testing.T) {
+	t.Parallel()
+
+	rec := serve("Bearer acme-key")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"client":"acme","scope":"acme"}`, rec.Body.String())
+
+	rec = serve("Bearer operator-key")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"client":"operator","scope":""}`, rec.Body.String())
+}
+
+func TestMiddleware_MissingOrUnknownKeyReturns401(t *testing.T) {
+	t.Parallel()
+
+	for _, authorization := range []string{"", "acme-key", "Bearer ", "Bearer other-key", "Basic YWNtZTprZXk="} {
+		rec := serve(authorization)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, authorization)
+	}
+}
+
+func TestRequireOperator_RejectsOtherClientsWith403(t *testing.T) {
+	t.Parallel()
+
+	rec := serve("Bearer acme-key", RequireOperator)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve("Bearer operator-key", RequireOperator)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
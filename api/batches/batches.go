@@ -0,0 +1,167 @@
+package batches
+
+import (
+	"errors"
+	"fmt"
+	"mint-redeem-workflow/api/auth"
+	"mint-redeem-workflow/api/requests"
+	"mint-redeem-workflow/config"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/operations"
+	"mint-redeem-workflow/service"
+	"mint-redeem-workflow/worker/workflows"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Service is what the batch handlers need from the service layer.
+type Service interface {
+	ProcessBatch(batch *models.Batch, batchRequests []models.Request, workflowParam workflows.BatchInput) error
+	GetBatch(batchID string) (*models.Batch, error)
+}
+
+type Handler struct {
+	service Service
+	cfg     *config.ServiceConfig
+}
+
+func NewHandler(service Service, cfg *config.ServiceConfig) *Handler {
+	return &Handler{service: service, cfg: cfg}
+}
+
+func (h *Handler) RegisterRoutes(r gin.IRouter) {
+	r.POST("/batches", h.HandleCreateBatch)
+	r.GET("/batches/:id", h.HandleGetBatch)
+}
+
+type BatchItemRequest struct {
+	Type      string  `json:"type"`
+	Amount    float64 `json:"amount"`
+	Recipient string  `json:"recipient"`
+	Token     string  `json:"token"`
+	Chain     string  `json:"chain"`
+}
+
+type BatchRequest struct {
+	Items []BatchItemRequest `json:"items" binding:"required"`
+}
+
+type ItemError struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+func (h *Handler) HandleCreateBatch(c *gin.Context) {
+	var req BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	if len(req.Items) == 0 || len(req.Items) > h.cfg.MaxBatchSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("a batch must have between 1 and %d items", h.cfg.MaxBatchSize)})
+		return
+	}
+
+	submitter := auth.ClientFrom(c).Name
+	var itemErrors []ItemError
+	requestRows := make([]models.Request, 0, len(req.Items))
+	input := workflows.BatchInput{Concurrency: h.cfg.BatchConcurrency}
+	for i, item := range req.Items {
+		asset, err := validateItem(h.cfg, item)
+		if err != nil {
+			itemErrors = append(itemErrors, ItemError{Index: i, Error: err.Error()})
+			continue
+		}
+
+		requestRows = append(requestRows, models.Request{
+			Type:      item.Type,
+			Amount:    item.Amount,
+			Recipient: item.Recipient,
+			Token:     asset.Token,
+			Chain:     asset.Chain,
+			Submitter: submitter,
+		})
+
+		requiredApprovals := h.cfg.RequiredApprovals(asset.Token, item.Amount)
+		approvalTimeout := h.cfg.ApprovalTimeout
+		if requiredApprovals == 0 {
+			approvalTimeout = 0
+		}
+
+		input.Items = append(input.Items, workflows.BatchItem{Request: &workflows.RequestInput{
+			Type:              item.Type,
+			Amount:            item.Amount,
+			Recipient:         item.Recipient,
+			Token:             asset.Token,
+			Chain:             asset.Chain,
+			Submitter:         submitter,
+			RequiredApprovals: requiredApprovals,
+			ApprovalTimeout:   approvalTimeout,
+		}})
+	}
+
+	if len(itemErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch items", "items": itemErrors})
+		return
+	}
+
+	batch := models.Batch{Submitter: submitter}
+	if err := h.service.ProcessBatch(&batch, requestRows, input); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	requestIDs := make([]string, 0, len(requestRows))
+	for _, request := range requestRows {
+		requestIDs = append(requestIDs, request.ID.String())
+	}
+
+	c.JSON(http.StatusOK, gin.H{"id": batch.ID.String(), "status": "workflow started", "request_ids": requestIDs})
+}
+
+func validateItem(cfg *config.ServiceConfig, item BatchItemRequest) (*config.Asset, error) {
+	op, err := operations.Lookup(item.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	return op.ValidateRequest(cfg, operations.Params{
+		Amount:    item.Amount,
+		Recipient: item.Recipient,
+		Token:     item.Token,
+		Chain:     item.Chain,
+	})
+}
+
+func (h *Handler) HandleGetBatch(c *gin.Context) {
+	batch, err := h.service.GetBatch(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrBatchNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	statusCounts := map[string]int{}
+	items := make([]requests.RequestResponse, 0, len(batch.Requests))
+	for _, request := range batch.Requests {
+		statusCounts[request.Status]++
+		items = append(items, requests.NewRequestResponse(request))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":         batch.ID.String(),
+		"status":     batch.Status,
+		"submitter":  batch.Submitter,
+		"created_at": batch.CreatedAt,
+		"totals": gin.H{
+			"items":     len(batch.Requests),
+			"by_status": statusCounts,
+		},
+		"items": items,
+	})
+}
@@ -0,0 +1,157 @@
+package batches
+
+import (
+	"bytes"
+	"encoding/json"
+	"mint-redeem-workflow/api/auth"
+	"mint-redeem-workflow/config"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/service"
+	"mint-redeem-workflow/worker/workflows"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeService struct {
+	processBatch func(batch *models.Batch, requests []models.Request, workflowParam workflows.BatchInput) error
+	getBatch     func(batchID string) (*models.Batch, error)
+}
+
+func (f *fakeService) ProcessBatch(batch *models.Batch, batchRequests []models.Request, workflowParam workflows.BatchInput) error {
+	return f.processBatch(batch, batchRequests, workflowParam)
+}
+
+func (f *fakeService) GetBatch(batchID string) (*models.Batch, error) {
+	return f.getBatch(batchID)
+}
+
+func serve(svc Service, req *http.Request) *httptest.ResponseRecorder {
+	r := gin.New()
+	r.Use(func(c *gin.Context) { auth.SetClient(c, auth.Client{Name: "alice"}) })
+	cfg, _ := config.NewServiceConfig()
+	NewHandler(svc, cfg).RegisterRoutes(r)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func postBatch(svc Service, body BatchRequest) *httptest.ResponseRecorder {
+	reqBody, _ := json.Marshal(body)
+	req, _ := http.NewRequest(http.MethodPost, "/batches", bytes.NewBuffer(reqBody))
+
+	return serve(svc, req)
+}
+
+func TestHandleCreateBatch_SuccessReturns200(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	var processedInput workflows.BatchInput
+	svc.processBatch = func(batch *models.Batch, requests []models.Request, workflowParam workflows.BatchInput) error {
+		processedInput = workflowParam
+		return nil
+	}
+
+	rec := postBatch(svc, BatchRequest{Items: []BatchItemRequest{
+		{Type: "mint", Amount: 10.5, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum"},
+		{Type: "redeem", Amount: 20000, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "polygon"},
+	}})
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, processedInput.Items, 2)
+	assert.Equal(t, "mint", processedInput.Items[0].Request.Type)
+	assert.Equal(t, 0, processedInput.Items[0].Request.RequiredApprovals)
+	assert.Equal(t, "redeem", processedInput.Items[1].Request.Type)
+	assert.Equal(t, 1, processedInput.Items[1].Request.RequiredApprovals)
+	assert.Equal(t, "alice", processedInput.Items[1].Request.Submitter)
+}
+
+func TestHandleCreateBatch_InvalidItemsReturn400WithIndexes(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	svc.processBatch = func(batch *models.Batch, requests []models.Request, workflowParam workflows.BatchInput) error {
+		t.Fatal("batch should not be processed")
+		return nil
+	}
+
+	rec := postBatch(svc, BatchRequest{Items: []BatchItemRequest{
+		{Type: "mint", Amount: 10.5, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum"},
+		{Type: "burn", Amount: 10.5, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum"},
+		{Type: "mint", Amount: 10.5, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "dogechain"},
+	}})
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var resp struct {
+		Items []ItemError `json:"items"`
+	}
+	err := json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, []ItemError{
+		{Index: 1, Error: "type must be mint or redeem"},
+		{Index: 2, Error: "unsupported token USDC on chain dogechain"},
+	}, resp.Items)
+}
+
+func TestHandleCreateBatch_TooManyItemsReturns400(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	items := make([]BatchItemRequest, 101)
+	for i := range items {
+		items[i] = BatchItemRequest{Type: "mint", Amount: 1, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum"}
+	}
+
+	rec := postBatch(svc, BatchRequest{Items: items})
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandleGetBatch_ReturnsItemsAndTotals(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	batchID := uuid.New()
+	svc.getBatch = func(id string) (*models.Batch, error) {
+		return &models.Batch{ID: batchID, Status: "partially_failed", Requests: []models.Request{
+			{ID: uuid.New(), Type: "mint", Status: "completed"},
+			{ID: uuid.New(), Type: "mint", Status: "completed"},
+			{ID: uuid.New(), Type: "redeem", Status: "failed"},
+		}}, nil
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/batches/"+batchID.String(), nil)
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp struct {
+		Status string `json:"status"`
+		Totals struct {
+			Items    int            `json:"items"`
+			ByStatus map[string]int `json:"by_status"`
+		} `json:"totals"`
+		Items []map[string]interface{} `json:"items"`
+	}
+	err := json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "partially_failed", resp.Status)
+	assert.Equal(t, 3, resp.Totals.Items)
+	assert.Equal(t, map[string]int{"completed": 2, "failed": 1}, resp.Totals.ByStatus)
+	assert.Len(t, resp.Items, 3)
+}
+
+func TestHandleGetBatch_NotFoundReturns404(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	svc.getBatch = func(id string) (*models.Batch, error) {
+		return nil, service.ErrBatchNotFound
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/batches/missing", nil)
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
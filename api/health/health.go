@@ -0,0 +1,41 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// checkTimeout bounds how long GET /ready waits on the check.
+const checkTimeout = time.Second * 5
+
+// Check returns an error that says what isn't ready yet.
+type Check func(ctx context.Context) error
+
+type Handler struct {
+	ready Check
+}
+
+func NewHandler(ready Check) *Handler {
+	return &Handler{ready: ready}
+}
+
+func (h *Handler) RegisterRoutes(r gin.IRouter) {
+	r.GET("/ready", h.HandleReady)
+}
+
+// HandleReady answers 200 once requests submitted to the API will be picked
+// up, and 503 with the reason until then.
+func (h *Handler) HandleReady(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), checkTimeout)
+	defer cancel()
+
+	if err := h.ready(ctx); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "not ready", "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ready"})
+}
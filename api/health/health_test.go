@@ -0,0 +1,40 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(ready Check, req *http.Request) *httptest.ResponseRecorder {
+	r := gin.New()
+	NewHandler(ready).RegisterRoutes(r)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestHandleReady_CheckPasses(t *testing.T) {
+	t.Parallel()
+	req, _ := http.NewRequest(http.MethodGet, "/ready", nil)
+	rec := serve(func(ctx context.Context) error { return nil }, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ready"}`, rec.Body.String())
+}
+
+func TestHandleReady_CheckFailsReturns503WithReason(t *testing.T) {
+	t.Parallel()
+	req, _ := http.NewRequest(http.MethodGet, "/ready", nil)
+	rec := serve(func(ctx context.Context) error { return errors.New("no workers are polling") }, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.JSONEq(t, `{"status":"not ready","error":"no workers are polling"}`, rec.Body.String())
+}
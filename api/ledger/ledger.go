@@ -0,0 +1,128 @@
+package ledger
+
+import (
+	"mint-redeem-workflow/ledger"
+	"mint-redeem-workflow/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Service is what the ledger handlers need from the service layer.
+type Service interface {
+	GetBalances(account string) ([]ledger.Balance, error)
+	ListJournalEntries(filter ledger.EntryFilter) ([]models.JournalEntry, error)
+}
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) RegisterRoutes(r gin.IRouter) {
+	r.GET("/ledger/balances", h.HandleGetBalances)
+	r.GET("/ledger/entries", h.HandleListEntries)
+}
+
+type BalanceResponse struct {
+	Account  string `json:"account"`
+	Currency string `json:"currency"`
+	Debits   string `json:"debits"`
+	Credits  string `json:"credits"`
+	Balance  string `json:"balance"`
+}
+
+type LineResponse struct {
+	Account  string `json:"account"`
+	Currency string `json:"currency"`
+	Debit    string `json:"debit"`
+	Credit   string `json:"credit"`
+}
+
+type EntryResponse struct {
+	ID         string         `json:"id"`
+	RequestID  string         `json:"request_id"`
+	Kind       string         `json:"kind"`
+	ReversesID string         `json:"reverses_id,omitempty"`
+	Memo       string         `json:"memo"`
+	CreatedAt  time.Time      `json:"created_at"`
+	Lines      []LineResponse `json:"lines"`
+}
+
+func newEntryResponse(entry models.JournalEntry) EntryResponse {
+	resp := EntryResponse{
+		ID:        entry.ID.String(),
+		RequestID: entry.RequestID.String(),
+		Kind:      entry.Kind,
+		Memo:      entry.Memo,
+		CreatedAt: entry.CreatedAt,
+		Lines:     make([]LineResponse, 0, len(entry.Lines)),
+	}
+	if entry.ReversesID != nil {
+		resp.ReversesID = entry.ReversesID.String()
+	}
+	for _, line := range entry.Lines {
+		resp.Lines = append(resp.Lines, LineResponse{
+			Account:  line.Account,
+			Currency: line.Currency,
+			Debit:    ledger.FormatUnits(line.Debit),
+			Credit:   ledger.FormatUnits(line.Credit),
+		})
+	}
+
+	return resp
+}
+
+func (h *Handler) HandleGetBalances(c *gin.Context) {
+	balances, err := h.service.GetBalances(c.Query("account"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := make([]BalanceResponse, 0, len(balances))
+	for _, balance := range balances {
+		resp = append(resp, BalanceResponse{
+			Account:  balance.Account,
+			Currency: balance.Currency,
+			Debits:   ledger.FormatUnits(balance.Debits),
+			Credits:  ledger.FormatUnits(balance.Credits),
+			Balance:  ledger.FormatUnits(balance.Balance),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"balances": resp})
+}
+
+func (h *Handler) HandleListEntries(c *gin.Context) {
+	filter := ledger.EntryFilter{
+		RequestID: c.Query("request_id"),
+		Account:   c.Query("account"),
+	}
+	if limit := c.Query("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a number"})
+			return
+		}
+		filter.Limit = parsed
+	}
+
+	entries, err := h.service.ListJournalEntries(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := make([]EntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		resp = append(resp, newEntryResponse(entry))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": resp})
+}
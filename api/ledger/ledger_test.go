@@ -0,0 +1,86 @@
+package ledger
+
+import (
+	"encoding/json"
+	"mint-redeem-workflow/ledger"
+	"mint-redeem-workflow/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeService struct {
+	getBalances        func(account string) ([]ledger.Balance, error)
+	listJournalEntries func(filter ledger.EntryFilter) ([]models.JournalEntry, error)
+}
+
+func (f *fakeService) GetBalances(account string) ([]ledger.Balance, error) {
+	return f.getBalances(account)
+}
+
+func (f *fakeService) ListJournalEntries(filter ledger.EntryFilter) ([]models.JournalEntry, error) {
+	return f.listJournalEntries(filter)
+}
+
+func serve(svc Service, req *http.Request) *httptest.ResponseRecorder {
+	r := gin.New()
+	NewHandler(svc).RegisterRoutes(r)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestHandleGetBalances_FormatsUnits(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{getBalances: func(account string) ([]ledger.Balance, error) {
+		assert.Equal(t, "fiat_reserve", account)
+		return []ledger.Balance{{Account: "fiat_reserve", Currency: "USD", Debits: 100_500_000, Credits: 500_000, Balance: 100_000_000}}, nil
+	}}
+
+	req, _ := http.NewRequest(http.MethodGet, "/ledger/balances?account=fiat_reserve", nil)
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp struct {
+		Balances []BalanceResponse `json:"balances"`
+	}
+	err := json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, []BalanceResponse{{Account: "fiat_reserve", Currency: "USD", Debits: "100.500000", Credits: "0.500000", Balance: "100.000000"}}, resp.Balances)
+}
+
+func TestHandleListEntries_PassesFiltersAndReturnsLines(t *testing.T) {
+	t.Parallel()
+	requestID := uuid.New()
+	svc := &fakeService{listJournalEntries: func(filter ledger.EntryFilter) ([]models.JournalEntry, error) {
+		assert.Equal(t, ledger.EntryFilter{RequestID: requestID.String(), Account: "fees", Limit: 5}, filter)
+		return []models.JournalEntry{{
+			ID:        uuid.New(),
+			RequestID: requestID,
+			Kind:      "posting",
+			Lines: []models.JournalLine{
+				{Account: "fiat_reserve", Currency: "USD", Debit: 1_000_000},
+				{Account: "customer:0xnotdeadbeef", Currency: "USD", Credit: 1_000_000},
+			},
+		}}, nil
+	}}
+
+	req, _ := http.NewRequest(http.MethodGet, "/ledger/entries?request_id="+requestID.String()+"&account=fees&limit=5", nil)
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp struct {
+		Entries []EntryResponse `json:"entries"`
+	}
+	err := json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Entries, 1)
+	assert.Equal(t, "1.000000", resp.Entries[0].Lines[0].Debit)
+	assert.Equal(t, "0.000000", resp.Entries[0].Lines[0].Credit)
+}
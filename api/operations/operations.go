@@ -0,0 +1,117 @@
+// Package operations serves the submit endpoint of every registered
+// operation, like POST /mint and POST /redeem.
+package operations
+
+import (
+	"mint-redeem-workflow/api/auth"
+	"mint-redeem-workflow/config"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/operations"
+	"mint-redeem-workflow/worker/workflows"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Service is what the submit handlers need from the service layer.
+type Service interface {
+	ProcessRequest(request *models.Request, workflowParam workflows.RequestInput) error
+}
+
+type Handler struct {
+	service Service
+	cfg     *config.ServiceConfig
+}
+
+func NewHandler(service Service, cfg *config.ServiceConfig) *Handler {
+	return &Handler{service: service, cfg: cfg}
+}
+
+func (h *Handler) RegisterRoutes(r gin.IRouter) {
+	for _, opType := range operations.Types() {
+		opType := opType
+		r.POST("/"+opType, func(c *gin.Context) {
+			h.HandleCreateRequest(c, opType)
+		})
+	}
+}
+
+type CreateRequest struct {
+	Amount    float64 `json:"amount" binding:"required"`
+	Recipient string  `json:"recipient" binding:"required"`
+	Token     string  `json:"token" binding:"required"`
+	Chain     string  `json:"chain" binding:"required"`
+
+	// ExecuteAt optionally delays the request until that time.
+	ExecuteAt *time.Time `json:"execute_at"`
+}
+
+// HandleCreateRequest submits a request of the operation registered as
+// opType. The caller is recorded as the request's submitter.
+func (h *Handler) HandleCreateRequest(c *gin.Context, opType string) {
+	op, err := operations.Lookup(opType)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req CreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	asset, err := op.ValidateRequest(h.cfg, operations.Params{
+		Amount:    req.Amount,
+		Recipient: req.Recipient,
+		Token:     req.Token,
+		Chain:     req.Chain,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.ExecuteAt != nil {
+		now := time.Now()
+		if err := h.cfg.ValidateExecuteAt(*req.ExecuteAt, now, now); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	request := models.Request{
+		ID:        uuid.New(),
+		Type:      op.Type,
+		Amount:    req.Amount,
+		Recipient: req.Recipient,
+		Token:     asset.Token,
+		Chain:     asset.Chain,
+		Submitter: auth.ClientFrom(c).Name,
+		ExecuteAt: req.ExecuteAt,
+	}
+
+	workflowInput := workflows.RequestInput{
+		Type:      op.Type,
+		Amount:    req.Amount,
+		Recipient: req.Recipient,
+		Token:     asset.Token,
+		Chain:     asset.Chain,
+		RequestID: request.ID.String(),
+		Submitter: auth.ClientFrom(c).Name,
+	}
+
+	if required := h.cfg.RequiredApprovals(asset.Token, req.Amount); required > 0 {
+		workflowInput.RequiredApprovals = required
+		workflowInput.ApprovalTimeout = h.cfg.ApprovalTimeout
+	}
+
+	if err := h.service.ProcessRequest(&request, workflowInput); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"id": request.ID.String(), "status": "workflow started"})
+}
@@ -0,0 +1,186 @@
+package operations
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mint-redeem-workflow/api/auth"
+	"mint-redeem-workflow/config"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/worker/workflows"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeService struct {
+	processRequest func(request *models.Request, workflowParam workflows.RequestInput) error
+}
+
+func (f *fakeService) ProcessRequest(request *models.Request, workflowParam workflows.RequestInput) error {
+	return f.processRequest(request, workflowParam)
+}
+
+func serve(svc Service, req *http.Request) *httptest.ResponseRecorder {
+	r := gin.New()
+	r.Use(func(c *gin.Context) { auth.SetClient(c, auth.Client{Name: "acme"}) })
+	cfg, _ := config.NewServiceConfig()
+	NewHandler(svc, cfg).RegisterRoutes(r)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func mockProcessRequest(request *models.Request, workflowParam workflows.RequestInput) error {
+	return nil
+}
+
+func mockProcessRequestError(request *models.Request, workflowParam workflows.RequestInput) error {
+	return errors.New("mock process request error")
+}
+
+func TestHandleCreateRequest_SuccessReturns200(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	svc.processRequest = func(request *models.Request, workflowParam workflows.RequestInput) error {
+		assert.Equal(t, "acme", request.Submitter)
+		assert.Equal(t, "acme", workflowParam.Submitter)
+		return nil
+	}
+
+	reqBody, _ := json.Marshal(CreateRequest{Amount: 10.50, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum"})
+	req, _ := http.NewRequest(http.MethodPost, "/mint", bytes.NewBuffer(reqBody))
+
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp map[string]string
+	err := json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "workflow started", resp["status"])
+}
+
+func TestHandleCreateRequest_MissingParamsReturns400(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	reqBody, _ := json.Marshal(CreateRequest{Recipient: "0xnotdeadbeef"})
+	req, _ := http.NewRequest(http.MethodPost, "/mint", bytes.NewBuffer(reqBody))
+
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var resp map[string]string
+	err := json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "Invalid request payload", resp["error"])
+}
+
+func TestHandleCreateRequest_ProcessRequestErrorReturns500(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	svc.processRequest = mockProcessRequestError
+
+	reqBody, _ := json.Marshal(CreateRequest{Amount: 10.50, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum"})
+	req, _ := http.NewRequest(http.MethodPost, "/mint", bytes.NewBuffer(reqBody))
+
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	var resp map[string]string
+	err := json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "mock process request error", resp["error"])
+}
+
+func TestHandleCreateRequest_UnsupportedAssetReturns400(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	reqBody, _ := json.Marshal(CreateRequest{Amount: 10.50, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "dogechain"})
+	req, _ := http.NewRequest(http.MethodPost, "/mint", bytes.NewBuffer(reqBody))
+
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var resp map[string]string
+	err := json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "unsupported token USDC on chain dogechain", resp["error"])
+}
+
+func TestHandleCreateRequest_AmountExceedsPrecisionReturns400(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	reqBody, _ := json.Marshal(CreateRequest{Amount: 10.505, Recipient: "0xnotdeadbeef", Token: "SBC", Chain: "base"})
+	req, _ := http.NewRequest(http.MethodPost, "/mint", bytes.NewBuffer(reqBody))
+
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var resp map[string]string
+	err := json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "amount 10.505 exceeds 2 decimal places for SBC on base", resp["error"])
+}
+
+func TestHandleCreateRequest_PastExecuteAtReturns400(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	executeAt := time.Now().Add(-time.Hour)
+	reqBody, _ := json.Marshal(CreateRequest{Amount: 10.50, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum", ExecuteAt: &executeAt})
+	req, _ := http.NewRequest(http.MethodPost, "/mint", bytes.NewBuffer(reqBody))
+
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var resp map[string]string
+	err := json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "execute_at must be in the future", resp["error"])
+}
+
+func TestHandleCreateRequest_RedeemStartsRequestOfThatType(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	var processed models.Request
+	var processedInput workflows.RequestInput
+	svc.processRequest = func(request *models.Request, workflowParam workflows.RequestInput) error {
+		processed, processedInput = *request, workflowParam
+		return nil
+	}
+
+	reqBody, _ := json.Marshal(CreateRequest{Amount: 10.50, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum"})
+	req, _ := http.NewRequest(http.MethodPost, "/redeem", bytes.NewBuffer(reqBody))
+
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "redeem", processed.Type)
+	assert.Equal(t, "redeem", processedInput.Type)
+	assert.Equal(t, processed.ID.String(), processedInput.RequestID)
+}
+
+func TestHandleCreateRequest_UnknownOperationReturns404(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	reqBody, _ := json.Marshal(CreateRequest{Amount: 10.50, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum"})
+	req, _ := http.NewRequest(http.MethodPost, "/burn", bytes.NewBuffer(reqBody))
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = req
+
+	cfg, _ := config.NewServiceConfig()
+	NewHandler(svc, cfg).HandleCreateRequest(c, "burn")
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	var resp map[string]string
+	err := json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "type must be mint or redeem", resp["error"])
+}
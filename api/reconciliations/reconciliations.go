@@ -0,0 +1,226 @@
+package reconciliations
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/service"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Service is what the reconciliation handlers need from the service layer.
+type Service interface {
+	StartReconciliation(report *models.ReconciliationReport) error
+	GetReconciliation(reportID string) (*models.ReconciliationReport, error)
+	ListReconciliations(limit int) ([]models.ReconciliationReport, error)
+}
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) RegisterRoutes(r gin.IRouter) {
+	r.POST("/reconciliations", h.HandleStartReconciliation)
+	r.GET("/reconciliations", h.HandleListReconciliations)
+	r.GET("/reconciliations/:id", h.HandleGetReconciliation)
+	r.GET("/reconciliations/:id/export", h.HandleExportReconciliation)
+}
+
+const maxRange = time.Hour * 24 * 31
+
+// StartReconciliationRequest is the range to reconcile. Leaving both times
+// out reconciles the previous UTC day.
+type StartReconciliationRequest struct {
+	From *time.Time `json:"from"`
+	To   *time.Time `json:"to"`
+}
+
+type ReportResponse struct {
+	ID              string         `json:"id"`
+	From            time.Time      `json:"from"`
+	To              time.Time      `json:"to"`
+	Status          string         `json:"status"`
+	Error           string         `json:"error,omitempty"`
+	Matched         int            `json:"matched"`
+	MissingLocally  int            `json:"missing_locally"`
+	MissingRemotely int            `json:"missing_remotely"`
+	AmountMismatch  int            `json:"amount_mismatch"`
+	CreatedAt       time.Time      `json:"created_at"`
+	CompletedAt     *time.Time     `json:"completed_at,omitempty"`
+	Items           []ItemResponse `json:"items,omitempty"`
+}
+
+type ItemResponse struct {
+	Kind           string   `json:"kind"`
+	Classification string   `json:"classification"`
+	RequestID      string   `json:"request_id,omitempty"`
+	OrderID        string   `json:"order_id,omitempty"`
+	TxHash         string   `json:"tx_hash,omitempty"`
+	LocalAmount    *float64 `json:"local_amount,omitempty"`
+	RemoteAmount   *float64 `json:"remote_amount,omitempty"`
+	Detail         string   `json:"detail,omitempty"`
+}
+
+func newReportResponse(report models.ReconciliationReport) ReportResponse {
+	resp := ReportResponse{
+		ID:              report.ID.String(),
+		From:            report.From,
+		To:              report.To,
+		Status:          report.Status,
+		Error:           report.Error,
+		Matched:         report.Matched,
+		MissingLocally:  report.MissingLocally,
+		MissingRemotely: report.MissingRemotely,
+		AmountMismatch:  report.AmountMismatch,
+		CreatedAt:       report.CreatedAt,
+		CompletedAt:     report.CompletedAt,
+	}
+	for _, item := range report.Items {
+		itemResp := ItemResponse{
+			Kind:           item.Kind,
+			Classification: item.Classification,
+			OrderID:        item.OrderID,
+			TxHash:         item.TxHash,
+			LocalAmount:    item.LocalAmount,
+			RemoteAmount:   item.RemoteAmount,
+			Detail:         item.Detail,
+		}
+		if item.RequestID != nil {
+			itemResp.RequestID = item.RequestID.String()
+		}
+		resp.Items = append(resp.Items, itemResp)
+	}
+
+	return resp
+}
+
+func (h *Handler) HandleStartReconciliation(c *gin.Context) {
+	var req StartReconciliationRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+			return
+		}
+	}
+
+	var from, to time.Time
+	switch {
+	case req.From == nil && req.To == nil:
+		to = time.Now().UTC().Truncate(time.Hour * 24)
+		from = to.Add(-time.Hour * 24)
+	case req.From == nil || req.To == nil:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to must be sent together"})
+		return
+	default:
+		from, to = *req.From, *req.To
+	}
+
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+		return
+	}
+	if to.Sub(from) > maxRange {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("range can't be longer than %d days", int(maxRange.Hours()/24))})
+		return
+	}
+
+	report := models.ReconciliationReport{From: from, To: to}
+	if err := h.service.StartReconciliation(&report); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newReportResponse(report))
+}
+
+func (h *Handler) HandleListReconciliations(c *gin.Context) {
+	limit := 0
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a number"})
+			return
+		}
+		limit = parsed
+	}
+
+	reports, err := h.service.ListReconciliations(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := make([]ReportResponse, 0, len(reports))
+	for _, report := range reports {
+		resp = append(resp, newReportResponse(report))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reconciliations": resp})
+}
+
+func (h *Handler) HandleGetReconciliation(c *gin.Context) {
+	report, ok := h.getReport(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, newReportResponse(*report))
+}
+
+// HandleExportReconciliation writes the report's items as a CSV file.
+func (h *Handler) HandleExportReconciliation(c *gin.Context) {
+	report, ok := h.getReport(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=reconciliation-%s.csv", report.ID))
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{"kind", "classification", "request_id", "order_id", "tx_hash", "local_amount", "remote_amount", "detail"})
+	for _, item := range newReportResponse(*report).Items {
+		writer.Write([]string{
+			item.Kind,
+			item.Classification,
+			item.RequestID,
+			item.OrderID,
+			item.TxHash,
+			formatAmount(item.LocalAmount),
+			formatAmount(item.RemoteAmount),
+			item.Detail,
+		})
+	}
+	writer.Flush()
+}
+
+func (h *Handler) getReport(c *gin.Context) (*models.ReconciliationReport, bool) {
+	report, err := h.service.GetReconciliation(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrReconciliationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	return report, true
+}
+
+func formatAmount(amount *float64) string {
+	if amount == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*amount, 'f', -1, 64)
+}
@@ -0,0 +1,111 @@
+package reconciliations
+
+import (
+	"bytes"
+	"encoding/json"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeService struct {
+	startReconciliation func(report *models.ReconciliationReport) error
+	getReconciliation   func(reportID string) (*models.ReconciliationReport, error)
+	listReconciliations func(limit int) ([]models.ReconciliationReport, error)
+}
+
+func (f *fakeService) StartReconciliation(report *models.ReconciliationReport) error {
+	return f.startReconciliation(report)
+}
+
+func (f *fakeService) GetReconciliation(reportID string) (*models.ReconciliationReport, error) {
+	return f.getReconciliation(reportID)
+}
+
+func (f *fakeService) ListReconciliations(limit int) ([]models.ReconciliationReport, error) {
+	return f.listReconciliations(limit)
+}
+
+func serve(svc Service, req *http.Request) *httptest.ResponseRecorder {
+	r := gin.New()
+	NewHandler(svc).RegisterRoutes(r)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestHandleStartReconciliation_NoRangeReconcilesPreviousDay(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	var started models.ReconciliationReport
+	svc.startReconciliation = func(report *models.ReconciliationReport) error {
+		report.ID = uuid.New()
+		started = *report
+		return nil
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "/reconciliations", nil)
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	today := time.Now().UTC().Truncate(time.Hour * 24)
+	assert.True(t, started.To.Equal(today))
+	assert.True(t, started.From.Equal(today.Add(-time.Hour*24)))
+}
+
+func TestHandleStartReconciliation_InvalidRangeReturns400(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	from := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	for _, to := range []time.Time{from, from.Add(-time.Hour), from.Add(time.Hour * 24 * 40)} {
+		reqBody, _ := json.Marshal(StartReconciliationRequest{From: &from, To: &to})
+		req, _ := http.NewRequest(http.MethodPost, "/reconciliations", bytes.NewBuffer(reqBody))
+		rec := serve(svc, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, to.String())
+	}
+}
+
+func TestHandleExportReconciliation_WritesCSV(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	requestID := uuid.New()
+	localAmount, remoteAmount := 10.5, 10.25
+	svc.getReconciliation = func(reportID string) (*models.ReconciliationReport, error) {
+		return &models.ReconciliationReport{ID: uuid.New(), Status: "completed", Items: []models.ReconciliationItem{
+			{Kind: "order", Classification: "amount_mismatch", RequestID: &requestID, OrderID: "order-1", LocalAmount: &localAmount, RemoteAmount: &remoteAmount, Detail: "request amount 10.5, Brale amount 10.25"},
+			{Kind: "transfer", Classification: "missing_locally", OrderID: "order-2", TxHash: "0xabc", RemoteAmount: &remoteAmount},
+		}}, nil
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/reconciliations/report-id/export", nil)
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "kind,classification,request_id,order_id,tx_hash,local_amount,remote_amount,detail\n"+
+		"order,amount_mismatch,"+requestID.String()+",order-1,,10.5,10.25,\"request amount 10.5, Brale amount 10.25\"\n"+
+		"transfer,missing_locally,,order-2,0xabc,,10.25,\n", rec.Body.String())
+}
+
+func TestHandleGetReconciliation_NotFoundReturns404(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	svc.getReconciliation = func(reportID string) (*models.ReconciliationReport, error) {
+		return nil, service.ErrReconciliationNotFound
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/reconciliations/missing", nil)
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
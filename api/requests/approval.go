@@ -0,0 +1,96 @@
+package requests
+
+import (
+	"errors"
+	"mint-redeem-workflow/api/auth"
+	"mint-redeem-workflow/service"
+	"mint-redeem-workflow/worker/workflows"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ApprovalRequest is a decision of the calling client's approver.
+type ApprovalRequest struct {
+	Reason    string `json:"reason"`
+	Signature string `json:"signature" binding:"required"`
+}
+
+func (h *Handler) HandleApproveRequest(c *gin.Context) {
+	h.handleApprovalDecision(c, true)
+}
+
+func (h *Handler) HandleRejectRequest(c *gin.Context) {
+	h.handleApprovalDecision(c, false)
+}
+
+func (h *Handler) handleApprovalDecision(c *gin.Context, approved bool) {
+	var req ApprovalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	signal := workflows.ApprovalSignal{
+		Approved:  approved,
+		Reason:    req.Reason,
+		Signature: req.Signature,
+	}
+
+	requestID := c.Param("id")
+	if err := h.service.SignalApproval(auth.ClientFrom(c).Name, requestID, signal); err != nil {
+		switch {
+		case errors.Is(err, service.ErrRequestNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrSubmitterCannotApprove), errors.Is(err, service.ErrUnknownApprover), errors.Is(err, service.ErrInvalidSignature):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrNotAwaitingApproval), errors.Is(err, service.ErrDuplicateApprover):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	status := "rejection sent"
+	if approved {
+		status = "approval sent"
+	}
+	c.JSON(http.StatusOK, gin.H{"id": requestID, "status": status})
+}
+
+func (h *Handler) HandleGetApprovals(c *gin.Context) {
+	requestID := c.Param("id")
+	state, trail, err := h.service.GetApprovals(auth.Scope(c), requestID)
+	if err != nil {
+		if errors.Is(err, service.ErrRequestNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	decisions := make([]gin.H, 0, len(trail))
+	for _, approval := range trail {
+		decisions = append(decisions, gin.H{
+			"approver":   approval.Approver,
+			"approved":   approval.Approved,
+			"reason":     approval.Reason,
+			"decided_at": approval.CreatedAt,
+		})
+	}
+
+	approvedBy := make([]string, 0, len(state.Approvals))
+	for _, approval := range state.Approvals {
+		approvedBy = append(approvedBy, approval.Approver)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":          requestID,
+		"required":    state.Required,
+		"approved_by": approvedBy,
+		"decision":    state.Decision,
+		"trail":       decisions,
+	})
+}
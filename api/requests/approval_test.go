@@ -0,0 +1,93 @@
+package requests
+
+import (
+	"bytes"
+	"encoding/json"
+	"mint-redeem-workflow/api/auth"
+	"mint-redeem-workflow/service"
+	"mint-redeem-workflow/worker/workflows"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func performApproval(svc Service, decision string, id string, body interface{}) *httptest.ResponseRecorder {
+	reqBody, _ := json.Marshal(body)
+	req, _ := http.NewRequest(http.MethodPost, "/requests/"+id+"/"+decision, bytes.NewBuffer(reqBody))
+
+	return serveAs(svc, auth.Client{Name: "globex"}, req)
+}
+
+func TestHandleApproveRequest_SuccessSendsApprovedSignal(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	var sent workflows.ApprovalSignal
+	svc.signalApproval = func(client string, requestID string, signal workflows.ApprovalSignal) error {
+		assert.Equal(t, "globex", client)
+		assert.Equal(t, "req-1", requestID)
+		sent = signal
+		return nil
+	}
+
+	rec := performApproval(svc, "approve", "req-1", ApprovalRequest{Signature: "c2ln"})
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp map[string]string
+	err := json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "approval sent", resp["status"])
+	assert.Equal(t, workflows.ApprovalSignal{Approved: true, Signature: "c2ln"}, sent)
+}
+
+func TestHandleRejectRequest_SuccessSendsRejectedSignal(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	var sent workflows.ApprovalSignal
+	svc.signalApproval = func(client string, requestID string, signal workflows.ApprovalSignal) error {
+		sent = signal
+		return nil
+	}
+
+	rec := performApproval(svc, "reject", "req-1", ApprovalRequest{Reason: "too large", Signature: "c2ln"})
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, workflows.ApprovalSignal{Approved: false, Reason: "too large", Signature: "c2ln"}, sent)
+}
+
+func TestHandleApproveRequest_MissingSignatureReturns400(t *testing.T) {
+	t.Parallel()
+	rec := performApproval(&fakeService{}, "approve", "req-1", map[string]string{"reason": "ok"})
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandleApproveRequest_ServiceErrorsMapToStatusCodes(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+
+	svc.signalApproval = func(client string, requestID string, signal workflows.ApprovalSignal) error {
+		return service.ErrRequestNotFound
+	}
+	rec := performApproval(svc, "approve", "req-1", ApprovalRequest{Signature: "c2ln"})
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	svc.signalApproval = func(client string, requestID string, signal workflows.ApprovalSignal) error {
+		return service.ErrNotAwaitingApproval
+	}
+	rec = performApproval(svc, "approve", "req-1", ApprovalRequest{Signature: "c2ln"})
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	svc.signalApproval = func(client string, requestID string, signal workflows.ApprovalSignal) error {
+		return service.ErrInvalidSignature
+	}
+	rec = performApproval(svc, "approve", "req-1", ApprovalRequest{Signature: "c2ln"})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	svc.signalApproval = func(client string, requestID string, signal workflows.ApprovalSignal) error {
+		return service.ErrSubmitterCannotApprove
+	}
+	rec = performApproval(svc, "approve", "req-1", ApprovalRequest{Signature: "c2ln"})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
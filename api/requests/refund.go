@@ -0,0 +1,40 @@
+package requests
+
+import (
+	"errors"
+	"mint-redeem-workflow/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RefundRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// HandleRefundRequest records a refund of a completed request that was paid
+// back outside the service, reversing its ledger posting. Only operators can
+// record refunds.
+func (h *Handler) HandleRefundRequest(c *gin.Context) {
+	var req RefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	requestID := c.Param("id")
+	reversal, err := h.service.RefundRequest(requestID, req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrRequestNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrNotRefundable), errors.Is(err, service.ErrRequestConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"id": requestID, "status": "refunded", "reversal_id": reversal.ID.String()})
+}
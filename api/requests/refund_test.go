@@ -0,0 +1,39 @@
+package requests
+
+import (
+	"bytes"
+	"encoding/json"
+	"mint-redeem-workflow/api/auth"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/service"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleRefundRequest_NotCompletedReturns409(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	svc.refundRequest = func(requestID string, reason string) (*models.JournalEntry, error) {
+		assert.Equal(t, "paid back by wire", reason)
+		return nil, service.ErrNotRefundable
+	}
+
+	reqBody, _ := json.Marshal(RefundRequest{Reason: "paid back by wire"})
+	req, _ := http.NewRequest(http.MethodPost, "/requests/request-id/refund", bytes.NewBuffer(reqBody))
+	rec := serveAs(svc, auth.Client{Name: "operator", Operator: true}, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestHandleRefundRequest_NonOperatorReturns403(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+
+	reqBody, _ := json.Marshal(RefundRequest{Reason: "paid back by wire"})
+	req, _ := http.NewRequest(http.MethodPost, "/requests/request-id/refund", bytes.NewBuffer(reqBody))
+	rec := serveAs(svc, auth.Client{Name: "acme"}, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
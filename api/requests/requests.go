@@ -0,0 +1,180 @@
+package requests
+
+import (
+	"errors"
+	"fmt"
+	"mint-redeem-workflow/api/auth"
+	"mint-redeem-workflow/events"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/repository"
+	"mint-redeem-workflow/service"
+	"mint-redeem-workflow/worker/workflows"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Service is what the request handlers need from the service layer.
+type Service interface {
+	GetRequest(client string, requestID string) (*models.Request, error)
+	ListRequests(filter repository.RequestFilter) ([]models.Request, error)
+	ListStatusTransitions(filter events.Filter) ([]events.Transition, uint, error)
+	SignalApproval(client string, requestID string, signal workflows.ApprovalSignal) error
+	GetApprovals(client string, requestID string) (*workflows.ApprovalState, []models.Approval, error)
+	CancelScheduledRequest(client string, requestID string) error
+	RescheduleRequest(client string, requestID string, executeAt time.Time) error
+	RefundRequest(requestID string, reason string) (*models.JournalEntry, error)
+	RetryRequest(client string, requestID string, retriedBy string) (*models.Request, error)
+	RetryFailedRequests(filter service.RetryFilter, retriedBy string) ([]service.RetryResult, error)
+	ListRequestAttempts(client string, requestID string) ([]models.RequestAttempt, error)
+}
+
+type Handler struct {
+	service Service
+
+	// streamPollInterval is how often a stream looks for new transitions.
+	streamPollInterval time.Duration
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service, streamPollInterval: time.Second}
+}
+
+func (h *Handler) RegisterRoutes(r gin.IRouter) {
+	r.GET("/requests", h.HandleListRequests)
+	r.GET("/requests/stream", h.HandleStreamRequests)
+	r.POST("/requests/retry", auth.RequireOperator, h.HandleRetryFailedRequests)
+	r.GET("/requests/:id", h.HandleGetRequest)
+	r.POST("/requests/:id/approve", h.HandleApproveRequest)
+	r.POST("/requests/:id/reject", h.HandleRejectRequest)
+	r.POST("/requests/:id/cancel", h.HandleCancelRequest)
+	r.POST("/requests/:id/reschedule", h.HandleRescheduleRequest)
+	r.POST("/requests/:id/refund", auth.RequireOperator, h.HandleRefundRequest)
+	r.GET("/requests/:id/approvals", h.HandleGetApprovals)
+	r.POST("/requests/:id/retry", h.HandleRetryRequest)
+	r.GET("/requests/:id/attempts", h.HandleListAttempts)
+}
+
+type RequestResponse struct {
+	ID              string     `json:"id"`
+	Type            string     `json:"type"`
+	Amount          float64    `json:"amount"`
+	Recipient       string     `json:"recipient"`
+	Token           string     `json:"token"`
+	Chain           string     `json:"chain"`
+	Status          string     `json:"status"`
+	Reason          string     `json:"reason,omitempty"`
+	Attempt         int        `json:"attempt"`
+	ProviderOrderID string     `json:"provider_order_id,omitempty"`
+	TxHash          string     `json:"tx_hash,omitempty"`
+	ErrorCode       string     `json:"error_code,omitempty"`
+	ErrorDetail     string     `json:"error_detail,omitempty"`
+	ClientReference *string    `json:"client_reference,omitempty"`
+	ScheduleID      *uuid.UUID `json:"schedule_id,omitempty"`
+	ExecuteAt       *time.Time `json:"execute_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	FailedAt        *time.Time `json:"failed_at,omitempty"`
+}
+
+func NewRequestResponse(request models.Request) RequestResponse {
+	return RequestResponse{
+		ID:              request.ID.String(),
+		Type:            request.Type,
+		Amount:          request.Amount,
+		Recipient:       request.Recipient,
+		Token:           request.Token,
+		Chain:           request.Chain,
+		Status:          request.Status,
+		Reason:          request.Reason,
+		Attempt:         request.Attempt,
+		ProviderOrderID: request.ProviderOrderID,
+		TxHash:          request.TxHash,
+		ErrorCode:       request.ErrorCode,
+		ErrorDetail:     request.ErrorDetail,
+		ClientReference: request.ClientReference,
+		ScheduleID:      request.ScheduleID,
+		ExecuteAt:       request.ExecuteAt,
+		CreatedAt:       request.CreatedAt,
+		CompletedAt:     request.CompletedAt,
+		FailedAt:        request.FailedAt,
+	}
+}
+
+// HandleGetRequest returns one of the caller's requests. Operators can get any
+// client's.
+func (h *Handler) HandleGetRequest(c *gin.Context) {
+	request, err := h.service.GetRequest(auth.Scope(c), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrRequestNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewRequestResponse(*request))
+}
+
+// HandleListRequests returns the caller's newest requests. Operators see every
+// client's requests and may narrow them to one submitter with the query param.
+func (h *Handler) HandleListRequests(c *gin.Context) {
+	filter := repository.RequestFilter{
+		Submitter: auth.Scope(c),
+		Type:      c.Query("type"),
+		Status:    c.Query("status"),
+		ErrorCode: c.Query("error_code"),
+	}
+	if auth.ClientFrom(c).Operator {
+		filter.Submitter = c.Query("submitter")
+	}
+	if limit := c.Query("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a number"})
+			return
+		}
+		filter.Limit = parsed
+	}
+	var err error
+	if filter.ExecuteAfter, err = timeQuery(c, "execute_after"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.ExecuteBefore, err = timeQuery(c, "execute_before"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	requests, err := h.service.ListRequests(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]RequestResponse, 0, len(requests))
+	for _, request := range requests {
+		response = append(response, NewRequestResponse(request))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"requests": response})
+}
+
+// timeQuery parses an optional RFC3339 query param.
+func timeQuery(c *gin.Context, param string) (*time.Time, error) {
+	value := c.Query(param)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an RFC3339 time", param)
+	}
+
+	return &parsed, nil
+}
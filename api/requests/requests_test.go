@@ -0,0 +1,175 @@
+package requests
+
+import (
+	"encoding/json"
+	"errors"
+	"mint-redeem-workflow/api/auth"
+	"mint-redeem-workflow/events"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/repository"
+	"mint-redeem-workflow/service"
+	"mint-redeem-workflow/worker/workflows"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeService struct {
+	getRequest             func(client string, requestID string) (*models.Request, error)
+	listRequests           func(filter repository.RequestFilter) ([]models.Request, error)
+	listStatusTransitions  func(filter events.Filter) ([]events.Transition, uint, error)
+	signalApproval         func(client string, requestID string, signal workflows.ApprovalSignal) error
+	getApprovals           func(client string, requestID string) (*workflows.ApprovalState, []models.Approval, error)
+	cancelScheduledRequest func(client string, requestID string) error
+	rescheduleRequest      func(client string, requestID string, executeAt time.Time) error
+	refundRequest          func(requestID string, reason string) (*models.JournalEntry, error)
+	retryRequest           func(client string, requestID string, retriedBy string) (*models.Request, error)
+	retryFailedRequests    func(filter service.RetryFilter, retriedBy string) ([]service.RetryResult, error)
+	listRequestAttempts    func(client string, requestID string) ([]models.RequestAttempt, error)
+}
+
+func (f *fakeService) GetRequest(client string, requestID string) (*models.Request, error) {
+	return f.getRequest(client, requestID)
+}
+
+func (f *fakeService) ListRequests(filter repository.RequestFilter) ([]models.Request, error) {
+	return f.listRequests(filter)
+}
+
+func (f *fakeService) ListStatusTransitions(filter events.Filter) ([]events.Transition, uint, error) {
+	return f.listStatusTransitions(filter)
+}
+
+func (f *fakeService) SignalApproval(client string, requestID string, signal workflows.ApprovalSignal) error {
+	return f.signalApproval(client, requestID, signal)
+}
+
+func (f *fakeService) GetApprovals(client string, requestID string) (*workflows.ApprovalState, []models.Approval, error) {
+	return f.getApprovals(client, requestID)
+}
+
+func (f *fakeService) CancelScheduledRequest(client string, requestID string) error {
+	return f.cancelScheduledRequest(client, requestID)
+}
+
+func (f *fakeService) RescheduleRequest(client string, requestID string, executeAt time.Time) error {
+	return f.rescheduleRequest(client, requestID, executeAt)
+}
+
+func (f *fakeService) RefundRequest(requestID string, reason string) (*models.JournalEntry, error) {
+	return f.refundRequest(requestID, reason)
+}
+
+func (f *fakeService) RetryRequest(client string, requestID string, retriedBy string) (*models.Request, error) {
+	return f.retryRequest(client, requestID, retriedBy)
+}
+
+func (f *fakeService) RetryFailedRequests(filter service.RetryFilter, retriedBy string) ([]service.RetryResult, error) {
+	return f.retryFailedRequests(filter, retriedBy)
+}
+
+func (f *fakeService) ListRequestAttempts(client string, requestID string) ([]models.RequestAttempt, error) {
+	return f.listRequestAttempts(client, requestID)
+}
+
+func serve(svc Service, req *http.Request) *httptest.ResponseRecorder {
+	return serveAs(svc, auth.Client{}, req)
+}
+
+// serveAs serves req as client.
+func serveAs(svc Service, client auth.Client, req *http.Request) *httptest.ResponseRecorder {
+	r := gin.New()
+	r.Use(func(c *gin.Context) { auth.SetClient(c, client) })
+	NewHandler(svc).RegisterRoutes(r)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestHandleGetRequest_ReturnsProviderDetails(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	completedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	request := models.Request{
+		ID:              uuid.New(),
+		Type:            "mint",
+		Amount:          10.5,
+		Recipient:       "0xnotdeadbeef",
+		Token:           "USDC",
+		Chain:           "ethereum",
+		Status:          "completed",
+		ProviderOrderID: "2VZvtmVc2j3gQ80CTlcuQXbGrwC",
+		TxHash:          "0xhash",
+		CompletedAt:     &completedAt,
+	}
+	svc.getRequest = func(client string, requestID string) (*models.Request, error) {
+		assert.Equal(t, request.ID.String(), requestID)
+		return &request, nil
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/requests/"+request.ID.String(), nil)
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp map[string]interface{}
+	err := json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "2VZvtmVc2j3gQ80CTlcuQXbGrwC", resp["provider_order_id"])
+	assert.Equal(t, "0xhash", resp["tx_hash"])
+	assert.Equal(t, "2024-01-01T12:00:00Z", resp["completed_at"])
+	assert.NotContains(t, resp, "failed_at")
+}
+
+func TestHandleGetRequest_NotFoundReturns404(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	svc.getRequest = func(client string, requestID string) (*models.Request, error) {
+		return nil, service.ErrRequestNotFound
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/requests/missing", nil)
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandleListRequests_PassesFilters(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	svc.listRequests = func(filter repository.RequestFilter) ([]models.Request, error) {
+		assert.Equal(t, repository.RequestFilter{Type: "mint", Status: "failed", Limit: 10}, filter)
+		return []models.Request{{ID: uuid.New(), Type: "mint", Status: "failed", ErrorCode: "brale_validation_error"}}, nil
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/requests?type=mint&status=failed&limit=10", nil)
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp struct {
+		Requests []RequestResponse `json:"requests"`
+	}
+	err := json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Requests, 1)
+	assert.Equal(t, "brale_validation_error", resp.Requests[0].ErrorCode)
+}
+
+func TestHandleListRequests_ServiceErrorReturns500(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	svc.listRequests = func(filter repository.RequestFilter) ([]models.Request, error) {
+		return nil, errors.New("db down")
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/requests", nil)
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
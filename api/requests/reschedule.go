@@ -0,0 +1,54 @@
+package requests
+
+import (
+	"errors"
+	"mint-redeem-workflow/api/auth"
+	"mint-redeem-workflow/service"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RescheduleRequest struct {
+	ExecuteAt time.Time `json:"execute_at" binding:"required"`
+}
+
+func (h *Handler) HandleCancelRequest(c *gin.Context) {
+	requestID := c.Param("id")
+	if err := h.service.CancelScheduledRequest(auth.Scope(c), requestID); err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"id": requestID, "status": "cancel sent"})
+}
+
+func (h *Handler) HandleRescheduleRequest(c *gin.Context) {
+	var req RescheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	requestID := c.Param("id")
+	if err := h.service.RescheduleRequest(auth.Scope(c), requestID, req.ExecuteAt); err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"id": requestID, "status": "reschedule sent", "execute_at": req.ExecuteAt})
+}
+
+func writeScheduleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrRequestNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidExecuteAt):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrNotScheduled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
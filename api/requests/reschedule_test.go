@@ -0,0 +1,49 @@
+package requests
+
+import (
+	"bytes"
+	"encoding/json"
+	"mint-redeem-workflow/service"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleRescheduleRequest_PassesExecuteAt(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	executeAt := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+	svc.rescheduleRequest = func(client string, requestID string, got time.Time) error {
+		assert.Equal(t, "request-id", requestID)
+		assert.True(t, executeAt.Equal(got))
+		return nil
+	}
+
+	reqBody, _ := json.Marshal(RescheduleRequest{ExecuteAt: executeAt})
+	req, _ := http.NewRequest(http.MethodPost, "/requests/request-id/reschedule", bytes.NewBuffer(reqBody))
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestHandleCancelRequest_ErrorsMapToStatusCodes(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	cases := map[error]int{
+		service.ErrRequestNotFound: http.StatusNotFound,
+		service.ErrNotScheduled:    http.StatusConflict,
+	}
+
+	for serviceErr, status := range cases {
+		svc.cancelScheduledRequest = func(client string, requestID string) error {
+			return serviceErr
+		}
+
+		req, _ := http.NewRequest(http.MethodPost, "/requests/request-id/cancel", nil)
+		rec := serve(svc, req)
+
+		assert.Equal(t, status, rec.Code, serviceErr.Error())
+	}
+}
@@ -0,0 +1,116 @@
+package requests
+
+import (
+	"errors"
+	"mint-redeem-workflow/api/auth"
+	"mint-redeem-workflow/service"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BulkRetryRequest struct {
+	ErrorCode string `json:"error_code" binding:"required"`
+	Type      string `json:"type"`
+	Limit     int    `json:"limit"`
+}
+
+type RetryResultResponse struct {
+	ID      string `json:"id"`
+	Attempt int    `json:"attempt"`
+	Error   string `json:"error,omitempty"`
+}
+
+type AttemptResponse struct {
+	Attempt             int       `json:"attempt"`
+	RunID               string    `json:"run_id"`
+	PreviousRunID       string    `json:"previous_run_id,omitempty"`
+	PreviousErrorCode   string    `json:"previous_error_code,omitempty"`
+	PreviousErrorDetail string    `json:"previous_error_detail,omitempty"`
+	RetriedBy           string    `json:"retried_by,omitempty"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+// HandleRetryRequest starts the next attempt of a failed request. The attempt
+// is recorded as retried by the caller, which has to be the request's
+// submitter or an operator.
+func (h *Handler) HandleRetryRequest(c *gin.Context) {
+	request, err := h.service.RetryRequest(auth.Scope(c), c.Param("id"), auth.ClientFrom(c).Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrRequestNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrNotRetryable), errors.Is(err, service.ErrTooManyAttempts), errors.Is(err, service.ErrRequestConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, NewRequestResponse(*request))
+}
+
+// HandleRetryFailedRequests retries the failed requests with an error code and
+// reports how each one went. It retries every client's requests, so only
+// operators can call it.
+func (h *Handler) HandleRetryFailedRequests(c *gin.Context) {
+	var req BulkRetryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	filter := service.RetryFilter{ErrorCode: req.ErrorCode, Type: req.Type, Limit: req.Limit}
+	results, err := h.service.RetryFailedRequests(filter, auth.ClientFrom(c).Name)
+	if err != nil {
+		if errors.Is(err, service.ErrErrorCodeRequired) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	retried := 0
+	response := make([]RetryResultResponse, 0, len(results))
+	for _, result := range results {
+		item := RetryResultResponse{ID: result.RequestID, Attempt: result.Attempt}
+		if result.Err != nil {
+			item.Error = result.Err.Error()
+		} else {
+			retried++
+		}
+		response = append(response, item)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"retried": retried, "results": response})
+}
+
+func (h *Handler) HandleListAttempts(c *gin.Context) {
+	attempts, err := h.service.ListRequestAttempts(auth.Scope(c), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrRequestNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]AttemptResponse, 0, len(attempts))
+	for _, attempt := range attempts {
+		response = append(response, AttemptResponse{
+			Attempt:             attempt.Attempt,
+			RunID:               attempt.RunID,
+			PreviousRunID:       attempt.PreviousRunID,
+			PreviousErrorCode:   attempt.PreviousErrorCode,
+			PreviousErrorDetail: attempt.PreviousErrorDetail,
+			RetriedBy:           attempt.RetriedBy,
+			CreatedAt:           attempt.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"attempts": response})
+}
@@ -0,0 +1,108 @@
+package requests
+
+import (
+	"bytes"
+	"encoding/json"
+	"mint-redeem-workflow/api/auth"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/service"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleRetryRequest_ReturnsNextAttempt(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	requestID := uuid.New()
+	svc.retryRequest = func(client string, id string, retriedBy string) (*models.Request, error) {
+		assert.Equal(t, "acme", client)
+		assert.Equal(t, requestID.String(), id)
+		assert.Equal(t, "acme", retriedBy)
+		return &models.Request{ID: requestID, Type: "mint", Status: "started", Attempt: 2}, nil
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "/requests/"+requestID.String()+"/retry", nil)
+	rec := serveAs(svc, auth.Client{Name: "acme"}, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp RequestResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "started", resp.Status)
+	assert.Equal(t, 2, resp.Attempt)
+}
+
+func TestHandleRetryRequest_NotRetryableReturns409(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	svc.retryRequest = func(client string, id string, retriedBy string) (*models.Request, error) {
+		return nil, service.ErrNotRetryable
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "/requests/request-id/retry", nil)
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestHandleRetryRequest_ConcurrentUpdateReturns409(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	svc.retryRequest = func(client string, id string, retriedBy string) (*models.Request, error) {
+		return nil, service.ErrRequestConflict
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "/requests/request-id/retry", nil)
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestHandleRetryFailedRequests_ReportsEachRequest(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	svc.retryFailedRequests = func(filter service.RetryFilter, retriedBy string) ([]service.RetryResult, error) {
+		assert.Equal(t, "brale_server_error", filter.ErrorCode)
+		assert.Equal(t, "mint", filter.Type)
+		assert.Equal(t, "operator", retriedBy)
+		return []service.RetryResult{
+			{RequestID: "first", Attempt: 2},
+			{RequestID: "second", Attempt: 5, Err: service.ErrTooManyAttempts},
+		}, nil
+	}
+
+	reqBody, _ := json.Marshal(BulkRetryRequest{ErrorCode: "brale_server_error", Type: "mint"})
+	req, _ := http.NewRequest(http.MethodPost, "/requests/retry", bytes.NewBuffer(reqBody))
+	rec := serveAs(svc, auth.Client{Name: "operator", Operator: true}, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp struct {
+		Retried int                   `json:"retried"`
+		Results []RetryResultResponse `json:"results"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Retried)
+	assert.Len(t, resp.Results, 2)
+	assert.Empty(t, resp.Results[0].Error)
+	assert.Equal(t, service.ErrTooManyAttempts.Error(), resp.Results[1].Error)
+}
+
+func TestHandleRetryFailedRequests_MissingErrorCodeReturns400(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	req, _ := http.NewRequest(http.MethodPost, "/requests/retry", bytes.NewBufferString(`{"type":"mint"}`))
+	rec := serveAs(svc, auth.Client{Name: "operator", Operator: true}, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandleRetryFailedRequests_NonOperatorReturns403(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	req, _ := http.NewRequest(http.MethodPost, "/requests/retry", bytes.NewBufferString(`{"error_code":"brale_server_error"}`))
+	rec := serveAs(svc, auth.Client{Name: "acme"}, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
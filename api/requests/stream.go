@@ -0,0 +1,103 @@
+package requests
+
+import (
+	"mint-redeem-workflow/api/auth"
+	"mint-redeem-workflow/events"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// streamKeepAlive is how often an idle stream gets a comment, so proxies
+// don't close it.
+const streamKeepAlive = time.Second * 15
+
+type TransitionResponse struct {
+	RequestID string    `json:"request_id"`
+	Type      string    `json:"type"`
+	From      string    `json:"from,omitempty"`
+	Status    string    `json:"status"`
+	Reason    string    `json:"reason,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// HandleStreamRequests streams status transitions of the caller's requests as
+// server-sent events, optionally filtered by request type and the status moved
+// to. Operators see every client's requests and may narrow them to one
+// submitter with the query param. Each event's ID is its request event ID, so
+// a client reconnecting with Last-Event-ID picks up after the last transition
+// it received.
+func (h *Handler) HandleStreamRequests(c *gin.Context) {
+	filter := events.Filter{
+		Submitter: auth.Scope(c),
+		Type:      c.Query("type"),
+		Status:    c.Query("status"),
+	}
+	if auth.ClientFrom(c).Operator {
+		filter.Submitter = c.Query("submitter")
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	if lastEventID != "" {
+		parsed, err := strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Last-Event-ID must be a number"})
+			return
+		}
+		filter.AfterID = uint(parsed)
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	poll := time.NewTicker(h.streamPollInterval)
+	defer poll.Stop()
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		transitions, lastID, err := h.service.ListStatusTransitions(filter)
+		if err != nil {
+			c.SSEvent("error", gin.H{"error": err.Error()})
+			c.Writer.Flush()
+			return
+		}
+
+		for _, transition := range transitions {
+			c.Render(-1, sse.Event{
+				Id:    strconv.FormatUint(uint64(transition.EventID), 10),
+				Event: events.TypeStatusChanged,
+				Data: TransitionResponse{
+					RequestID: transition.RequestID.String(),
+					Type:      transition.RequestType,
+					From:      transition.From,
+					Status:    transition.To,
+					Reason:    transition.Reason,
+					ChangedAt: transition.CreatedAt,
+				},
+			})
+		}
+		if len(transitions) > 0 {
+			c.Writer.Flush()
+		}
+		filter.AfterID = lastID
+
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-keepAlive.C:
+			c.Writer.WriteString(": keep-alive\n\n")
+			c.Writer.Flush()
+		case <-poll.C:
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package requests
+
+import (
+	"context"
+	"mint-redeem-workflow/api/auth"
+	"mint-redeem-workflow/events"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// stream serves req as client until the stream has had a few polls.
+func stream(svc Service, client auth.Client, req *http.Request) *httptest.ResponseRecorder {
+	handler := NewHandler(svc)
+	handler.streamPollInterval = time.Millisecond
+	r := gin.New()
+	r.Use(func(c *gin.Context) { auth.SetClient(c, client) })
+	handler.RegisterRoutes(r)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req.WithContext(ctx))
+
+	return rec
+}
+
+func TestHandleStreamRequests_ResumesAfterLastEventID(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	requestID := uuid.New()
+	var filters []events.Filter
+	svc.listStatusTransitions = func(filter events.Filter) ([]events.Transition, uint, error) {
+		filters = append(filters, filter)
+		if len(filters) > 1 {
+			return nil, filter.AfterID, nil
+		}
+		return []events.Transition{{
+			EventID:      8,
+			RequestID:    requestID,
+			RequestType:  "mint",
+			StatusChange: events.StatusChange{From: "started", To: "completed"},
+		}}, 9, nil
+	}
+	req, _ := http.NewRequest(http.MethodGet, "/requests/stream?type=mint&status=completed", nil)
+	req.Header.Set("Last-Event-ID", "7")
+	rec := stream(svc, auth.Client{Name: "acme"}, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	assert.Equal(t, events.Filter{Submitter: "acme", Type: "mint", Status: "completed", AfterID: 7}, filters[0])
+	assert.Equal(t, uint(9), filters[1].AfterID)
+	assert.Contains(t, rec.Body.String(), "id:8\nevent:status_changed\ndata:{\"request_id\":\""+requestID.String()+"\",\"type\":\"mint\",\"from\":\"started\",\"status\":\"completed\"")
+}
+
+func TestHandleStreamRequests_ScopesSubmitterToCaller(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		client    auth.Client
+		query     string
+		submitter string
+	}{
+		{auth.Client{Name: "acme"}, "", "acme"},
+		{auth.Client{Name: "acme"}, "?submitter=treasury", "acme"},
+		{auth.Client{Name: "operator", Operator: true}, "", ""},
+		{auth.Client{Name: "operator", Operator: true}, "?submitter=treasury", "treasury"},
+	} {
+		svc := &fakeService{}
+		var filters []events.Filter
+		svc.listStatusTransitions = func(filter events.Filter) ([]events.Transition, uint, error) {
+			filters = append(filters, filter)
+			return nil, filter.AfterID, nil
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, "/requests/stream"+tc.query, nil)
+		stream(svc, tc.client, req)
+
+		assert.Equal(t, tc.submitter, filters[0].Submitter, tc.client.Name+tc.query)
+	}
+}
+
+func TestHandleStreamRequests_InvalidLastEventIDReturns400(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	req, _ := http.NewRequest(http.MethodGet, "/requests/stream", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
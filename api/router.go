@@ -0,0 +1,52 @@
+// Package api builds the HTTP API out of the handlers of its subpackages.
+package api
+
+import (
+	"mint-redeem-workflow/api/approvers"
+	"mint-redeem-workflow/api/auth"
+	"mint-redeem-workflow/api/batches"
+	"mint-redeem-workflow/api/health"
+	"mint-redeem-workflow/api/ledger"
+	"mint-redeem-workflow/api/operations"
+	"mint-redeem-workflow/api/reconciliations"
+	"mint-redeem-workflow/api/requests"
+	"mint-redeem-workflow/api/schedules"
+	"mint-redeem-workflow/api/webhooks"
+	"mint-redeem-workflow/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Service is everything the handlers need from the service layer.
+// service.Service implements it.
+type Service interface {
+	approvers.Service
+	batches.Service
+	ledger.Service
+	operations.Service
+	reconciliations.Service
+	requests.Service
+	schedules.Service
+	webhooks.Service
+}
+
+// NewRouter returns the API's routes served by handlers that call svc and
+// check requests against cfg. Every route but GET /ready, which reports
+// whether ready passes, needs the key of one of cfg's API clients.
+func NewRouter(svc Service, cfg *config.ServiceConfig, ready health.Check) *gin.Engine {
+	r := gin.Default()
+
+	health.NewHandler(ready).RegisterRoutes(r)
+
+	authed := r.Group("/", auth.Middleware(cfg.APIClients))
+	operations.NewHandler(svc, cfg).RegisterRoutes(authed)
+	batches.NewHandler(svc, cfg).RegisterRoutes(authed)
+	schedules.NewHandler(svc, cfg).RegisterRoutes(authed)
+	requests.NewHandler(svc).RegisterRoutes(authed)
+	ledger.NewHandler(svc).RegisterRoutes(authed)
+	reconciliations.NewHandler(svc).RegisterRoutes(authed)
+	approvers.NewHandler(svc).RegisterRoutes(authed)
+	webhooks.NewHandler(svc).RegisterRoutes(authed)
+
+	return r
+}
@@ -0,0 +1,222 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mint-redeem-workflow/api/auth"
+	"mint-redeem-workflow/config"
+	"mint-redeem-workflow/infra/cadence"
+	"mint-redeem-workflow/repository"
+	"mint-redeem-workflow/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/cadence/client"
+)
+
+// fakeCadence starts every workflow it is given.
+type fakeCadence struct {
+	cadence.WorkflowClient
+}
+
+func (fakeCadence) ExecuteWorkflow(ctx context.Context, options client.StartWorkflowOptions, workflow interface{}, args ...interface{}) (client.WorkflowRun, error) {
+	return fakeRun{id: options.ID}, nil
+}
+
+type fakeRun struct {
+	client.WorkflowRun
+	id string
+}
+
+func (r fakeRun) GetID() string {
+	return r.id
+}
+
+func (r fakeRun) GetRunID() string {
+	return "run-id"
+}
+
+func ready(ctx context.Context) error {
+	return nil
+}
+
+func newRouter(t *testing.T) *gin.Engine {
+	cfg, err := config.NewServiceConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.APIClients = []config.APIClient{
+		{Name: "acme", KeyHash: auth.HashKey("acme-key")},
+		{Name: "globex", KeyHash: auth.HashKey("globex-key")},
+		{Name: "ops", KeyHash: auth.HashKey("ops-key"), Operator: true},
+	}
+
+	return NewRouter(service.New(nil, repository.NewMemoryRequestRepository(), fakeCadence{}, cfg), cfg, ready)
+}
+
+func TestNewRouter_RegistersEveryHandler(t *testing.T) {
+	t.Parallel()
+	r := newRouter(t)
+
+	routes := map[string]bool{}
+	for _, route := range r.Routes() {
+		routes[route.Method+" "+route.Path] = true
+	}
+
+	for _, route := range []string{
+		"POST /mint",
+		"POST /redeem",
+		"POST /batches",
+		"GET /schedules",
+		"GET /requests/:id",
+		"GET /requests/stream",
+		"GET /ledger/balances",
+		"GET /reconciliations/:id/export",
+		"POST /approvers/keys",
+		"POST /webhooks/:id/deliveries/:delivery_id/redeliver",
+		"GET /ready",
+	} {
+		assert.True(t, routes[route], route)
+	}
+}
+
+func TestNewRouter_SubmitAndGetRequest(t *testing.T) {
+	t.Parallel()
+	r := newRouter(t)
+
+	reqBody, _ := json.Marshal(gin.H{"amount": 10.5, "recipient": "0xnotdeadbeef", "token": "USDC", "chain": "ethereum"})
+	req, _ := http.NewRequest(http.MethodPost, "/mint", bytes.NewBuffer(reqBody))
+	req.Header.Set("Authorization", "Bearer acme-key")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var created map[string]string
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+
+	req, _ = http.NewRequest(http.MethodGet, "/requests/"+created["id"], nil)
+	req.Header.Set("Authorization", "Bearer acme-key")
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var fetched map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &fetched))
+	assert.Equal(t, "mint", fetched["type"])
+	assert.Equal(t, "started", fetched["status"])
+
+	req, _ = http.NewRequest(http.MethodGet, "/requests?type=mint", nil)
+	req.Header.Set("Authorization", "Bearer acme-key")
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), created["id"])
+}
+
+func TestNewRouter_OnlySubmitterAndOperatorsSeeARequest(t *testing.T) {
+	t.Parallel()
+	r := newRouter(t)
+	send := func(method string, path string, key string, body interface{}) *httptest.ResponseRecorder {
+		var reqBody bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&reqBody).Encode(body)
+		}
+		req, _ := http.NewRequest(method, path, &reqBody)
+		req.Header.Set("Authorization", "Bearer "+key)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	executeAt := time.Now().Add(time.Hour)
+	rec := send(http.MethodPost, "/mint", "acme-key", gin.H{"amount": 10.5, "recipient": "0xnotdeadbeef", "token": "USDC", "chain": "ethereum", "execute_at": executeAt})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var created map[string]string
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	id := created["id"]
+
+	for _, route := range []struct {
+		method string
+		path   string
+		body   interface{}
+	}{
+		{http.MethodGet, "/requests/" + id, nil},
+		{http.MethodGet, "/requests/" + id + "/approvals", nil},
+		{http.MethodGet, "/requests/" + id + "/attempts", nil},
+		{http.MethodPost, "/requests/" + id + "/reschedule", gin.H{"execute_at": executeAt.Add(time.Hour)}},
+		{http.MethodPost, "/requests/" + id + "/cancel", nil},
+	} {
+		rec := send(route.method, route.path, "globex-key", route.body)
+		assert.Equal(t, http.StatusNotFound, rec.Code, route.path)
+	}
+
+	rec = send(http.MethodGet, "/requests", "globex-key", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), id)
+
+	for _, key := range []string{"acme-key", "ops-key"} {
+		rec = send(http.MethodGet, "/requests/"+id, key, nil)
+		assert.Equal(t, http.StatusOK, rec.Code, key)
+
+		rec = send(http.MethodGet, "/requests", key, nil)
+		assert.Contains(t, rec.Body.String(), id, key)
+	}
+
+	rec = send(http.MethodGet, "/requests?submitter=globex", "ops-key", nil)
+	assert.NotContains(t, rec.Body.String(), id)
+}
+
+func TestNewRouter_SubmitterCannotApproveItsOwnRequest(t *testing.T) {
+	t.Parallel()
+	r := newRouter(t)
+
+	reqBody, _ := json.Marshal(gin.H{"amount": 50000, "recipient": "0xnotdeadbeef", "token": "USDC", "chain": "ethereum"})
+	req, _ := http.NewRequest(http.MethodPost, "/mint", bytes.NewBuffer(reqBody))
+	req.Header.Set("Authorization", "Bearer acme-key")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var created map[string]string
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+
+	// Naming another approver in the body doesn't change who is deciding.
+	for _, decision := range []string{"approve", "reject"} {
+		reqBody, _ = json.Marshal(gin.H{"approver": "alice", "signature": "c2ln"})
+		req, _ = http.NewRequest(http.MethodPost, "/requests/"+created["id"]+"/"+decision, bytes.NewBuffer(reqBody))
+		req.Header.Set("Authorization", "Bearer acme-key")
+		rec = httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code, decision)
+		assert.Contains(t, rec.Body.String(), service.ErrSubmitterCannotApprove.Error(), decision)
+	}
+}
+
+func TestNewRouter_RequiresAPIKeyExceptForReady(t *testing.T) {
+	t.Parallel()
+	r := newRouter(t)
+
+	for _, key := range []string{"", "Bearer ", "Bearer wrong-key", "acme-key"} {
+		req, _ := http.NewRequest(http.MethodGet, "/requests", nil)
+		if key != "" {
+			req.Header.Set("Authorization", key)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, key)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/ready", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,191 @@
+package schedules
+
+import (
+	"errors"
+	"fmt"
+	"mint-redeem-workflow/api/auth"
+	"mint-redeem-workflow/config"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/operations"
+	"mint-redeem-workflow/service"
+	"mint-redeem-workflow/worker/workflows"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/robfig/cron"
+)
+
+// Service is what the schedule handlers need from the service layer.
+type Service interface {
+	CreateSchedule(schedule *models.Schedule, workflowParam workflows.ScheduleInput) error
+	ListSchedules(client string) ([]models.Schedule, error)
+	PauseSchedule(client string, scheduleID string) (*models.Schedule, error)
+	ResumeSchedule(client string, scheduleID string) (*models.Schedule, error)
+	DeleteSchedule(client string, scheduleID string) error
+}
+
+type Handler struct {
+	service Service
+	cfg     *config.ServiceConfig
+}
+
+func NewHandler(service Service, cfg *config.ServiceConfig) *Handler {
+	return &Handler{service: service, cfg: cfg}
+}
+
+func (h *Handler) RegisterRoutes(r gin.IRouter) {
+	r.POST("/schedules", h.HandleCreateSchedule)
+	r.GET("/schedules", h.HandleListSchedules)
+	r.POST("/schedules/:id/pause", h.HandlePauseSchedule)
+	r.POST("/schedules/:id/resume", h.HandleResumeSchedule)
+	r.DELETE("/schedules/:id", h.HandleDeleteSchedule)
+}
+
+type CreateScheduleRequest struct {
+	Type         string  `json:"type" binding:"required"`
+	Amount       float64 `json:"amount" binding:"required"`
+	Recipient    string  `json:"recipient" binding:"required"`
+	Token        string  `json:"token" binding:"required"`
+	Chain        string  `json:"chain" binding:"required"`
+	CronSchedule string  `json:"cron_schedule" binding:"required"`
+}
+
+type ScheduleResponse struct {
+	ID           string    `json:"id"`
+	Type         string    `json:"type"`
+	Amount       float64   `json:"amount"`
+	Recipient    string    `json:"recipient"`
+	Token        string    `json:"token"`
+	Chain        string    `json:"chain"`
+	Submitter    string    `json:"submitter,omitempty"`
+	CronSchedule string    `json:"cron_schedule"`
+	Status       string    `json:"status"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func newScheduleResponse(schedule models.Schedule) ScheduleResponse {
+	return ScheduleResponse{
+		ID:           schedule.ID.String(),
+		Type:         schedule.Type,
+		Amount:       schedule.Amount,
+		Recipient:    schedule.Recipient,
+		Token:        schedule.Token,
+		Chain:        schedule.Chain,
+		Submitter:    schedule.Submitter,
+		CronSchedule: schedule.CronSchedule,
+		Status:       schedule.Status,
+		CreatedAt:    schedule.CreatedAt,
+	}
+}
+
+func (h *Handler) HandleCreateSchedule(c *gin.Context) {
+	var req CreateScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	op, err := operations.Lookup(req.Type)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Cadence parses cron schedules in the standard five field format.
+	if _, err := cron.ParseStandard(req.CronSchedule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid cron schedule: %v", err)})
+		return
+	}
+
+	asset, err := op.ValidateRequest(h.cfg, operations.Params{
+		Amount:    req.Amount,
+		Recipient: req.Recipient,
+		Token:     req.Token,
+		Chain:     req.Chain,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule := models.Schedule{
+		Type:         req.Type,
+		Amount:       req.Amount,
+		Recipient:    req.Recipient,
+		Token:        asset.Token,
+		Chain:        asset.Chain,
+		Submitter:    auth.ClientFrom(c).Name,
+		CronSchedule: req.CronSchedule,
+	}
+
+	var workflowInput workflows.ScheduleInput
+	if required := h.cfg.RequiredApprovals(asset.Token, req.Amount); required > 0 {
+		workflowInput.RequiredApprovals = required
+		workflowInput.ApprovalTimeout = h.cfg.ApprovalTimeout
+	}
+
+	if err := h.service.CreateSchedule(&schedule, workflowInput); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newScheduleResponse(schedule))
+}
+
+// HandleListSchedules lists the caller's schedules. Operators get every
+// client's, or the one client's given in the submitter query param.
+func (h *Handler) HandleListSchedules(c *gin.Context) {
+	client := auth.Scope(c)
+	if client == "" {
+		client = c.Query("submitter")
+	}
+
+	schedules, err := h.service.ListSchedules(client)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := make([]ScheduleResponse, 0, len(schedules))
+	for _, schedule := range schedules {
+		resp = append(resp, newScheduleResponse(schedule))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"schedules": resp})
+}
+
+func (h *Handler) HandlePauseSchedule(c *gin.Context) {
+	h.handleSetStatus(c, h.service.PauseSchedule)
+}
+
+func (h *Handler) HandleResumeSchedule(c *gin.Context) {
+	h.handleSetStatus(c, h.service.ResumeSchedule)
+}
+
+func (h *Handler) handleSetStatus(c *gin.Context, setStatus func(string, string) (*models.Schedule, error)) {
+	schedule, err := setStatus(auth.Scope(c), c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newScheduleResponse(*schedule))
+}
+
+func (h *Handler) HandleDeleteSchedule(c *gin.Context) {
+	if err := h.service.DeleteSchedule(auth.Scope(c), c.Param("id")); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"id": c.Param("id"), "status": "deleted"})
+}
+
+func writeError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrScheduleNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,139 @@
+package schedules
+
+import (
+	"bytes"
+	"encoding/json"
+	"mint-redeem-workflow/api/auth"
+	"mint-redeem-workflow/config"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/service"
+	"mint-redeem-workflow/worker/workflows"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeService struct {
+	createSchedule func(schedule *models.Schedule, workflowParam workflows.ScheduleInput) error
+	listSchedules  func(client string) ([]models.Schedule, error)
+	pauseSchedule  func(client string, scheduleID string) (*models.Schedule, error)
+	resumeSchedule func(client string, scheduleID string) (*models.Schedule, error)
+	deleteSchedule func(client string, scheduleID string) error
+}
+
+func (f *fakeService) CreateSchedule(schedule *models.Schedule, workflowParam workflows.ScheduleInput) error {
+	return f.createSchedule(schedule, workflowParam)
+}
+
+func (f *fakeService) ListSchedules(client string) ([]models.Schedule, error) {
+	return f.listSchedules(client)
+}
+
+func (f *fakeService) PauseSchedule(client string, scheduleID string) (*models.Schedule, error) {
+	return f.pauseSchedule(client, scheduleID)
+}
+
+func (f *fakeService) ResumeSchedule(client string, scheduleID string) (*models.Schedule, error) {
+	return f.resumeSchedule(client, scheduleID)
+}
+
+func (f *fakeService) DeleteSchedule(client string, scheduleID string) error {
+	return f.deleteSchedule(client, scheduleID)
+}
+
+func serve(svc Service, req *http.Request) *httptest.ResponseRecorder {
+	r := gin.New()
+	r.Use(func(c *gin.Context) { auth.SetClient(c, auth.Client{Name: "acme"}) })
+	cfg, _ := config.NewServiceConfig()
+	NewHandler(svc, cfg).RegisterRoutes(r)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func postSchedule(svc Service, body CreateScheduleRequest) *httptest.ResponseRecorder {
+	reqBody, _ := json.Marshal(body)
+	req, _ := http.NewRequest(http.MethodPost, "/schedules", bytes.NewBuffer(reqBody))
+
+	return serve(svc, req)
+}
+
+func TestHandleCreateSchedule_SuccessReturns200(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	var createdInput workflows.ScheduleInput
+	svc.createSchedule = func(schedule *models.Schedule, workflowParam workflows.ScheduleInput) error {
+		schedule.ID = uuid.New()
+		schedule.Status = "active"
+		createdInput = workflowParam
+		return nil
+	}
+
+	rec := postSchedule(svc, CreateScheduleRequest{Type: "mint", Amount: 20000, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", CronSchedule: "0 9 * * 1-5"})
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp ScheduleResponse
+	err := json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "active", resp.Status)
+	assert.Equal(t, "0 9 * * 1-5", resp.CronSchedule)
+	assert.Equal(t, 1, createdInput.RequiredApprovals)
+}
+
+func TestHandleCreateSchedule_InvalidCronReturns400(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	rec := postSchedule(svc, CreateScheduleRequest{Type: "mint", Amount: 20, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", CronSchedule: "every weekday"})
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var resp map[string]string
+	err := json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Contains(t, resp["error"], "invalid cron schedule")
+}
+
+func TestHandlePauseSchedule_NotFoundReturns404(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	var pausedFor string
+	svc.pauseSchedule = func(client string, scheduleID string) (*models.Schedule, error) {
+		pausedFor = client
+		return nil, service.ErrScheduleNotFound
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "/schedules/missing/pause", nil)
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "acme", pausedFor)
+}
+
+func TestHandleListSchedules_ReturnsSchedules(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	var listedFor string
+	svc.listSchedules = func(client string) ([]models.Schedule, error) {
+		listedFor = client
+		return []models.Schedule{{ID: uuid.New(), Type: "mint", CronSchedule: "0 9 * * *", Status: "paused"}}, nil
+	}
+
+	// A client only lists its own schedules, whatever it asks for.
+	req, _ := http.NewRequest(http.MethodGet, "/schedules?submitter=globex", nil)
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "acme", listedFor)
+	var resp struct {
+		Schedules []ScheduleResponse `json:"schedules"`
+	}
+	err := json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Schedules, 1)
+	assert.Equal(t, "paused", resp.Schedules[0].Status)
+}
@@ -0,0 +1,193 @@
+package webhooks
+
+import (
+	"errors"
+	"mint-redeem-workflow/api/auth"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/service"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Service is what the webhook handlers need from the service layer.
+type Service interface {
+	RegisterWebhook(client string, webhookURL string, eventTypes []string) (*models.Webhook, error)
+	ListWebhooks(client string) ([]models.Webhook, error)
+	DeleteWebhook(client string, webhookID string) error
+	ListWebhookDeliveries(client string, webhookID string, status string, limit int) ([]models.WebhookDelivery, error)
+	RedeliverWebhook(client string, webhookID string, deliveryID string) (*models.WebhookDelivery, error)
+}
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) RegisterRoutes(r gin.IRouter) {
+	r.POST("/webhooks", h.HandleRegisterWebhook)
+	r.GET("/webhooks", h.HandleListWebhooks)
+	r.DELETE("/webhooks/:id", h.HandleDeleteWebhook)
+	r.GET("/webhooks/:id/deliveries", h.HandleListDeliveries)
+	r.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", h.HandleRedeliver)
+}
+
+type RegisterWebhookRequest struct {
+	URL        string   `json:"url" binding:"required"`
+	EventTypes []string `json:"event_types" binding:"required,min=1"`
+}
+
+type WebhookResponse struct {
+	ID         string    `json:"id"`
+	Client     string    `json:"client"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	// Secret is only returned when the webhook is registered.
+	Secret string `json:"secret,omitempty"`
+}
+
+type DeliveryResponse struct {
+	ID             string     `json:"id"`
+	RequestID      string     `json:"request_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func newWebhookResponse(webhook models.Webhook) WebhookResponse {
+	return WebhookResponse{
+		ID:         webhook.ID.String(),
+		Client:     webhook.Client,
+		URL:        webhook.URL,
+		EventTypes: strings.Split(webhook.EventTypes, ","),
+		CreatedAt:  webhook.CreatedAt,
+	}
+}
+
+func newDeliveryResponse(delivery models.WebhookDelivery) DeliveryResponse {
+	return DeliveryResponse{
+		ID:             delivery.ID.String(),
+		RequestID:      delivery.RequestID.String(),
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		LastAttemptAt:  delivery.LastAttemptAt,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
+
+// HandleRegisterWebhook registers a webhook for the caller's requests. The
+// response carries the secret deliveries are signed with.
+func (h *Handler) HandleRegisterWebhook(c *gin.Context) {
+	var req RegisterWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	webhook, err := h.service.RegisterWebhook(auth.ClientFrom(c).Name, req.URL, req.EventTypes)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	resp := newWebhookResponse(*webhook)
+	resp.Secret = webhook.Secret
+	c.JSON(http.StatusOK, resp)
+}
+
+// HandleListWebhooks lists the caller's webhooks. Operators get every
+// client's, or the one client's given in the client query param.
+func (h *Handler) HandleListWebhooks(c *gin.Context) {
+	client := auth.Scope(c)
+	if client == "" {
+		client = c.Query("client")
+	}
+
+	webhooks, err := h.service.ListWebhooks(client)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	resp := make([]WebhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		resp = append(resp, newWebhookResponse(webhook))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"webhooks": resp})
+}
+
+func (h *Handler) HandleDeleteWebhook(c *gin.Context) {
+	if err := h.service.DeleteWebhook(auth.Scope(c), c.Param("id")); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"id": c.Param("id"), "status": "deleted"})
+}
+
+func (h *Handler) HandleListDeliveries(c *gin.Context) {
+	limit := 0
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		limit = parsed
+	}
+
+	deliveries, err := h.service.ListWebhookDeliveries(auth.Scope(c), c.Param("id"), c.Query("status"), limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	resp := make([]DeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		resp = append(resp, newDeliveryResponse(delivery))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": resp})
+}
+
+// HandleRedeliver sends a delivered or failed delivery again.
+func (h *Handler) HandleRedeliver(c *gin.Context) {
+	delivery, err := h.service.RedeliverWebhook(auth.Scope(c), c.Param("id"), c.Param("delivery_id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newDeliveryResponse(*delivery))
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidWebhookURL), errors.Is(err, service.ErrInvalidEventType):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrWebhookNotFound), errors.Is(err, service.ErrWebhookDeliveryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrWebhookDeliveryInProcess):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,152 @@
+package webhooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"mint-redeem-workflow/api/auth"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeService struct {
+	registerWebhook       func(client string, webhookURL string, eventTypes []string) (*models.Webhook, error)
+	listWebhooks          func(client string) ([]models.Webhook, error)
+	deleteWebhook         func(client string, webhookID string) error
+	listWebhookDeliveries func(client string, webhookID string, status string, limit int) ([]models.WebhookDelivery, error)
+	redeliverWebhook      func(client string, webhookID string, deliveryID string) (*models.WebhookDelivery, error)
+}
+
+func (f *fakeService) RegisterWebhook(client string, webhookURL string, eventTypes []string) (*models.Webhook, error) {
+	return f.registerWebhook(client, webhookURL, eventTypes)
+}
+
+func (f *fakeService) ListWebhooks(client string) ([]models.Webhook, error) {
+	return f.listWebhooks(client)
+}
+
+func (f *fakeService) DeleteWebhook(client string, webhookID string) error {
+	return f.deleteWebhook(client, webhookID)
+}
+
+func (f *fakeService) ListWebhookDeliveries(client string, webhookID string, status string, limit int) ([]models.WebhookDelivery, error) {
+	return f.listWebhookDeliveries(client, webhookID, status, limit)
+}
+
+func (f *fakeService) RedeliverWebhook(client string, webhookID string, deliveryID string) (*models.WebhookDelivery, error) {
+	return f.redeliverWebhook(client, webhookID, deliveryID)
+}
+
+func serve(svc Service, req *http.Request) *httptest.ResponseRecorder {
+	return serveAs(svc, auth.Client{Name: "acme"}, req)
+}
+
+func serveAs(svc Service, client auth.Client, req *http.Request) *httptest.ResponseRecorder {
+	r := gin.New()
+	r.Use(func(c *gin.Context) { auth.SetClient(c, client) })
+	NewHandler(svc).RegisterRoutes(r)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func performRegisterWebhook(svc Service, body interface{}) *httptest.ResponseRecorder {
+	reqBody, _ := json.Marshal(body)
+	req, _ := http.NewRequest(http.MethodPost, "/webhooks", bytes.NewBuffer(reqBody))
+
+	return serve(svc, req)
+}
+
+func TestHandleRegisterWebhook_SuccessReturnsSecret(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	svc.registerWebhook = func(client string, url string, eventTypes []string) (*models.Webhook, error) {
+		assert.Equal(t, "acme", client)
+		assert.Equal(t, []string{"request.completed", "request.failed"}, eventTypes)
+		return &models.Webhook{ID: uuid.New(), Client: client, URL: url, EventTypes: "request.completed,request.failed", Secret: "secret"}, nil
+	}
+
+	rec := performRegisterWebhook(svc, RegisterWebhookRequest{URL: "https://example.com/hook", EventTypes: []string{"request.completed", "request.failed"}})
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp WebhookResponse
+	err := json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", resp.Secret)
+	assert.Equal(t, []string{"request.completed", "request.failed"}, resp.EventTypes)
+}
+
+func TestHandleRegisterWebhook_InvalidEventTypeReturns400(t *testing.T) {
+	t.Parallel()
+	// The service rejects the event types before it touches the database.
+	svc := service.New(nil, nil, nil, nil)
+	rec := performRegisterWebhook(svc, RegisterWebhookRequest{URL: "https://example.com/hook", EventTypes: []string{"request.done"}})
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = performRegisterWebhook(svc, RegisterWebhookRequest{URL: "https://example.com/hook"})
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandleRedeliver_InProcessReturns409(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	svc.redeliverWebhook = func(client string, webhookID string, deliveryID string) (*models.WebhookDelivery, error) {
+		assert.Equal(t, "acme", client)
+		assert.Equal(t, "webhook-id", webhookID)
+		assert.Equal(t, "delivery-id", deliveryID)
+		return nil, service.ErrWebhookDeliveryInProcess
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "/webhooks/webhook-id/deliveries/delivery-id/redeliver", nil)
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestHandleListDeliveries_UnknownWebhookReturns404(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	svc.listWebhookDeliveries = func(client string, webhookID string, status string, limit int) ([]models.WebhookDelivery, error) {
+		assert.Equal(t, "failed", status)
+		return nil, service.ErrWebhookNotFound
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/webhooks/missing/deliveries?status=failed", nil)
+	rec := serve(svc, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandleListWebhooks_OnlyOperatorsChooseTheClient(t *testing.T) {
+	t.Parallel()
+	var listed []string
+	svc := &fakeService{}
+	svc.listWebhooks = func(client string) ([]models.Webhook, error) {
+		listed = append(listed, client)
+		return nil, nil
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/webhooks?client=globex", nil)
+	rec := serve(svc, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/webhooks?client=globex", nil)
+	rec = serveAs(svc, auth.Client{Name: "operator", Operator: true}, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/webhooks", nil)
+	rec = serveAs(svc, auth.Client{Name: "operator", Operator: true}, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	assert.Equal(t, []string{"acme", "globex", ""}, listed)
+}
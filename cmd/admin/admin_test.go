@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"mint-redeem-workflow/config"
+	"mint-redeem-workflow/events"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/repository"
+	"mint-redeem-workflow/service"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/cadence/.gen/go/shared"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openTestApp(t *testing.T, jsonOutput bool) (*App, *bytes.Buffer) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Request{}, &models.RequestEvent{}))
+
+	cfg, err := config.NewServiceConfig()
+	require.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	return &App{DB: db, Service: service.New(db, repository.NewGormRequestRepository(db), nil, cfg), Out: out, JSON: jsonOutput}, out
+}
+
+func execution(runID string, closeStatus *shared.WorkflowExecutionCloseStatus) *shared.DescribeWorkflowExecutionResponse {
+	return &shared.DescribeWorkflowExecutionResponse{
+		WorkflowExecutionInfo: &shared.WorkflowExecutionInfo{
+			Execution:   &shared.WorkflowExecution{RunId: &runID},
+			CloseStatus: closeStatus,
+		},
+	}
+}
+
+func TestInspect_FlagsStatusThatDisagreesWithWorkflow(t *testing.T) {
+	completed := shared.WorkflowExecutionCloseStatusCompleted
+	failed := shared.WorkflowExecutionCloseStatusFailed
+	tests := []struct {
+		name       string
+		status     string
+		execution  *shared.DescribeWorkflowExecutionResponse
+		consistent bool
+	}{
+		{"running request, running workflow", "awaiting_approval", execution("run-1", nil), true},
+		{"completed request, completed workflow", "completed", execution("run-1", &completed), true},
+		{"failed request, failed workflow", "failed", execution("run-1", &failed), true},
+		{"finished request, workflow past retention", "completed", nil, true},
+		{"running request, no workflow", "started", nil, false},
+		{"running request, closed workflow", "started", execution("run-1", &failed), false},
+		{"completed request, failed workflow", "completed", execution("run-1", &failed), false},
+		{"failed request, running workflow", "failed", execution("run-1", nil), false},
+		{"recorded run isn't the latest", "started", execution("run-2", nil), false},
+	}
+
+	for _, test := range tests {
+		request := models.Request{ID: uuid.New(), Status: test.status, RunID: "run-1"}
+		inspection := Inspect(request, test.execution)
+		assert.Equal(t, test.consistent, inspection.Consistent, test.name)
+		assert.Equal(t, test.consistent, len(inspection.Problems) == 0, test.name)
+	}
+}
+
+func TestRunList_PrintsTableOrJSON(t *testing.T) {
+	app, out := openTestApp(t, false)
+	app.DB.Create(&models.Request{Type: "mint", Amount: 10.5, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", Status: "failed", ErrorCode: "server_error"})
+	app.DB.Create(&models.Request{Type: "redeem", Amount: 3, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", Status: "completed"})
+
+	require.NoError(t, runList(app, []string{"-status", "failed"}))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], "ID"))
+	assert.Contains(t, lines[1], "10.500000")
+	assert.Contains(t, lines[1], "server_error")
+
+	app.JSON = true
+	out.Reset()
+	require.NoError(t, runList(app, []string{"-type", "redeem"}))
+
+	var listed []map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &listed))
+	require.Len(t, listed, 1)
+	assert.Equal(t, "completed", listed[0]["status"])
+}
+
+func TestRunEvents_PrintsRequestHistory(t *testing.T) {
+	app, out := openTestApp(t, false)
+	request := models.Request{Type: "mint", Amount: 10.5, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", Status: "started"}
+	app.DB.Create(&request)
+	require.NoError(t, events.RecordStatusChange(app.DB, request.ID, "", "pending", ""))
+	require.NoError(t, events.RecordStatusChange(app.DB, request.ID, "pending", "started", ""))
+
+	require.NoError(t, runEvents(app, []string{request.ID.String()}))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[2], `{"from":"pending","to":"started"}`)
+
+	assert.Error(t, runEvents(app, []string{uuid.NewString()}))
+}
+
+func TestReconcileRange_RequestReconcilesItsCreationDay(t *testing.T) {
+	app, _ := openTestApp(t, false)
+	request := models.Request{Type: "mint", Amount: 10.5, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", Status: "completed"}
+	app.DB.Create(&request)
+
+	report, err := reconcileRange(app, "", "", request.ID.String())
+	require.NoError(t, err)
+	assert.True(t, report.From.Equal(request.CreatedAt.UTC().Truncate(time.Hour*24)))
+	assert.Equal(t, float64(24), report.To.Sub(report.From).Hours())
+
+	_, err = reconcileRange(app, "2024-01-01T00:00:00Z", "", "")
+	assert.Error(t, err)
+	_, err = reconcileRange(app, "2024-01-02T00:00:00Z", "2024-01-01T00:00:00Z", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"mint-redeem-workflow/api/requests"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/repository"
+	"strconv"
+	"time"
+)
+
+func runList(app *App, args []string) error {
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	requestType := flags.String("type", "", "only list mints or redeems")
+	status := flags.String("status", "", "only list requests in the status")
+	limit := flags.Int("limit", 50, "most requests to list")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	list, err := app.Service.ListRequests(repository.RequestFilter{Type: *requestType, Status: *status, Limit: *limit})
+	if err != nil {
+		return err
+	}
+
+	result := make([]requests.RequestResponse, 0, len(list))
+	table := Table{Headers: []string{"ID", "TYPE", "AMOUNT", "TOKEN", "CHAIN", "STATUS", "ERROR", "CREATED"}}
+	for _, request := range list {
+		result = append(result, requests.NewRequestResponse(request))
+		table.Rows = append(table.Rows, []string{
+			request.ID.String(),
+			request.Type,
+			formatAmount(request.Amount),
+			request.Token,
+			request.Chain,
+			request.Status,
+			request.ErrorCode,
+			formatTime(&request.CreatedAt),
+		})
+	}
+
+	return app.Print(result, table)
+}
+
+func runShow(app *App, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: show <request id>")
+	}
+
+	request, err := app.Service.GetRequest("", args[0])
+	if err != nil {
+		return err
+	}
+
+	return app.printRequest(*request)
+}
+
+func (app *App) printRequest(request models.Request) error {
+	rows := [][]string{
+		{"id", request.ID.String()},
+		{"type", request.Type},
+		{"amount", formatAmount(request.Amount)},
+		{"recipient", request.Recipient},
+		{"token", request.Token},
+		{"chain", request.Chain},
+		{"status", request.Status},
+		{"reason", request.Reason},
+		{"submitter", request.Submitter},
+		{"run id", request.RunID},
+		{"attempt", strconv.Itoa(request.Attempt)},
+		{"provider order id", request.ProviderOrderID},
+		{"tx hash", request.TxHash},
+		{"error code", request.ErrorCode},
+		{"error detail", request.ErrorDetail},
+		{"execute at", formatTime(request.ExecuteAt)},
+		{"created", formatTime(&request.CreatedAt)},
+		{"completed", formatTime(request.CompletedAt)},
+		{"failed", formatTime(request.FailedAt)},
+	}
+
+	return app.Print(requests.NewRequestResponse(request), Table{Rows: rows})
+}
+
+type eventResponse struct {
+	ID        uint      `json:"id"`
+	Type      string    `json:"type"`
+	Data      string    `json:"data,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func runEvents(app *App, args []string) error {
+	flags := flag.NewFlagSet("events", flag.ContinueOnError)
+	follow := flags.Bool("follow", false, "keep printing new events as they are recorded")
+	interval := flags.Duration("interval", time.Second*2, "how often to check for new events with -follow")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: events [-follow] [-interval 2s] <request id>")
+	}
+
+	var lastID uint
+	for {
+		history, err := app.Service.ListRequestEvents("", flags.Arg(0), lastID)
+		if err != nil {
+			return err
+		}
+
+		if len(history) > 0 || lastID == 0 {
+			if err := app.printEvents(history, lastID == 0); err != nil {
+				return err
+			}
+		}
+		if len(history) > 0 {
+			lastID = history[len(history)-1].ID
+		}
+
+		if !*follow {
+			return nil
+		}
+		time.Sleep(*interval)
+	}
+}
+
+// printEvents prints one batch of events. Following prints further batches as
+// table rows without headers, or as JSON lines.
+func (app *App) printEvents(history []models.RequestEvent, first bool) error {
+	table := Table{}
+	if first {
+		table.Headers = []string{"ID", "TYPE", "CREATED", "DATA"}
+	}
+
+	result := make([]eventResponse, 0, len(history))
+	for _, event := range history {
+		result = append(result, eventResponse{ID: event.ID, Type: event.Type, Data: event.Data, CreatedAt: event.CreatedAt})
+		table.Rows = append(table.Rows, []string{strconv.FormatUint(uint64(event.ID), 10), event.Type, formatTime(&event.CreatedAt), event.Data})
+	}
+
+	if app.JSON {
+		for _, event := range result {
+			if err := app.Print(event, table); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return app.Print(result, table)
+}
+
+func runCancel(app *App, args []string) error {
+	flags := flag.NewFlagSet("cancel", flag.ContinueOnError)
+	reason := flags.String("reason", "canceled by operator", "reason recorded on the request")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: cancel [-reason text] <request id>")
+	}
+
+	request, err := app.Service.CancelRequest(flags.Arg(0), *reason)
+	if err != nil {
+		return err
+	}
+
+	if request.Status == "scheduled" {
+		fmt.Fprintf(app.Out, "cancel sent to scheduled request %s\n", request.ID)
+		return nil
+	}
+
+	return app.printRequest(*request)
+}
+
+func runRetry(app *App, args []string) error {
+	flags := flag.NewFlagSet("retry", flag.ContinueOnError)
+	retriedBy := flags.String("by", "operator", "who the attempt is recorded as retried by")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: retry [-by name] <request id>")
+	}
+
+	request, err := app.Service.RetryRequest("", flags.Arg(0), *retriedBy)
+	if err != nil {
+		return err
+	}
+
+	return app.printRequest(*request)
+}
+
+type reconciliationResponse struct {
+	ID     string    `json:"id"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+	Status string    `json:"status"`
+	RunID  string    `json:"run_id"`
+}
+
+func runReconcile(app *App, args []string) error {
+	flags := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	from := flags.String("from", "", "start of the range, RFC3339")
+	to := flags.String("to", "", "end of the range, RFC3339")
+	requestID := flags.String("request", "", "reconcile the UTC day the request was created on")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	report, err := reconcileRange(app, *from, *to, *requestID)
+	if err != nil {
+		return err
+	}
+
+	if err := app.Service.StartReconciliation(report); err != nil {
+		return err
+	}
+
+	result := reconciliationResponse{ID: report.ID.String(), From: report.From, To: report.To, Status: report.Status, RunID: report.RunID}
+	return app.Print(result, Table{Rows: [][]string{
+		{"report", result.ID},
+		{"from", formatTime(&result.From)},
+		{"to", formatTime(&result.To)},
+		{"status", result.Status},
+	}})
+}
+
+// reconcileRange works out the report's range from the flags. With none it is
+// the previous UTC day, like the API.
+func reconcileRange(app *App, from string, to string, requestID string) (*models.ReconciliationReport, error) {
+	switch {
+	case requestID != "":
+		if from != "" || to != "" {
+			return nil, errors.New("-request can't be used with -from and -to")
+		}
+		request, err := app.Service.GetRequest("", requestID)
+		if err != nil {
+			return nil, err
+		}
+		day := request.CreatedAt.UTC().Truncate(time.Hour * 24)
+		return &models.ReconciliationReport{From: day, To: day.Add(time.Hour * 24)}, nil
+	case from == "" && to == "":
+		day := time.Now().UTC().Truncate(time.Hour * 24)
+		return &models.ReconciliationReport{From: day.Add(-time.Hour * 24), To: day}, nil
+	case from == "" || to == "":
+		return nil, errors.New("-from and -to must be used together")
+	}
+
+	fromTime, err := time.Parse(time.RFC3339, from)
+	if err != nil {
+		return nil, fmt.Errorf("-from must be an RFC3339 time")
+	}
+	toTime, err := time.Parse(time.RFC3339, to)
+	if err != nil {
+		return nil, fmt.Errorf("-to must be an RFC3339 time")
+	}
+	if !fromTime.Before(toTime) {
+		return nil, errors.New("-from must be before -to")
+	}
+
+	return &models.ReconciliationReport{From: fromTime, To: toTime}, nil
+}
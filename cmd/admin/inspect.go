@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/service"
+	"strings"
+	"time"
+
+	"go.uber.org/cadence/.gen/go/shared"
+)
+
+const workflowNotFound = "not_found"
+
+// Inspection compares a request's row with the latest run of its workflow.
+type Inspection struct {
+	RequestID         string     `json:"request_id"`
+	Status            string     `json:"status"`
+	RunID             string     `json:"run_id,omitempty"`
+	WorkflowStatus    string     `json:"workflow_status"`
+	WorkflowRunID     string     `json:"workflow_run_id,omitempty"`
+	WorkflowStarted   *time.Time `json:"workflow_started,omitempty"`
+	WorkflowClosed    *time.Time `json:"workflow_closed,omitempty"`
+	PendingActivities []string   `json:"pending_activities,omitempty"`
+	Consistent        bool       `json:"consistent"`
+	Problems          []string   `json:"problems,omitempty"`
+}
+
+func runInspect(app *App, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: inspect <request id>")
+	}
+
+	request, err := app.Service.GetRequest("", args[0])
+	if err != nil {
+		return err
+	}
+
+	execution, err := app.CadenceClient.DescribeWorkflowExecution(context.Background(), request.ID.String(), "")
+	var notExists *shared.EntityNotExistsError
+	if err != nil && !errors.As(err, &notExists) {
+		return err
+	}
+
+	inspection := Inspect(*request, execution)
+	rows := [][]string{
+		{"request", inspection.RequestID},
+		{"status", inspection.Status},
+		{"run id", inspection.RunID},
+		{"workflow status", inspection.WorkflowStatus},
+		{"workflow run id", inspection.WorkflowRunID},
+		{"workflow started", formatTime(inspection.WorkflowStarted)},
+		{"workflow closed", formatTime(inspection.WorkflowClosed)},
+		{"pending activities", strings.Join(inspection.PendingActivities, ", ")},
+		{"consistent", fmt.Sprint(inspection.Consistent)},
+	}
+	for _, problem := range inspection.Problems {
+		rows = append(rows, []string{"problem", problem})
+	}
+
+	return app.Print(inspection, Table{Rows: rows})
+}
+
+// Inspect compares the request with its workflow execution, which is nil when
+// Cadence has no workflow for the request.
+func Inspect(request models.Request, execution *shared.DescribeWorkflowExecutionResponse) Inspection {
+	inspection := Inspection{
+		RequestID:      request.ID.String(),
+		Status:         request.Status,
+		RunID:          request.RunID,
+		WorkflowStatus: workflowNotFound,
+	}
+
+	active := service.IsActiveStatus(request.Status)
+	if execution == nil || execution.WorkflowExecutionInfo == nil {
+		// Closed workflows are dropped once the domain's retention passes, so
+		// only a request that should still be running is a problem.
+		if active {
+			inspection.Problems = append(inspection.Problems, fmt.Sprintf("request is %s but Cadence has no workflow for it", request.Status))
+		}
+		inspection.Consistent = len(inspection.Problems) == 0
+		return inspection
+	}
+
+	info := execution.WorkflowExecutionInfo
+	inspection.WorkflowRunID = info.GetExecution().GetRunId()
+	inspection.WorkflowStarted = unixNanoTime(info.StartTime)
+	inspection.WorkflowClosed = unixNanoTime(info.CloseTime)
+	for _, pending := range execution.PendingActivities {
+		inspection.PendingActivities = append(inspection.PendingActivities, pending.GetActivityType().GetName())
+	}
+
+	if info.CloseStatus == nil {
+		inspection.WorkflowStatus = "running"
+	} else {
+		inspection.WorkflowStatus = strings.ToLower(info.CloseStatus.String())
+	}
+
+	if request.RunID != "" && request.RunID != inspection.WorkflowRunID {
+		inspection.Problems = append(inspection.Problems, fmt.Sprintf("latest run %s isn't the recorded run %s", inspection.WorkflowRunID, request.RunID))
+	}
+
+	switch {
+	case active && info.CloseStatus != nil:
+		inspection.Problems = append(inspection.Problems, fmt.Sprintf("request is %s but its workflow %s", request.Status, inspection.WorkflowStatus))
+	case !active && info.CloseStatus == nil:
+		inspection.Problems = append(inspection.Problems, fmt.Sprintf("request is %s but its workflow is still running", request.Status))
+	case (request.Status == "completed" || request.Status == "refunded") && info.GetCloseStatus() != shared.WorkflowExecutionCloseStatusCompleted:
+		inspection.Problems = append(inspection.Problems, fmt.Sprintf("request is %s but its workflow %s", request.Status, inspection.WorkflowStatus))
+	case !active && request.Status != "completed" && request.Status != "refunded" && info.GetCloseStatus() == shared.WorkflowExecutionCloseStatusCompleted:
+		inspection.Problems = append(inspection.Problems, fmt.Sprintf("request is %s but its workflow completed", request.Status))
+	}
+
+	inspection.Consistent = len(inspection.Problems) == 0
+	return inspection
+}
+
+func unixNanoTime(nanos *int64) *time.Time {
+	if nanos == nil || *nanos == 0 {
+		return nil
+	}
+	t := time.Unix(0, *nanos).UTC()
+	return &t
+}
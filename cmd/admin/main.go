@@ -0,0 +1,116 @@
+// Command admin is the operator tool for requests and their workflows. It
+// opens the same database and Cadence domain as the API and worker through the
+// db and deps packages, and the service calls it makes read the same config.
+//
+//	go run ./cmd/admin list -status failed
+//	go run ./cmd/admin show <request id>
+//	go run ./cmd/admin events -follow <request id>
+//	go run ./cmd/admin cancel -reason "wrong recipient" <request id>
+//	go run ./cmd/admin retry -by alice <request id>
+//	go run ./cmd/admin reconcile -request <request id>
+//	go run ./cmd/admin inspect <request id>
+//
+// Every command prints a table, or JSON with -o json before the command.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"mint-redeem-workflow/config"
+	"mint-redeem-workflow/db"
+	"mint-redeem-workflow/deps"
+	"mint-redeem-workflow/infra/cadence"
+	"mint-redeem-workflow/repository"
+	"mint-redeem-workflow/service"
+	"os"
+
+	"go.uber.org/cadence/.gen/go/shared"
+	"gorm.io/gorm"
+)
+
+// WorkflowClient is the part of the Cadence client the commands use.
+type WorkflowClient interface {
+	cadence.WorkflowClient
+	DescribeWorkflowExecution(ctx context.Context, workflowID string, runID string) (*shared.DescribeWorkflowExecutionResponse, error)
+}
+
+// App holds what every command runs against.
+type App struct {
+	DB            *gorm.DB
+	Service       *service.Service
+	CadenceClient WorkflowClient
+	Out           io.Writer
+	JSON          bool
+}
+
+type command struct {
+	usage string
+	run   func(app *App, args []string) error
+}
+
+var commands = map[string]command{
+	"list":      {"list [-type mint|redeem] [-status status] [-limit n]", runList},
+	"show":      {"show <request id>", runShow},
+	"events":    {"events [-follow] [-interval 2s] <request id>", runEvents},
+	"cancel":    {"cancel [-reason text] <request id>", runCancel},
+	"retry":     {"retry [-by name] <request id>", runRetry},
+	"reconcile": {"reconcile [-from time -to time | -request id]", runReconcile},
+	"inspect":   {"inspect <request id>", runInspect},
+}
+
+var commandOrder = []string{"list", "show", "events", "cancel", "retry", "reconcile", "inspect"}
+
+func main() {
+	output := flag.String("o", "table", "output format, table or json")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 || (*output != "table" && *output != "json") {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+
+	db.InitDB()
+
+	cfg, err := config.NewServiceConfig()
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+
+	cadenceConn, err := deps.DialCadence(cfg.CadenceDomain)
+	if err != nil {
+		log.Fatal("Failed to create cadence client:", err)
+	}
+
+	app := &App{
+		DB:            db.Db,
+		Service:       service.New(db.Db, repository.NewGormRequestRepository(db.Db), cadenceConn.Client, cfg),
+		CadenceClient: cadenceConn.Client,
+		Out:           os.Stdout,
+		JSON:          *output == "json",
+	}
+	err = cmd.run(app, flag.Args()[1:])
+	cadenceConn.Close()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: admin [-o table|json] <command> [flags]")
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, name := range commandOrder {
+		fmt.Fprintf(os.Stderr, "  %s\n", commands[name].usage)
+	}
+}
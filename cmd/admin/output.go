@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Table is the tabular form of a command's result.
+type Table struct {
+	Headers []string
+	Rows    [][]string
+}
+
+// Print writes the result as indented JSON, or as the table when the app
+// isn't in JSON mode.
+func (app *App) Print(result interface{}, table Table) error {
+	if app.JSON {
+		encoder := json.NewEncoder(app.Out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	writer := tabwriter.NewWriter(app.Out, 0, 0, 2, ' ', 0)
+	if len(table.Headers) > 0 {
+		fmt.Fprintln(writer, strings.Join(table.Headers, "\t"))
+	}
+	for _, row := range table.Rows {
+		fmt.Fprintln(writer, strings.Join(row, "\t"))
+	}
+
+	return writer.Flush()
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatAmount(amount float64) string {
+	return fmt.Sprintf("%f", amount)
+}
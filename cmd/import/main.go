@@ -0,0 +1,227 @@
+// Command import submits a CSV file of mints and redeems.
+//
+// The file needs a header with the columns type, amount, recipient, token,
+// chain and client_reference. Every row is validated before anything is
+// submitted. Rows whose client reference was already imported are skipped, so
+// a partially imported file can be run again. A row that doesn't match the
+// request its client reference was imported as is reported as failed. A row that was saved but never
+// got a workflow, e.g. because Cadence was down, has its workflow started.
+//
+//	go run ./cmd/import -file mints.csv -dry-run
+//	go run ./cmd/import -file mints.csv -submitter treasury
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"mint-redeem-workflow/config"
+	"mint-redeem-workflow/db"
+	"mint-redeem-workflow/deps"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/repository"
+	"mint-redeem-workflow/service"
+	"mint-redeem-workflow/worker/workflows"
+	"os"
+	"strings"
+)
+
+func main() {
+	file := flag.String("file", "", "path of the csv file to import")
+	dryRun := flag.Bool("dry-run", false, "validate the file and print totals without submitting anything")
+	yes := flag.Bool("yes", false, "submit without asking for confirmation")
+	submitter := flag.String("submitter", "", "submitter recorded on every request")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.NewServiceConfig()
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatal("Failed to open file:", err)
+	}
+	defer f.Close()
+
+	rows, errs := ReadRows(f, cfg)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		fmt.Fprintf(os.Stderr, "%d invalid rows, nothing was submitted\n", len(errs))
+		os.Exit(1)
+	}
+
+	printTotals(os.Stdout, rows)
+	if *dryRun || len(rows) == 0 {
+		return
+	}
+
+	if !*yes && !confirm(os.Stdin, os.Stdout, len(rows)) {
+		fmt.Println("Aborted, nothing was submitted")
+		return
+	}
+
+	db.InitDB()
+
+	cadenceConn, err := deps.DialCadence(cfg.CadenceDomain)
+	if err != nil {
+		log.Fatal("Failed to create cadence client:", err)
+	}
+
+	result := Submit(service.New(db.Db, repository.NewGormRequestRepository(db.Db), cadenceConn.Client, cfg), cfg, rows, *submitter, os.Stdout)
+	cadenceConn.Close()
+	fmt.Printf("%d submitted, %d resumed, %d already imported, %d failed\n", result.Submitted, result.Resumed, result.Skipped, result.Failed)
+	if result.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func printTotals(out io.Writer, rows []Row) {
+	fmt.Fprintf(out, "%d rows\n", len(rows))
+	for _, total := range Totals(rows) {
+		fmt.Fprintf(out, "  %-6s %-5s %-9s %4d rows  %f\n", total.Type, total.Token, total.Chain, total.Count, total.Amount)
+	}
+}
+
+func confirm(in io.Reader, out io.Writer, count int) bool {
+	fmt.Fprintf(out, "Submit %d rows? [y/N] ", count)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes"
+}
+
+// Service has the service calls the import makes, so that tests can hand
+// Submit a fake.
+type Service interface {
+	ProcessRequest(request *models.Request, workflowParam workflows.RequestInput) error
+	StartRequest(request *models.Request, workflowParam workflows.RequestInput) error
+	FindByClientReference(clientReference string) (*models.Request, error)
+}
+
+type SubmitResult struct {
+	Submitted int
+	Resumed   int
+	Skipped   int
+	Failed    int
+}
+
+// Submit sends each row through the same service calls as the API. A row
+// that fails is reported and doesn't stop the rest of the file.
+func Submit(svc Service, cfg *config.ServiceConfig, rows []Row, submitter string, out io.Writer) SubmitResult {
+	var result SubmitResult
+	for _, row := range rows {
+		request, err := submitRow(svc, cfg, row, submitter)
+		switch {
+		case errors.Is(err, service.ErrDuplicateClientReference):
+			resumeRow(svc, cfg, row, &result, out)
+		case err != nil:
+			result.Failed++
+			fmt.Fprintf(out, "line %d: %s failed: %v\n", row.Line, row.ClientReference, err)
+		default:
+			result.Submitted++
+			fmt.Fprintf(out, "line %d: %s submitted as %s\n", row.Line, row.ClientReference, request.ID)
+		}
+	}
+
+	return result
+}
+
+// resumeRow handles a row whose client reference was already imported. The
+// earlier run may have saved the request and then failed to start its
+// workflow, so a request without a run ID has its workflow started now. A
+// request that differs from the row is left alone and the row fails.
+func resumeRow(svc Service, cfg *config.ServiceConfig, row Row, result *SubmitResult, out io.Writer) {
+	existing, err := svc.FindByClientReference(row.ClientReference)
+	if err != nil {
+		result.Failed++
+		fmt.Fprintf(out, "line %d: %s already imported, but looking it up failed: %v\n", row.Line, row.ClientReference, err)
+		return
+	}
+
+	if mismatches := mismatches(row, existing); len(mismatches) > 0 {
+		result.Failed++
+		fmt.Fprintf(out, "line %d: %s was already imported as %s with a different %s\n", row.Line, row.ClientReference, existing.ID, strings.Join(mismatches, ", "))
+		return
+	}
+
+	err = svc.StartRequest(existing, workflowInput(cfg, existing))
+	switch {
+	case errors.Is(err, service.ErrWorkflowStarted):
+		result.Skipped++
+		fmt.Fprintf(out, "line %d: %s already imported as %s (%s)\n", row.Line, row.ClientReference, existing.ID, existing.Status)
+	case err != nil:
+		result.Failed++
+		fmt.Fprintf(out, "line %d: %s was imported as %s without a workflow, starting it failed: %v\n", row.Line, row.ClientReference, existing.ID, err)
+	default:
+		result.Resumed++
+		fmt.Fprintf(out, "line %d: %s was imported as %s without a workflow, started it\n", row.Line, row.ClientReference, existing.ID)
+	}
+}
+
+// mismatches lists the fields in which the row differs from the request its
+// client reference was imported as.
+func mismatches(row Row, request *models.Request) []string {
+	var fields []string
+	if row.Type != request.Type {
+		fields = append(fields, fmt.Sprintf("type (%s, not %s)", request.Type, row.Type))
+	}
+	if row.Amount != request.Amount {
+		fields = append(fields, fmt.Sprintf("amount (%v, not %v)", request.Amount, row.Amount))
+	}
+	if row.Recipient != request.Recipient {
+		fields = append(fields, fmt.Sprintf("recipient (%s, not %s)", request.Recipient, row.Recipient))
+	}
+	if row.Token != request.Token {
+		fields = append(fields, fmt.Sprintf("token (%s, not %s)", request.Token, row.Token))
+	}
+	if row.Chain != request.Chain {
+		fields = append(fields, fmt.Sprintf("chain (%s, not %s)", request.Chain, row.Chain))
+	}
+
+	return fields
+}
+
+func submitRow(svc Service, cfg *config.ServiceConfig, row Row, submitter string) (*models.Request, error) {
+	clientReference := row.ClientReference
+	request := models.Request{
+		Type:            row.Type,
+		Amount:          row.Amount,
+		Recipient:       row.Recipient,
+		Token:           row.Token,
+		Chain:           row.Chain,
+		Submitter:       submitter,
+		ClientReference: &clientReference,
+	}
+
+	return &request, svc.ProcessRequest(&request, workflowInput(cfg, &request))
+}
+
+func workflowInput(cfg *config.ServiceConfig, request *models.Request) workflows.RequestInput {
+	requiredApprovals := cfg.RequiredApprovals(request.Token, request.Amount)
+	approvalTimeout := cfg.ApprovalTimeout
+	if requiredApprovals == 0 {
+		approvalTimeout = 0
+	}
+
+	return workflows.RequestInput{
+		Type:              request.Type,
+		Amount:            request.Amount,
+		Recipient:         request.Recipient,
+		Token:             request.Token,
+		Chain:             request.Chain,
+		Submitter:         request.Submitter,
+		RequiredApprovals: requiredApprovals,
+		ApprovalTimeout:   approvalTimeout,
+	}
+}
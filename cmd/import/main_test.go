@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"mint-redeem-workflow/config"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/service"
+	"mint-redeem-workflow/worker/workflows"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeService struct {
+	processRequest        func(request *models.Request, workflowParam workflows.RequestInput) error
+	startRequest          func(request *models.Request, workflowParam workflows.RequestInput) error
+	findByClientReference func(clientReference string) (*models.Request, error)
+}
+
+func (f *fakeService) StartRequest(request *models.Request, workflowParam workflows.RequestInput) error {
+	return f.startRequest(request, workflowParam)
+}
+
+func (f *fakeService) ProcessRequest(request *models.Request, workflowParam workflows.RequestInput) error {
+	return f.processRequest(request, workflowParam)
+}
+
+func (f *fakeService) FindByClientReference(clientReference string) (*models.Request, error) {
+	return f.findByClientReference(clientReference)
+}
+
+func TestSubmit_ReportsEachRow(t *testing.T) {
+	cfg, _ := config.NewServiceConfig()
+	existingID := uuid.New()
+	savedID := uuid.New()
+	conflictingID := uuid.New()
+	svc := &fakeService{
+		processRequest: func(request *models.Request, workflowParam workflows.RequestInput) error {
+			switch *request.ClientReference {
+			case "ref-2", "ref-4", "ref-5":
+				return service.ErrDuplicateClientReference
+			case "ref-3":
+				return errors.New("cadence unavailable")
+			}
+			request.ID = uuid.New()
+			assert.Equal(t, "treasury", workflowParam.Submitter)
+			return nil
+		},
+		startRequest: func(request *models.Request, workflowParam workflows.RequestInput) error {
+			if request.RunID != "" {
+				return service.ErrWorkflowStarted
+			}
+			assert.Equal(t, "treasury", workflowParam.Submitter)
+			return nil
+		},
+		findByClientReference: func(clientReference string) (*models.Request, error) {
+			switch clientReference {
+			case "ref-4":
+				return &models.Request{ID: savedID, Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum", Submitter: "treasury", Status: "pending"}, nil
+			case "ref-5":
+				return &models.Request{ID: conflictingID, Type: "mint", Amount: 10, Recipient: "0xdeadbeef", Token: "USDC", Chain: "ethereum", Submitter: "treasury", Status: "pending"}, nil
+			}
+			return &models.Request{ID: existingID, Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum", Status: "completed", RunID: "run-1"}, nil
+		},
+	}
+	rows := []Row{
+		{Line: 2, Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum", ClientReference: "ref-1"},
+		{Line: 3, Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum", ClientReference: "ref-2"},
+		{Line: 4, Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum", ClientReference: "ref-3"},
+		{Line: 5, Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum", ClientReference: "ref-4"},
+		{Line: 6, Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum", ClientReference: "ref-5"},
+	}
+	var out bytes.Buffer
+
+	result := Submit(svc, cfg, rows, "treasury", &out)
+
+	assert.Equal(t, SubmitResult{Submitted: 1, Resumed: 1, Skipped: 1, Failed: 2}, result)
+	assert.Contains(t, out.String(), "line 3: ref-2 already imported as "+existingID.String()+" (completed)")
+	assert.Contains(t, out.String(), "line 4: ref-3 failed: cadence unavailable")
+	assert.Contains(t, out.String(), "line 5: ref-4 was imported as "+savedID.String()+" without a workflow, started it")
+	assert.Contains(t, out.String(), "line 6: ref-5 was already imported as "+conflictingID.String()+" with a different recipient (0xdeadbeef, not 0xnotdeadbeef)")
+}
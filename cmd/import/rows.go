@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mint-redeem-workflow/config"
+	"mint-redeem-workflow/operations"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var columns = []string{"type", "amount", "recipient", "token", "chain", "client_reference"}
+
+// Row is one validated line of an import file.
+type Row struct {
+	Line            int
+	Type            string
+	Amount          float64
+	Recipient       string
+	Token           string
+	Chain           string
+	ClientReference string
+}
+
+// RowError is a problem with one line of an import file.
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// ReadRows parses and validates every line of the file. It returns all the
+// problems it finds rather than stopping at the first one.
+func ReadRows(r io.Reader, cfg *config.ServiceConfig) ([]Row, []error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, []error{RowError{Line: 1, Err: fmt.Errorf("reading header: %w", err)}}
+	}
+
+	index := map[string]int{}
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	var missing []string
+	for _, column := range columns {
+		if _, ok := index[column]; !ok {
+			missing = append(missing, column)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, []error{RowError{Line: 1, Err: fmt.Errorf("missing columns: %s", strings.Join(missing, ", "))}}
+	}
+
+	var rows []Row
+	var errs []error
+	seen := map[string]int{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// FieldPos can't be used on a record that failed to parse.
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				errs = append(errs, err)
+				break
+			}
+			errs = append(errs, RowError{Line: parseErr.Line, Err: err})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		field := func(name string) string {
+			return strings.TrimSpace(record[index[name]])
+		}
+
+		row, err := validateRow(cfg, line, field)
+		if err != nil {
+			errs = append(errs, RowError{Line: line, Err: err})
+			continue
+		}
+
+		if first, ok := seen[row.ClientReference]; ok {
+			errs = append(errs, RowError{Line: line, Err: fmt.Errorf("client reference %s is already used on line %d", row.ClientReference, first)})
+			continue
+		}
+		seen[row.ClientReference] = line
+
+		rows = append(rows, row)
+	}
+
+	return rows, errs
+}
+
+func validateRow(cfg *config.ServiceConfig, line int, field func(string) string) (Row, error) {
+	row := Row{
+		Line:            line,
+		Type:            strings.ToLower(field("type")),
+		Recipient:       field("recipient"),
+		Token:           field("token"),
+		Chain:           field("chain"),
+		ClientReference: field("client_reference"),
+	}
+
+	op, err := operations.Lookup(row.Type)
+	if err != nil {
+		return row, err
+	}
+
+	amount, err := strconv.ParseFloat(field("amount"), 64)
+	if err != nil {
+		return row, fmt.Errorf("amount %q is not a number", field("amount"))
+	}
+	row.Amount = amount
+
+	if row.Recipient == "" {
+		return row, fmt.Errorf("recipient is required")
+	}
+	if row.ClientReference == "" {
+		return row, fmt.Errorf("client reference is required")
+	}
+
+	_, err = op.ValidateRequest(cfg, operations.Params{
+		Amount:    row.Amount,
+		Recipient: row.Recipient,
+		Token:     row.Token,
+		Chain:     row.Chain,
+	})
+
+	return row, err
+}
+
+// Total is the number and sum of rows of one type, token and chain.
+type Total struct {
+	Type   string
+	Token  string
+	Chain  string
+	Count  int
+	Amount float64
+}
+
+// Totals groups the rows by type, token and chain.
+func Totals(rows []Row) []Total {
+	byKey := map[string]*Total{}
+	for _, row := range rows {
+		key := row.Type + "/" + row.Token + "/" + row.Chain
+		total, ok := byKey[key]
+		if !ok {
+			total = &Total{Type: row.Type, Token: row.Token, Chain: row.Chain}
+			byKey[key] = total
+		}
+		total.Count++
+		total.Amount += row.Amount
+	}
+
+	totals := make([]Total, 0, len(byKey))
+	for _, total := range byKey {
+		totals = append(totals, *total)
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Type != totals[j].Type {
+			return totals[i].Type < totals[j].Type
+		}
+		if totals[i].Token != totals[j].Token {
+			return totals[i].Token < totals[j].Token
+		}
+		return totals[i].Chain < totals[j].Chain
+	})
+
+	return totals
+}
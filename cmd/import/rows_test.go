@@ -0,0 +1,91 @@
+package main
+
+import (
+	"mint-redeem-workflow/config"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadRows_ValidFileReturnsRows(t *testing.T) {
+	cfg, _ := config.NewServiceConfig()
+	file := `type,amount,recipient,token,chain,client_reference
+mint,10.5,0xnotdeadbeef,USDC,ethereum,ref-1
+redeem,20,0xnotdeadbeef,SBC,base,ref-2
+`
+
+	rows, errs := ReadRows(strings.NewReader(file), cfg)
+
+	assert.Empty(t, errs)
+	assert.Equal(t, []Row{
+		{Line: 2, Type: "mint", Amount: 10.5, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum", ClientReference: "ref-1"},
+		{Line: 3, Type: "redeem", Amount: 20, Recipient: "0xnotdeadbeef", Token: "SBC", Chain: "base", ClientReference: "ref-2"},
+	}, rows)
+}
+
+func TestReadRows_InvalidRowsReturnLineNumberedErrors(t *testing.T) {
+	cfg, _ := config.NewServiceConfig()
+	file := `type,amount,recipient,token,chain,client_reference
+mint,10.5,0xnotdeadbeef,USDC,ethereum,ref-1
+burn,10.5,0xnotdeadbeef,USDC,ethereum,ref-2
+mint,ten,0xnotdeadbeef,USDC,ethereum,ref-3
+mint,10.555,0xnotdeadbeef,SBC,base,ref-4
+mint,10,0xnotdeadbeef,USDC,dogechain,ref-5
+mint,10,0xnotdeadbeef,USDC,ethereum,ref-1
+mint,10,0xnotdeadbeef,USDC,ethereum,
+`
+
+	rows, errs := ReadRows(strings.NewReader(file), cfg)
+
+	assert.Len(t, rows, 1)
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	assert.Equal(t, []string{
+		"line 3: type must be mint or redeem",
+		`line 4: amount "ten" is not a number`,
+		"line 5: amount 10.555 exceeds 2 decimal places for SBC on base",
+		"line 6: unsupported token USDC on chain dogechain",
+		"line 7: client reference ref-1 is already used on line 2",
+		"line 8: client reference is required",
+	}, messages)
+}
+
+func TestReadRows_MalformedQuoteReturnsLineError(t *testing.T) {
+	cfg, _ := config.NewServiceConfig()
+	file := `type,amount,recipient,token,chain,client_reference
+mint,10.5,0xnotdeadbeef,USDC,ethereum,ref-1
+"mint,10.5,0xnotdeadbeef,USDC,ethereum,ref-2
+`
+
+	rows, errs := ReadRows(strings.NewReader(file), cfg)
+
+	assert.Len(t, rows, 1)
+	if assert.Len(t, errs, 1) {
+		assert.Contains(t, errs[0].Error(), "line 3: ")
+	}
+}
+
+func TestReadRows_MissingColumnsReturnsHeaderError(t *testing.T) {
+	cfg, _ := config.NewServiceConfig()
+
+	_, errs := ReadRows(strings.NewReader("type,amount,recipient\n"), cfg)
+
+	assert.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "line 1: missing columns: token, chain, client_reference")
+}
+
+func TestTotals_GroupsByTypeTokenAndChain(t *testing.T) {
+	rows := []Row{
+		{Type: "mint", Amount: 10, Token: "USDC", Chain: "ethereum"},
+		{Type: "redeem", Amount: 5, Token: "USDC", Chain: "ethereum"},
+		{Type: "mint", Amount: 2.5, Token: "USDC", Chain: "ethereum"},
+	}
+
+	assert.Equal(t, []Total{
+		{Type: "mint", Token: "USDC", Chain: "ethereum", Count: 2, Amount: 12.5},
+		{Type: "redeem", Token: "USDC", Chain: "ethereum", Count: 1, Amount: 5},
+	}, Totals(rows))
+}
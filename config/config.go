@@ -1,7 +1,53 @@
 package config
 
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
 type ServiceConfig struct {
-	BraleAuth string
+	BraleAuth                string
+	SupportedAssets          []Asset
+	SanctionsListPath        string
+	SanctionsRefreshInterval time.Duration
+	ApprovalTiers            []ApprovalTier
+	ApprovalTimeout          time.Duration
+	MaxBatchSize             int
+	BatchConcurrency         int
+	MaxExecuteAhead          time.Duration
+	MaxRequestAttempts       int
+	CadenceDomain            string
+	CadenceTaskList          string
+	CadenceDomainRetention   time.Duration
+	RegisterCadenceDomain    bool
+	WebhookDispatchInterval  time.Duration
+	APIClients               []APIClient
+}
+
+// APIClient is a caller of the API. KeyHash is the hex SHA-256 of the key it
+// sends as a bearer token. Operators can see and manage every client's
+// webhooks, while other clients only get their own.
+type APIClient struct {
+	Name     string
+	KeyHash  string
+	Operator bool
+}
+
+// ApprovalTier requires Approvers distinct approvals for amounts above
+// MinAmount. An empty Token applies the tier to every token.
+type ApprovalTier struct {
+	Token     string
+	MinAmount float64
+	Approvers int
+}
+
+// Asset is a supported (token, chain) pair. Token maps to the Brale value
+// type and Chain to the Brale transfer type.
+type Asset struct {
+	Token    string
+	Chain    string
+	Decimals int
 }
 
 func NewServiceConfig() (*ServiceConfig, error) {
@@ -9,5 +55,87 @@ func NewServiceConfig() (*ServiceConfig, error) {
 
 	return &ServiceConfig{
 		BraleAuth: jwt,
+		SupportedAssets: []Asset{
+			{Token: "USDC", Chain: "ethereum", Decimals: 6},
+			{Token: "USDC", Chain: "polygon", Decimals: 6},
+			{Token: "USDC", Chain: "base", Decimals: 6},
+			{Token: "SBC", Chain: "base", Decimals: 2},
+		},
+		SanctionsListPath:        "sdn.csv",
+		SanctionsRefreshInterval: time.Hour,
+		ApprovalTiers: []ApprovalTier{
+			{MinAmount: 10000, Approvers: 1},
+			{MinAmount: 1000000, Approvers: 2},
+			{Token: "SBC", MinAmount: 250000, Approvers: 2},
+		},
+		ApprovalTimeout:         time.Hour * 24,
+		MaxBatchSize:            100,
+		BatchConcurrency:        5,
+		MaxExecuteAhead:         time.Hour * 24 * 30,
+		MaxRequestAttempts:      5,
+		CadenceDomain:           "test-domain2",
+		CadenceTaskList:         "test-worker",
+		CadenceDomainRetention:  time.Hour * 24,
+		RegisterCadenceDomain:   true,
+		WebhookDispatchInterval: time.Second,
+		APIClients: []APIClient{
+			// The keys are "acme-dev-key" and "operator-dev-key".
+			{Name: "acme", KeyHash: "7f2e6802f17f6ba210bb387707340e4fa6d03de0347ddcc5dd4ece4439694fce"},
+			{Name: "operator", KeyHash: "5de520b94f3f4e3d2493273dbaebaceb2ec274809fe23cf2bbaba7bc1451aae6", Operator: true},
+		},
 	}, nil
 }
+
+// LookupAsset returns the configured asset for the token and chain pair.
+func (c *ServiceConfig) LookupAsset(token string, chain string) (*Asset, error) {
+	for _, asset := range c.SupportedAssets {
+		if asset.Token == token && asset.Chain == chain {
+			return &asset, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported token %s on chain %s", token, chain)
+}
+
+// RequiredApprovals returns how many distinct approvers the amount needs. It is
+// the highest count of all the tiers the token and amount fall in.
+func (c *ServiceConfig) RequiredApprovals(token string, amount float64) int {
+	required := 0
+	for _, tier := range c.ApprovalTiers {
+		if tier.Token != "" && tier.Token != token {
+			continue
+		}
+		if amount > tier.MinAmount && tier.Approvers > required {
+			required = tier.Approvers
+		}
+	}
+
+	return required
+}
+
+// ValidateAmount checks the amount is positive and fits the asset's precision.
+func (a *Asset) ValidateAmount(amount float64) error {
+	if amount <= 0 {
+		return fmt.Errorf("amount must be greater than zero")
+	}
+
+	scaled := amount * math.Pow10(a.Decimals)
+	if math.Abs(scaled-math.Round(scaled)) > 1e-6 {
+		return fmt.Errorf("amount %v exceeds %d decimal places for %s on %s", amount, a.Decimals, a.Token, a.Chain)
+	}
+
+	return nil
+}
+
+// ValidateExecuteAt checks a future execute_at time is after now and no further
+// ahead of submittedAt than MaxExecuteAhead.
+func (c *ServiceConfig) ValidateExecuteAt(executeAt time.Time, submittedAt time.Time, now time.Time) error {
+	if !executeAt.After(now) {
+		return fmt.Errorf("execute_at must be in the future")
+	}
+	if executeAt.After(submittedAt.Add(c.MaxExecuteAhead)) {
+		return fmt.Errorf("execute_at can't be more than %s after the request was submitted", c.MaxExecuteAhead)
+	}
+
+	return nil
+}
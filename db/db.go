@@ -2,6 +2,7 @@ package db
 
 import (
 	"log"
+	"mint-redeem-workflow/ledger"
 	"mint-redeem-workflow/models"
 
 	"gorm.io/driver/sqlite"
@@ -10,12 +11,38 @@ import (
 
 var Db *gorm.DB
 
+// InitDB opens mint-redeem.db in the working directory.
 func InitDB() {
+	initDB("mint-redeem.db")
+}
+
+// InitMemoryDB opens a database that only lives in memory, so nothing is
+// written to disk and everything is gone when the process exits.
+func InitMemoryDB() {
+	initDB("file::memory:?cache=shared")
+}
+
+func initDB(dsn string) {
 	var err error
-	Db, err = gorm.Open(sqlite.Open("mint-redeem.db"), &gorm.Config{})
+	Db, err = gorm.Open(sqlite.Open(dsn), &gorm.Config{})
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
-	Db.AutoMigrate(&models.Request{})
+	Db.AutoMigrate(&models.Request{}, &models.Approval{}, &models.ApproverKey{}, &models.RequestEvent{}, &models.Batch{}, &models.Schedule{}, &models.ReconciliationReport{}, &models.ReconciliationItem{}, &models.Webhook{}, &models.WebhookDelivery{}, &models.RequestAttempt{}, &models.EventCursor{})
+
+	// Deliveries used to be unique per status of a request, which kept a
+	// retried request from notifying its webhooks again.
+	for _, index := range []string{"idx_webhook_deliveries_event", "idx_webhook_deliveries_attempt"} {
+		if !Db.Migrator().HasIndex(&models.WebhookDelivery{}, index) {
+			continue
+		}
+		if err := Db.Migrator().DropIndex(&models.WebhookDelivery{}, index); err != nil {
+			log.Fatal("Failed to drop old webhook delivery index:", err)
+		}
+	}
+
+	if err := ledger.Migrate(Db); err != nil {
+		log.Fatal("Failed to migrate ledger:", err)
+	}
 }
@@ -14,9 +14,8 @@ import (
 )
 
 type Dependencies struct {
-	Config        config.ServiceConfig
-	BraleClient   brale.BraleClient
-	CadenceClient workflowserviceclient.Interface
+	Config      *config.ServiceConfig
+	BraleClient brale.BraleClient
 }
 
 const (
@@ -25,21 +24,35 @@ const (
 	hostPort       = "127.0.0.1:7833"
 )
 
-func NewDependencies() (*Dependencies, error) {
-	cfg, err := config.NewServiceConfig()
-	if err != nil {
-		return nil, err
-	}
-
+func NewDependencies(cfg *config.ServiceConfig) *Dependencies {
 	braleClient := brale.NewMockBraleClient()
 
 	return &Dependencies{
-		Config:      *cfg,
+		Config:      cfg,
 		BraleClient: braleClient,
-	}, nil
+	}
+}
+
+// Cadence is a connection to the Cadence frontend. Its dispatcher holds the
+// gRPC connection and the goroutines serving it, so a process dials once at
+// startup, shares the connection and closes it on shutdown.
+type Cadence struct {
+	// Service is what workers poll with.
+	Service workflowserviceclient.Interface
+
+	// Client starts, signals and queries workflows in the domain.
+	Client client.Client
+
+	dispatcher *yarpc.Dispatcher
 }
 
-func BuildCadenceServiceClient() (workflowserviceclient.Interface, error) {
+// DialCadence starts a connection to the local Cadence frontend, with a client
+// for the domain.
+func DialCadence(domain string) (*Cadence, error) {
+	return dialCadence(hostPort, domain)
+}
+
+func dialCadence(hostPort string, domain string) (*Cadence, error) {
 	dispatcher := yarpc.NewDispatcher(yarpc.Config{
 		Name: clientName,
 		Outbounds: yarpc.Outbounds{
@@ -51,21 +64,21 @@ func BuildCadenceServiceClient() (workflowserviceclient.Interface, error) {
 	}
 
 	clientConfig := dispatcher.ClientConfig(cadenceService)
-
-	return compatibility.NewThrift2ProtoAdapter(
+	service := compatibility.NewThrift2ProtoAdapter(
 		apiv1.NewDomainAPIYARPCClient(clientConfig),
 		apiv1.NewWorkflowAPIYARPCClient(clientConfig),
 		apiv1.NewWorkerAPIYARPCClient(clientConfig),
 		apiv1.NewVisibilityAPIYARPCClient(clientConfig),
-	), nil
-}
+	)
 
-func BuildCadenceClient() (client.Client, error) {
-	service, err := BuildCadenceServiceClient()
-	if err != nil {
-		return nil, err
-	}
+	return &Cadence{
+		Service:    service,
+		Client:     client.NewClient(service, domain, &client.Options{MetricsScope: tally.NoopScope}),
+		dispatcher: dispatcher,
+	}, nil
+}
 
-	return client.NewClient(
-		service, "test-domain2", &client.Options{MetricsScope: tally.NoopScope}), nil
+// Close stops the dispatcher, closing its connection. Calls in flight fail.
+func (c *Cadence) Close() error {
+	return c.dispatcher.Stop()
 }
@@ -0,0 +1,97 @@
+package deps
+
+import (
+	"context"
+	"net"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "github.com/uber/cadence-idl/go/proto/api/v1"
+	"go.uber.org/cadence/client"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/transport/grpc"
+)
+
+// frontend answers StartWorkflowExecution like the Cadence frontend, so
+// clients can be load tested without a Cadence cluster.
+type frontend struct {
+	apiv1.WorkflowAPIYARPCServer
+}
+
+func (frontend) StartWorkflowExecution(ctx context.Context, req *apiv1.StartWorkflowExecutionRequest) (*apiv1.StartWorkflowExecutionResponse, error) {
+	return &apiv1.StartWorkflowExecutionResponse{RunId: "run-id"}, nil
+}
+
+func startFrontend(tb testing.TB) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(tb, err)
+
+	dispatcher := yarpc.NewDispatcher(yarpc.Config{
+		Name:     cadenceService,
+		Inbounds: yarpc.Inbounds{grpc.NewTransport().NewInbound(listener)},
+	})
+	dispatcher.Register(apiv1.BuildWorkflowAPIYARPCProcedures(frontend{}))
+	require.NoError(tb, dispatcher.Start())
+	tb.Cleanup(func() { dispatcher.Stop() })
+
+	return listener.Addr().String()
+}
+
+func startWorkflow(tb testing.TB, workflowClient client.Client) {
+	_, err := workflowClient.StartWorkflow(context.Background(), client.StartWorkflowOptions{
+		ID:                           "request-id",
+		TaskList:                     "test-worker",
+		ExecutionStartToCloseTimeout: time.Minute,
+	}, "RequestWorkflow")
+	require.NoError(tb, err)
+}
+
+func TestCadence_CloseReleasesConnection(t *testing.T) {
+	addr := startFrontend(t)
+
+	conn, err := dialCadence(addr, "test-domain2")
+	require.NoError(t, err)
+	startWorkflow(t, conn.Client)
+	outbound := conn.dispatcher.ClientConfig(cadenceService).GetUnaryOutbound()
+	assert.True(t, outbound.IsRunning())
+
+	require.NoError(t, conn.Close())
+
+	assert.False(t, outbound.IsRunning())
+}
+
+// BenchmarkStartWorkflow_DialPerRequest starts each workflow the way the API
+// used to, with a new connection that is never closed.
+func BenchmarkStartWorkflow_DialPerRequest(b *testing.B) {
+	addr := startFrontend(b)
+	goroutines := runtime.NumGoroutine()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		conn, err := dialCadence(addr, "test-domain2")
+		require.NoError(b, err)
+		startWorkflow(b, conn.Client)
+	}
+	b.StopTimer()
+
+	b.ReportMetric(float64(runtime.NumGoroutine()-goroutines)/float64(b.N), "leaked-goroutines/op")
+}
+
+// BenchmarkStartWorkflow_SharedConnection starts workflows from parallel
+// requests over one connection.
+func BenchmarkStartWorkflow_SharedConnection(b *testing.B) {
+	addr := startFrontend(b)
+	conn, err := dialCadence(addr, "test-domain2")
+	require.NoError(b, err)
+	defer conn.Close()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			startWorkflow(b, conn.Client)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+// Package events records request status transitions in the request event
+// history and reads them back in order for streaming.
+package events
+
+import (
+	"encoding/json"
+	"mint-redeem-workflow/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TypeStatusChanged is the request event type of a status transition.
+const TypeStatusChanged = "status_changed"
+
+const defaultLimit = 100
+
+// StatusChange is the data of a status_changed event. From is empty for the
+// status a request is created with.
+type StatusChange struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Transition is a status_changed event joined with the request it belongs to.
+type Transition struct {
+	EventID     uint
+	RequestID   uuid.UUID
+	RequestType string
+	Submitter   string
+	StatusChange
+	CreatedAt time.Time
+}
+
+// Filter narrows Transitions. Empty fields match everything, and Status
+// matches the status moved to.
+type Filter struct {
+	Submitter string
+	Type      string
+	Status    string
+	AfterID   uint
+	Limit     int
+}
+
+// RecordStatusChange adds a status_changed event to the request's history.
+// Nothing is recorded when the status didn't change, so a retried update
+// doesn't record the transition twice.
+func RecordStatusChange(tx *gorm.DB, requestID uuid.UUID, from string, to string, reason string) error {
+	if from == to {
+		return nil
+	}
+
+	data, err := json.Marshal(StatusChange{From: from, To: to, Reason: reason})
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(&models.RequestEvent{
+		RequestID: requestID,
+		Type:      TypeStatusChanged,
+		Data:      string(data),
+	}).Error
+}
+
+// Transitions returns the status transitions after filter.AfterID, oldest
+// first, along with the ID of the last event read. Event IDs only grow, so a
+// reader that passes that ID back gets every later transition exactly once,
+// including past events the status filter skipped.
+func Transitions(db *gorm.DB, filter Filter) ([]Transition, uint, error) {
+	query := db.Table("request_events").
+		Select("request_events.id, request_events.request_id, request_events.data, request_events.created_at, requests.type, requests.submitter").
+		Joins("JOIN requests ON requests.id = request_events.request_id").
+		Where("request_events.type = ? AND request_events.id > ?", TypeStatusChanged, filter.AfterID).
+		Order("request_events.id")
+	if filter.Submitter != "" {
+		query = query.Where("requests.submitter = ?", filter.Submitter)
+	}
+	if filter.Type != "" {
+		query = query.Where("requests.type = ?", filter.Type)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 || limit > defaultLimit {
+		limit = defaultLimit
+	}
+
+	rows, err := query.Limit(limit).Rows()
+	if err != nil {
+		return nil, filter.AfterID, err
+	}
+	defer rows.Close()
+
+	lastID := filter.AfterID
+	var transitions []Transition
+	for rows.Next() {
+		var transition Transition
+		var data string
+		if err := rows.Scan(&transition.EventID, &transition.RequestID, &data, &transition.CreatedAt, &transition.RequestType, &transition.Submitter); err != nil {
+			return nil, lastID, err
+		}
+		if err := json.Unmarshal([]byte(data), &transition.StatusChange); err != nil {
+			return nil, lastID, err
+		}
+		lastID = transition.EventID
+		if filter.Status != "" && transition.To != filter.Status {
+			continue
+		}
+		transitions = append(transitions, transition)
+	}
+
+	return transitions, lastID, rows.Err()
+}
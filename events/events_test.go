@@ -0,0 +1,73 @@
+package events
+
+import (
+	"mint-redeem-workflow/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Request{}, &models.RequestEvent{}))
+
+	return db
+}
+
+func createRequest(t *testing.T, db *gorm.DB, requestType string, submitter string) models.Request {
+	request := models.Request{Type: requestType, Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", Submitter: submitter}
+	require.NoError(t, db.Create(&request).Error)
+
+	return request
+}
+
+func TestRecordStatusChange_SameStatusIsNotRecorded(t *testing.T) {
+	db := openTestDB(t)
+	request := createRequest(t, db, "mint", "acme")
+
+	require.NoError(t, RecordStatusChange(db, request.ID, "", "pending", ""))
+	require.NoError(t, RecordStatusChange(db, request.ID, "pending", "pending", ""))
+	require.NoError(t, RecordStatusChange(db, request.ID, "pending", "blocked", "sanctioned"))
+
+	transitions, _, err := Transitions(db, Filter{})
+	require.NoError(t, err)
+	require.Len(t, transitions, 2)
+	assert.Equal(t, StatusChange{From: "", To: "pending"}, transitions[0].StatusChange)
+	assert.Equal(t, StatusChange{From: "pending", To: "blocked", Reason: "sanctioned"}, transitions[1].StatusChange)
+	assert.Equal(t, "mint", transitions[1].RequestType)
+	assert.Equal(t, "acme", transitions[1].Submitter)
+}
+
+func TestTransitions_FiltersAndResumesAfterLastID(t *testing.T) {
+	db := openTestDB(t)
+	mint := createRequest(t, db, "mint", "acme")
+	redeem := createRequest(t, db, "redeem", "acme")
+	other := createRequest(t, db, "mint", "globex")
+
+	require.NoError(t, RecordStatusChange(db, mint.ID, "", "started", ""))
+	require.NoError(t, RecordStatusChange(db, redeem.ID, "", "started", ""))
+	require.NoError(t, RecordStatusChange(db, other.ID, "", "started", ""))
+	require.NoError(t, RecordStatusChange(db, mint.ID, "started", "completed", ""))
+
+	transitions, lastID, err := Transitions(db, Filter{Submitter: "acme", Type: "mint", Status: "completed"})
+	require.NoError(t, err)
+	require.Len(t, transitions, 1)
+	assert.Equal(t, mint.ID, transitions[0].RequestID)
+	assert.Equal(t, transitions[0].EventID, lastID)
+
+	transitions, lastID, err = Transitions(db, Filter{Submitter: "acme", AfterID: lastID})
+	require.NoError(t, err)
+	assert.Empty(t, transitions)
+
+	require.NoError(t, RecordStatusChange(db, redeem.ID, "started", "failed", "insufficient_funds"))
+	transitions, _, err = Transitions(db, Filter{Submitter: "acme", AfterID: lastID})
+	require.NoError(t, err)
+	require.Len(t, transitions, 1)
+	assert.Equal(t, redeem.ID, transitions[0].RequestID)
+	assert.Equal(t, "failed", transitions[0].To)
+}
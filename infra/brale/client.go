@@ -3,11 +3,19 @@ package brale
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 )
 
+// BraleClient places orders against Brale. transferType is the destination
+// chain (e.g. "ethereum") and valueType the stablecoin (e.g. "USDC").
+// ListOrders returns a page of the orders created in [from, to). Pass the
+// previous page's next cursor to get the page after it. ListTransfers pages
+// the on-chain transfers created in [from, to) the same way.
 type BraleClient interface {
-	Mint(float64, string, string) (*APIResponse, error)
-	Redeem(float64, string, string) (*APIResponse, error)
+	Mint(amount float64, recipient string, transferType string, valueType string, idem string) (*APIResponse, error)
+	Redeem(amount float64, recipient string, transferType string, valueType string, idem string) (*APIResponse, error)
+	ListOrders(from time.Time, to time.Time, cursor string) (*OrderList, error)
+	ListTransfers(from time.Time, to time.Time, cursor string) (*TransferList, error)
 }
 
 type braleClient struct {
@@ -22,14 +30,22 @@ func NewBraleClient(baseURL string, jwt string) *braleClient {
 	}
 }
 
-func (bc *braleClient) Mint(amount float64, recipient string, idem string) (*APIResponse, error) {
+func (bc *braleClient) Mint(amount float64, recipient string, transferType string, valueType string, idem string) (*APIResponse, error) {
 	return &APIResponse{}, nil
 }
 
-func (bc *braleClient) Redeem(amount float64, recipient string, idem string) (*APIResponse, error) {
+func (bc *braleClient) Redeem(amount float64, recipient string, transferType string, valueType string, idem string) (*APIResponse, error) {
 	return &APIResponse{}, nil
 }
 
+func (bc *braleClient) ListOrders(from time.Time, to time.Time, cursor string) (*OrderList, error) {
+	return &OrderList{}, nil
+}
+
+func (bc *braleClient) ListTransfers(from time.Time, to time.Time, cursor string) (*TransferList, error) {
+	return &TransferList{}, nil
+}
+
 type mockBraleClient struct {
 }
 
@@ -37,7 +53,7 @@ func NewMockBraleClient() *mockBraleClient {
 	return &mockBraleClient{}
 }
 
-func (m *mockBraleClient) Mint(amount float64, recipient string, idem string) (*APIResponse, error) {
+func (m *mockBraleClient) Mint(amount float64, recipient string, transferType string, valueType string, idem string) (*APIResponse, error) {
 	// idem would be used here to prevent double spends since
 	if recipient == "0xdeadbeef" {
 		errResp, err := m.loadErrorResponse()
@@ -49,7 +65,7 @@ func (m *mockBraleClient) Mint(amount float64, recipient string, idem string) (*
 	return m.loadSuccessResponse()
 }
 
-func (m *mockBraleClient) Redeem(amount float64, recipient string, idem string) (*APIResponse, error) {
+func (m *mockBraleClient) Redeem(amount float64, recipient string, transferType string, valueType string, idem string) (*APIResponse, error) {
 	// idem would be used here to prevent double spends
 	if recipient == "0xdeadbeef" {
 		errResp, err := m.loadErrorResponse()
@@ -61,6 +77,81 @@ func (m *mockBraleClient) Redeem(amount float64, recipient string, idem string)
 	return m.loadSuccessResponse()
 }
 
+// ListOrders returns the same sample order for every range, split over two
+// pages so that callers exercise paging.
+func (m *mockBraleClient) ListOrders(from time.Time, to time.Time, cursor string) (*OrderList, error) {
+	if cursor != "" {
+		return &OrderList{}, nil
+	}
+
+	data := `{
+		"data": [
+		  {
+			"attributes": {
+			  "amount": {
+				"currency": "USD",
+				"value": "100.00"
+			  },
+			  "created": "2020-01-01T12:00:00Z",
+			  "idempotency_key": "sample-idempotency-key",
+			  "status": "complete",
+			  "transfer_type": "ethereum",
+			  "type": "mint",
+			  "value_type": "USDC"
+			},
+			"id": "2VZvtmVc2j3gQ80CTlcuQXbGrwC",
+			"type": "order"
+		  }
+		],
+		"links": {
+		  "next": "page-2"
+		}
+	}`
+
+	var orders OrderList
+	if err := json.Unmarshal([]byte(data), &orders); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal orders response: %v", err)
+	}
+	return &orders, nil
+}
+
+// ListTransfers returns the transfer that settled the sample order for every
+// range, split over two pages like ListOrders.
+func (m *mockBraleClient) ListTransfers(from time.Time, to time.Time, cursor string) (*TransferList, error) {
+	if cursor != "" {
+		return &TransferList{}, nil
+	}
+
+	data := `{
+		"data": [
+		  {
+			"attributes": {
+			  "amount": {
+				"currency": "USD",
+				"value": "100.00"
+			  },
+			  "created": "2020-01-01T12:00:00Z",
+			  "hash": "0x5b1e8c2b2e4a2a3f8a1f0c9b6e1d2c3b4a5f6e7d8c9b0a1f2e3d4c5b6a7f8e9d",
+			  "order_id": "2VZvtmVc2j3gQ80CTlcuQXbGrwC",
+			  "status": "complete",
+			  "type": "transfer"
+			},
+			"id": "2VZvtnQ0kDlRm3LoA7vjyK1hpJE",
+			"type": "transaction"
+		  }
+		],
+		"links": {
+		  "next": "page-2"
+		}
+	}`
+
+	var transfers TransferList
+	if err := json.Unmarshal([]byte(data), &transfers); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transfers response: %v", err)
+	}
+	return &transfers, nil
+}
+
 func (m *mockBraleClient) loadSuccessResponse() (*APIResponse, error) {
 	data := `{
 		"data": {
@@ -72,7 +163,24 @@ func (m *mockBraleClient) loadSuccessResponse() (*APIResponse, error) {
 		  },
 		  "id": "2VZvtmVc2j3gQ80CTlcuQXbGrwC",
 		  "type": "order"
-		}
+		},
+		"included": [
+		  {
+			"attributes": {
+			  "amount": {
+				"currency": "USD",
+				"value": "100.00"
+			  },
+			  "created": "2020-01-01T12:00:00Z",
+			  "hash": "0x5b1e8c2b2e4a2a3f8a1f0c9b6e1d2c3b4a5f6e7d8c9b0a1f2e3d4c5b6a7f8e9d",
+			  "status": "complete",
+			  "type": "transfer",
+			  "updated": "2020-01-01T12:00:00Z"
+			},
+			"id": "2VZvtnQ0kDlRm3LoA7vjyK1hpJE",
+			"type": "transaction"
+		  }
+		]
 	}
 	`
 
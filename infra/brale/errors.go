@@ -0,0 +1,98 @@
+package brale
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Error reasons. They double as the error code persisted on a failed request
+// and as the cadence custom error reason the retry policies match on.
+const (
+	ReasonValidation        = "brale_validation_error"
+	ReasonAuth              = "brale_auth_error"
+	ReasonInsufficientFunds = "brale_insufficient_funds"
+	ReasonRateLimited       = "brale_rate_limited"
+	ReasonServerError       = "brale_server_error"
+	ReasonNetwork           = "brale_network_error"
+	ReasonUnknown           = "brale_unknown_error"
+)
+
+// NonRetryableReasons are the reasons retrying the same order can't fix.
+var NonRetryableReasons = []string{
+	ReasonValidation,
+	ReasonAuth,
+	ReasonInsufficientFunds,
+}
+
+// Error is a classified Brale failure.
+type Error struct {
+	Reason string
+	Code   string
+	Status string
+	Detail string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Reason, e.Detail)
+}
+
+func (e *Error) Retryable() bool {
+	for _, reason := range NonRetryableReasons {
+		if e.Reason == reason {
+			return false
+		}
+	}
+	return true
+}
+
+// NewResponseError classifies the first error of a Brale error response.
+func NewResponseError(resp *APIResponse) *Error {
+	detail := resp.Errors[0]
+	return &Error{
+		Reason: classify(detail.Code, detail.Status),
+		Code:   detail.Code,
+		Status: detail.Status,
+		Detail: detail.Detail,
+	}
+}
+
+// NewNetworkError wraps an error from calling Brale that never got a response.
+func NewNetworkError(err error) *Error {
+	return &Error{
+		Reason: ReasonNetwork,
+		Detail: err.Error(),
+	}
+}
+
+func classify(code string, status string) string {
+	switch code {
+	case "ValidationError", "BadRequest", "UnprocessableEntity":
+		return ReasonValidation
+	case "Unauthorized", "Forbidden", "AuthenticationError", "AuthorizationError":
+		return ReasonAuth
+	case "InsufficientFunds", "InsufficientBalance":
+		return ReasonInsufficientFunds
+	case "TooManyRequests", "RateLimited":
+		return ReasonRateLimited
+	}
+
+	httpStatus, err := strconv.Atoi(status)
+	if err != nil {
+		return ReasonUnknown
+	}
+
+	switch {
+	case httpStatus == 429:
+		return ReasonRateLimited
+	case httpStatus == 401 || httpStatus == 403:
+		return ReasonAuth
+	case httpStatus == 408:
+		return ReasonNetwork
+	case httpStatus >= 500:
+		return ReasonServerError
+	case httpStatus >= 400:
+		return ReasonValidation
+	}
+
+	return ReasonUnknown
+}
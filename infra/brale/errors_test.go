@@ -0,0 +1,43 @@
+package brale
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewResponseError_ClassifiesByCodeThenStatus(t *testing.T) {
+	tests := []struct {
+		code      string
+		status    string
+		reason    string
+		retryable bool
+	}{
+		{"ValidationError", "400", ReasonValidation, false},
+		{"Unauthorized", "401", ReasonAuth, false},
+		{"InsufficientFunds", "422", ReasonInsufficientFunds, false},
+		{"", "403", ReasonAuth, false},
+		{"", "404", ReasonValidation, false},
+		{"", "408", ReasonNetwork, true},
+		{"", "429", ReasonRateLimited, true},
+		{"ServiceUnavailable", "503", ReasonServerError, true},
+		{"", "500", ReasonServerError, true},
+		{"SomethingNew", "", ReasonUnknown, true},
+	}
+
+	for _, tt := range tests {
+		err := NewResponseError(&APIResponse{Errors: []ErrorDetail{{Code: tt.code, Status: tt.status, Detail: "detail"}}})
+		assert.Equal(t, tt.reason, err.Reason, "code %q status %q", tt.code, tt.status)
+		assert.Equal(t, tt.retryable, err.Retryable(), "code %q status %q", tt.code, tt.status)
+		assert.Equal(t, "detail", err.Detail)
+	}
+}
+
+func TestNewNetworkError_IsRetryable(t *testing.T) {
+	err := NewNetworkError(errors.New("connection reset by peer"))
+
+	assert.Equal(t, ReasonNetwork, err.Reason)
+	assert.True(t, err.Retryable())
+	assert.Equal(t, "brale_network_error: connection reset by peer", err.Error())
+}
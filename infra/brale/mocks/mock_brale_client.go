@@ -7,6 +7,7 @@ package mocks
 import (
 	brale "mint-redeem-workflow/infra/brale"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -34,32 +35,62 @@ func (m *MockBraleClient) EXPECT() *MockBraleClientMockRecorder {
 	return m.recorder
 }
 
+// ListOrders mocks base method.
+func (m *MockBraleClient) ListOrders(from, to time.Time, cursor string) (*brale.OrderList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrders", from, to, cursor)
+	ret0, _ := ret[0].(*brale.OrderList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrders indicates an expected call of ListOrders.
+func (mr *MockBraleClientMockRecorder) ListOrders(from, to, cursor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockBraleClient)(nil).ListOrders), from, to, cursor)
+}
+
+// ListTransfers mocks base method.
+func (m *MockBraleClient) ListTransfers(from, to time.Time, cursor string) (*brale.TransferList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransfers", from, to, cursor)
+	ret0, _ := ret[0].(*brale.TransferList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransfers indicates an expected call of ListTransfers.
+func (mr *MockBraleClientMockRecorder) ListTransfers(from, to, cursor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockBraleClient)(nil).ListTransfers), from, to, cursor)
+}
+
 // Mint mocks base method.
-func (m *MockBraleClient) Mint(arg0 float64, arg1 string) (*brale.APIResponse, error) {
+func (m *MockBraleClient) Mint(amount float64, recipient, transferType, valueType, idem string) (*brale.APIResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Mint", arg0, arg1)
+	ret := m.ctrl.Call(m, "Mint", amount, recipient, transferType, valueType, idem)
 	ret0, _ := ret[0].(*brale.APIResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Mint indicates an expected call of Mint.
-func (mr *MockBraleClientMockRecorder) Mint(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockBraleClientMockRecorder) Mint(amount, recipient, transferType, valueType, idem interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Mint", reflect.TypeOf((*MockBraleClient)(nil).Mint), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Mint", reflect.TypeOf((*MockBraleClient)(nil).Mint), amount, recipient, transferType, valueType, idem)
 }
 
 // Redeem mocks base method.
-func (m *MockBraleClient) Redeem(arg0 float64, arg1 string) (*brale.APIResponse, error) {
+func (m *MockBraleClient) Redeem(amount float64, recipient, transferType, valueType, idem string) (*brale.APIResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Redeem", arg0, arg1)
+	ret := m.ctrl.Call(m, "Redeem", amount, recipient, transferType, valueType, idem)
 	ret0, _ := ret[0].(*brale.APIResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Redeem indicates an expected call of Redeem.
-func (mr *MockBraleClientMockRecorder) Redeem(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockBraleClientMockRecorder) Redeem(amount, recipient, transferType, valueType, idem interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeem", reflect.TypeOf((*MockBraleClient)(nil).Redeem), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeem", reflect.TypeOf((*MockBraleClient)(nil).Redeem), amount, recipient, transferType, valueType, idem)
 }
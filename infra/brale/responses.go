@@ -86,3 +86,63 @@ type ErrorDetail struct {
 	Status string `json:"status"`
 	Title  string `json:"title"`
 }
+
+// OrderList is a page of orders from the list orders endpoint.
+type OrderList struct {
+	Data   []Order       `json:"data"`
+	Links  PageLinks     `json:"links"`
+	Errors []ErrorDetail `json:"errors,omitempty"`
+}
+
+type Order struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Attributes OrderAttributes `json:"attributes"`
+}
+
+// OrderAttributes describe an order. IdempotencyKey is the key the order was
+// placed with, which is our request ID.
+type OrderAttributes struct {
+	Amount struct {
+		Currency string `json:"currency"`
+		Value    string `json:"value"`
+	} `json:"amount"`
+	Created        string `json:"created"`
+	IdempotencyKey string `json:"idempotency_key"`
+	Status         string `json:"status"`
+	TransferType   string `json:"transfer_type"`
+	Type           string `json:"type"`
+	ValueType      string `json:"value_type"`
+}
+
+// PageLinks holds the cursor of the next page. Next is empty on the last page.
+type PageLinks struct {
+	Next string `json:"next"`
+}
+
+// TransferList is a page of transfers from the list transfers endpoint.
+type TransferList struct {
+	Data   []Transfer    `json:"data"`
+	Links  PageLinks     `json:"links"`
+	Errors []ErrorDetail `json:"errors,omitempty"`
+}
+
+type Transfer struct {
+	ID         string             `json:"id"`
+	Type       string             `json:"type"`
+	Attributes TransferAttributes `json:"attributes"`
+}
+
+// TransferAttributes describe an on-chain transfer. Hash is the transaction
+// hash and OrderID the order the transfer settled.
+type TransferAttributes struct {
+	Amount struct {
+		Currency string `json:"currency"`
+		Value    string `json:"value"`
+	} `json:"amount"`
+	Created string `json:"created"`
+	Hash    string `json:"hash"`
+	OrderID string `json:"order_id"`
+	Status  string `json:"status"`
+	Type    string `json:"type"`
+}
@@ -0,0 +1,53 @@
+package cadence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"go.uber.org/cadence/.gen/go/cadence/workflowserviceclient"
+	"go.uber.org/cadence/.gen/go/shared"
+)
+
+// EnsureDomain checks that the domain exists. A missing domain is registered
+// with the retention if register is set, and is an error that says how to
+// register it by hand otherwise.
+func EnsureDomain(ctx context.Context, service workflowserviceclient.Interface, domain string, retention time.Duration, register bool) error {
+	_, err := service.DescribeDomain(ctx, &shared.DescribeDomainRequest{Name: &domain})
+	var notExists *shared.EntityNotExistsError
+	if err == nil {
+		return nil
+	}
+	if !errors.As(err, &notExists) {
+		return fmt.Errorf("describe Cadence domain %s: %w. Check that the Cadence server is running, e.g. with `docker-compose up`", domain, err)
+	}
+
+	retentionDays := retentionDays(retention)
+	if !register {
+		return fmt.Errorf("Cadence domain %s doesn't exist. Register it with `docker run --network=host --rm ubercadence/cli:master --do %s domain register -rd %d` or turn on RegisterCadenceDomain in the config", domain, domain, retentionDays)
+	}
+
+	err = service.RegisterDomain(ctx, &shared.RegisterDomainRequest{
+		Name:                                   &domain,
+		WorkflowExecutionRetentionPeriodInDays: &retentionDays,
+	})
+	var alreadyExists *shared.DomainAlreadyExistsError
+	if err != nil && !errors.As(err, &alreadyExists) {
+		return fmt.Errorf("register Cadence domain %s: %w", domain, err)
+	}
+
+	return nil
+}
+
+// retentionDays rounds the retention up to the whole days Cadence keeps
+// closed workflows for, and keeps them for at least a day.
+func retentionDays(retention time.Duration) int32 {
+	days := int32(math.Ceil(retention.Hours() / 24))
+	if days < 1 {
+		return 1
+	}
+
+	return days
+}
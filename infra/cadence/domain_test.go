@@ -0,0 +1,90 @@
+package cadence
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/cadence/.gen/go/cadence/workflowserviceclient"
+	"go.uber.org/cadence/.gen/go/shared"
+	"go.uber.org/yarpc"
+)
+
+// frontend answers the domain and task list calls like the Cadence frontend.
+type frontend struct {
+	workflowserviceclient.Interface
+
+	domains     map[string]*shared.RegisterDomainRequest
+	describeErr error
+	pollers     map[shared.TaskListType]int
+}
+
+func newFrontend() *frontend {
+	return &frontend{domains: map[string]*shared.RegisterDomainRequest{}, pollers: map[shared.TaskListType]int{}}
+}
+
+func (f *frontend) DescribeDomain(ctx context.Context, request *shared.DescribeDomainRequest, opts ...yarpc.CallOption) (*shared.DescribeDomainResponse, error) {
+	if f.describeErr != nil {
+		return nil, f.describeErr
+	}
+	if _, ok := f.domains[request.GetName()]; !ok {
+		return nil, &shared.EntityNotExistsError{Message: "domain doesn't exist"}
+	}
+
+	return &shared.DescribeDomainResponse{}, nil
+}
+
+func (f *frontend) RegisterDomain(ctx context.Context, request *shared.RegisterDomainRequest, opts ...yarpc.CallOption) error {
+	if _, ok := f.domains[request.GetName()]; ok {
+		return &shared.DomainAlreadyExistsError{Message: "domain already exists"}
+	}
+	f.domains[request.GetName()] = request
+
+	return nil
+}
+
+func (f *frontend) DescribeTaskList(ctx context.Context, request *shared.DescribeTaskListRequest, opts ...yarpc.CallOption) (*shared.DescribeTaskListResponse, error) {
+	pollers := make([]*shared.PollerInfo, f.pollers[request.GetTaskListType()])
+	for i := range pollers {
+		pollers[i] = &shared.PollerInfo{}
+	}
+
+	return &shared.DescribeTaskListResponse{Pollers: pollers}, nil
+}
+
+func TestEnsureDomain_RegistersMissingDomainWithRetention(t *testing.T) {
+	service := newFrontend()
+
+	require.NoError(t, EnsureDomain(context.Background(), service, "test-domain2", time.Hour*36, true))
+
+	require.Contains(t, service.domains, "test-domain2")
+	assert.Equal(t, int32(2), service.domains["test-domain2"].GetWorkflowExecutionRetentionPeriodInDays())
+
+	// A domain that exists is left as it is.
+	require.NoError(t, EnsureDomain(context.Background(), service, "test-domain2", time.Hour*24*7, true))
+	assert.Equal(t, int32(2), service.domains["test-domain2"].GetWorkflowExecutionRetentionPeriodInDays())
+}
+
+func TestEnsureDomain_MissingDomainWithoutRegisterSaysHowToRegister(t *testing.T) {
+	service := newFrontend()
+
+	err := EnsureDomain(context.Background(), service, "test-domain2", time.Hour*24, false)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--do test-domain2 domain register -rd 1")
+	assert.Empty(t, service.domains)
+}
+
+func TestEnsureDomain_UnreachableFrontendSaysToStartCadence(t *testing.T) {
+	service := newFrontend()
+	service.describeErr = errors.New("connection refused")
+
+	err := EnsureDomain(context.Background(), service, "test-domain2", time.Hour*24, true)
+
+	assert.ErrorIs(t, err, service.describeErr)
+	assert.Contains(t, err.Error(), "docker-compose up")
+	assert.Empty(t, service.domains)
+}
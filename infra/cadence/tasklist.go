@@ -0,0 +1,51 @@
+package cadence
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/cadence/.gen/go/cadence/workflowserviceclient"
+	"go.uber.org/cadence/.gen/go/shared"
+)
+
+// CheckPollers returns an error unless a worker is polling both the decision
+// and the activity tasks of the task list.
+func CheckPollers(ctx context.Context, service workflowserviceclient.Interface, domain string, taskList string) error {
+	for _, taskListType := range []shared.TaskListType{shared.TaskListTypeDecision, shared.TaskListTypeActivity} {
+		response, err := service.DescribeTaskList(ctx, &shared.DescribeTaskListRequest{
+			Domain:       &domain,
+			TaskList:     &shared.TaskList{Name: &taskList},
+			TaskListType: taskListType.Ptr(),
+		})
+		if err != nil {
+			return fmt.Errorf("describe task list %s in Cadence domain %s: %w", taskList, domain, err)
+		}
+		if len(response.GetPollers()) == 0 {
+			return fmt.Errorf("no workers are polling the %s tasks of task list %s in Cadence domain %s. Check that the worker started and uses the same domain and task list", strings.ToLower(taskListType.String()), taskList, domain)
+		}
+	}
+
+	return nil
+}
+
+// WaitForPollers calls CheckPollers every interval until it passes, and
+// returns its last error if ctx is done first.
+func WaitForPollers(ctx context.Context, service workflowserviceclient.Interface, domain string, taskList string, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := CheckPollers(ctx, service, domain, taskList)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-ticker.C:
+		}
+	}
+}
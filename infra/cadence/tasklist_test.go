@@ -0,0 +1,34 @@
+package cadence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/cadence/.gen/go/shared"
+)
+
+func TestCheckPollers_NeedsDecisionAndActivityPollers(t *testing.T) {
+	service := newFrontend()
+
+	err := CheckPollers(context.Background(), service, "test-domain2", "test-worker")
+	assert.ErrorContains(t, err, "no workers are polling the decision tasks of task list test-worker")
+
+	service.pollers[shared.TaskListTypeDecision] = 1
+	err = CheckPollers(context.Background(), service, "test-domain2", "test-worker")
+	assert.ErrorContains(t, err, "no workers are polling the activity tasks of task list test-worker")
+
+	service.pollers[shared.TaskListTypeActivity] = 1
+	assert.NoError(t, CheckPollers(context.Background(), service, "test-domain2", "test-worker"))
+}
+
+func TestWaitForPollers_ReturnsLastErrorWhenContextEnds(t *testing.T) {
+	service := newFrontend()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	err := WaitForPollers(ctx, service, "test-domain2", "test-worker", time.Millisecond*10)
+
+	assert.ErrorContains(t, err, "no workers are polling")
+}
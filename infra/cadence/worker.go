@@ -1,13 +1,17 @@
 package cadence
 
 import (
+	"fmt"
+
 	"github.com/uber-go/tally"
 	"go.uber.org/cadence/.gen/go/cadence/workflowserviceclient"
 	"go.uber.org/cadence/worker"
 	"go.uber.org/zap"
 )
 
-func StartWorker(taskName string, domain string, logger *zap.Logger, service workflowserviceclient.Interface) {
+// StartWorker starts polling the task list. Stop the worker before closing
+// the connection its service client uses.
+func StartWorker(taskName string, domain string, logger *zap.Logger, service workflowserviceclient.Interface) (worker.Worker, error) {
 	workerOptions := worker.Options{
 		Logger:       logger,
 		MetricsScope: tally.NewTestScope(taskName, map[string]string{}),
@@ -18,10 +22,11 @@ func StartWorker(taskName string, domain string, logger *zap.Logger, service wor
 		domain,
 		taskName,
 		workerOptions)
-	err := worker.Start()
-	if err != nil {
-		panic("Failed to start worker")
+	if err := worker.Start(); err != nil {
+		return nil, fmt.Errorf("start worker for task list %s in Cadence domain %s: %w", taskName, domain, err)
 	}
 
 	logger.Info("Started Worker.", zap.String("worker", taskName))
+
+	return worker, nil
 }
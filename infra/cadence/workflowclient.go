@@ -4,8 +4,12 @@ import (
 	"context"
 
 	"go.uber.org/cadence/client"
+	"go.uber.org/cadence/encoded"
 )
 
 type WorkflowClient interface {
 	ExecuteWorkflow(ctx context.Context, options client.StartWorkflowOptions, workflow interface{}, args ...interface{}) (client.WorkflowRun, error)
+	SignalWorkflow(ctx context.Context, workflowID string, runID string, signalName string, arg interface{}) error
+	TerminateWorkflow(ctx context.Context, workflowID string, runID string, reason string, details []byte) error
+	QueryWorkflow(ctx context.Context, workflowID string, runID string, queryType string, args ...interface{}) (encoded.Value, error)
 }
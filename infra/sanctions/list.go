@@ -0,0 +1,222 @@
+package sanctions
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const digitalCurrencyPrefix = "Digital Currency Address - "
+
+// maxShrink is the largest fraction of the current addresses a refresh may
+// drop. A bigger drop is more likely a truncated download than OFAC delisting
+// half the list.
+const maxShrink = 0.5
+
+var SDN *List
+
+// Entry is a sanctioned digital currency address and the party it belongs to.
+type Entry struct {
+	UID      string
+	Name     string
+	Currency string
+	Address  string
+}
+
+// List holds the sanctioned addresses loaded from an OFAC SDN style CSV or XML
+// file. It can be refreshed from the same file while in use.
+type List struct {
+	path    string
+	mu      sync.RWMutex
+	entries map[string]Entry
+}
+
+func InitList(path string) {
+	SDN = NewList(path)
+	if err := SDN.Refresh(); err != nil {
+		log.Fatal("Failed to load sanctions list:", err)
+	}
+}
+
+func NewList(path string) *List {
+	return &List{
+		path:    path,
+		entries: map[string]Entry{},
+	}
+}
+
+// Refresh reloads the list from its file. The current entries are kept if the
+// file can't be read or parsed, has no addresses, or has lost more than
+// maxShrink of them, since screening against a partial list would let
+// sanctioned addresses through.
+func (l *List) Refresh() error {
+	f, err := os.Open(l.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	if strings.EqualFold(filepath.Ext(l.path), ".xml") {
+		entries, err = parseXML(f)
+	} else {
+		entries, err = parseCSV(f)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse sanctions list %s: %v", l.path, err)
+	}
+
+	byAddress := make(map[string]Entry, len(entries))
+	for _, entry := range entries {
+		byAddress[normalizeAddress(entry.Address)] = entry
+	}
+
+	if len(byAddress) == 0 {
+		return fmt.Errorf("sanctions list %s has no digital currency addresses", l.path)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if current := len(l.entries); float64(len(byAddress)) < float64(current)*(1-maxShrink) {
+		return fmt.Errorf("sanctions list %s has %d addresses, down from %d", l.path, len(byAddress), current)
+	}
+	l.entries = byAddress
+
+	return nil
+}
+
+// StartRefresh reloads the list from its file on every interval until stop is
+// closed.
+func (l *List) StartRefresh(interval time.Duration, logger *zap.Logger, stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := l.Refresh(); err != nil {
+					logger.Error("Failed to refresh sanctions list.", zap.Error(err))
+					continue
+				}
+				logger.Info("Refreshed sanctions list.", zap.Int("addresses", l.Len()))
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Match returns the entry for the address if it is on the list.
+func (l *List) Match(address string) (Entry, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	entry, ok := l.entries[normalizeAddress(address)]
+	return entry, ok
+}
+
+func (l *List) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return len(l.entries)
+}
+
+// EVM addresses are case insensitive, other chains' addresses are not.
+func normalizeAddress(address string) string {
+	address = strings.TrimSpace(address)
+	if strings.HasPrefix(strings.ToLower(address), "0x") {
+		return strings.ToLower(address)
+	}
+	return address
+}
+
+var remarksAddressPattern = regexp.MustCompile(`Digital Currency Address - (\w+) ([^;\s]+)`)
+
+// parseCSV reads the OFAC sdn.csv layout, where digital currency addresses are
+// listed in the remarks column (the last one).
+func parseCSV(r io.Reader) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var entries []Entry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) < 3 {
+			continue
+		}
+
+		remarks := record[len(record)-1]
+		for _, match := range remarksAddressPattern.FindAllStringSubmatch(remarks, -1) {
+			entries = append(entries, Entry{
+				UID:      strings.TrimSpace(record[0]),
+				Name:     strings.TrimSpace(record[1]),
+				Currency: match[1],
+				Address:  match[2],
+			})
+		}
+	}
+
+	return entries, nil
+}
+
+type sdnList struct {
+	Entries []sdnEntry `xml:"sdnEntry"`
+}
+
+type sdnEntry struct {
+	UID       string  `xml:"uid"`
+	FirstName string  `xml:"firstName"`
+	LastName  string  `xml:"lastName"`
+	IDs       []sdnID `xml:"idList>id"`
+}
+
+type sdnID struct {
+	IDType   string `xml:"idType"`
+	IDNumber string `xml:"idNumber"`
+}
+
+// parseXML reads the OFAC sdn.xml layout, where digital currency addresses are
+// ids with a "Digital Currency Address - <currency>" id type.
+func parseXML(r io.Reader) ([]Entry, error) {
+	var list sdnList
+	if err := xml.NewDecoder(r).Decode(&list); err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, sdn := range list.Entries {
+		name := strings.TrimSpace(strings.TrimSpace(sdn.FirstName) + " " + strings.TrimSpace(sdn.LastName))
+		for _, id := range sdn.IDs {
+			if !strings.HasPrefix(id.IDType, digitalCurrencyPrefix) {
+				continue
+			}
+			entries = append(entries, Entry{
+				UID:      sdn.UID,
+				Name:     name,
+				Currency: strings.TrimPrefix(id.IDType, digitalCurrencyPrefix),
+				Address:  strings.TrimSpace(id.IDNumber),
+			})
+		}
+	}
+
+	return entries, nil
+}
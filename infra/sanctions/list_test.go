@@ -0,0 +1,97 @@
+package sanctions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestList_LoadsCSVAddressesFromRemarks(t *testing.T) {
+	list := NewList("testdata/sdn.csv")
+	require.NoError(t, list.Refresh())
+
+	assert.Equal(t, 3, list.Len())
+
+	entry, ok := list.Match("0x2F389CE8BD8FF92DE3402FFCE4691D17FC4F6535")
+	assert.True(t, ok)
+	assert.Equal(t, "30296", entry.UID)
+	assert.Equal(t, "SUEX OTC, S.R.O.", entry.Name)
+	assert.Equal(t, "ETH", entry.Currency)
+
+	entry, ok = list.Match("12HQDsicffSBaYdJ6BhnE22sfjTESmmzKx")
+	assert.True(t, ok)
+	assert.Equal(t, "XBT", entry.Currency)
+
+	_, ok = list.Match("12hqdsicffsbaydj6bhne22sfjtesmmzkx")
+	assert.False(t, ok)
+}
+
+func TestList_LoadsXMLDigitalCurrencyIDs(t *testing.T) {
+	list := NewList("testdata/sdn.xml")
+	require.NoError(t, list.Refresh())
+
+	assert.Equal(t, 1, list.Len())
+
+	entry, ok := list.Match("0x8589427373d6d84e98730d7795d8f6f8731fda16")
+	assert.True(t, ok)
+	assert.Equal(t, "TORNADO CASH", entry.Name)
+	assert.Equal(t, "ETH", entry.Currency)
+
+	_, ok = list.Match("tornado.cash")
+	assert.False(t, ok)
+}
+
+func TestList_RefreshPicksUpFileChangesAndKeepsEntriesOnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sdn.csv")
+	require.NoError(t, os.WriteFile(path, []byte(`1,"A","Entity","CYBER2","-0- ","-0- ","-0- ","-0- ","-0- ","-0- ","-0- ","Digital Currency Address - ETH 0xaaa;"`+"\n"), 0o644))
+
+	list := NewList(path)
+	require.NoError(t, list.Refresh())
+	_, ok := list.Match("0xaaa")
+	assert.True(t, ok)
+
+	require.NoError(t, os.WriteFile(path, []byte(`2,"B","Entity","CYBER2","-0- ","-0- ","-0- ","-0- ","-0- ","-0- ","-0- ","Digital Currency Address - ETH 0xbbb;"`+"\n"), 0o644))
+	require.NoError(t, list.Refresh())
+	_, ok = list.Match("0xaaa")
+	assert.False(t, ok)
+	_, ok = list.Match("0xbbb")
+	assert.True(t, ok)
+
+	require.NoError(t, os.Remove(path))
+	assert.Error(t, list.Refresh())
+	_, ok = list.Match("0xbbb")
+	assert.True(t, ok)
+}
+
+func TestList_RefreshKeepsEntriesWhenTheFileIsEmptyOrShrinksSharply(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sdn.csv")
+	write := func(addresses ...string) {
+		var data string
+		for i, address := range addresses {
+			data += fmt.Sprintf(`%d,"A","Entity","CYBER2","-0- ","-0- ","-0- ","-0- ","-0- ","-0- ","-0- ","Digital Currency Address - ETH %s;"`+"\n", i, address)
+		}
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+	}
+
+	list := NewList(path)
+	write("0xaaa", "0xbbb", "0xccc", "0xddd")
+	require.NoError(t, list.Refresh())
+
+	write()
+	assert.Error(t, list.Refresh())
+	assert.Equal(t, 4, list.Len())
+
+	write("0xaaa")
+	assert.Error(t, list.Refresh())
+	assert.Equal(t, 4, list.Len())
+
+	write("0xaaa", "0xbbb")
+	require.NoError(t, list.Refresh())
+	assert.Equal(t, 2, list.Len())
+	_, ok := list.Match("0xccc")
+	assert.False(t, ok)
+}
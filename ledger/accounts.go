@@ -0,0 +1,24 @@
+package ledger
+
+import "fmt"
+
+// Chart of accounts. Stablecoins are issued 1:1, so a movement debits and
+// credits the same value in fiat and tokens.
+const (
+	// FiatReserveAccount holds the fiat backing every token in issue.
+	FiatReserveAccount = "fiat_reserve"
+	// FeesAccount collects fees. No fees are charged yet.
+	FeesAccount = "fees"
+
+	FiatCurrency = "USD"
+)
+
+// TokenSupplyAccount tracks the tokens in issue on one chain.
+func TokenSupplyAccount(token string, chain string) string {
+	return fmt.Sprintf("token_supply:%s:%s", token, chain)
+}
+
+// CustomerAccount is the account of the customer a request pays out to.
+func CustomerAccount(recipient string) string {
+	return "customer:" + recipient
+}
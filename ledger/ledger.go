@@ -0,0 +1,197 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"mint-redeem-workflow/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	KindPosting  = "posting"
+	KindReversal = "reversal"
+
+	// unitsPerWhole is how many ledger units make up one unit of a currency.
+	unitsPerWhole = 1_000_000
+)
+
+var (
+	ErrNotPostable = errors.New("only completed requests can be posted")
+	ErrNoPosting   = errors.New("request has no ledger posting")
+)
+
+// ToUnits converts an amount to ledger units.
+func ToUnits(amount float64) int64 {
+	return int64(math.Round(amount * unitsPerWhole))
+}
+
+// FromUnits converts ledger units back to an amount.
+func FromUnits(units int64) float64 {
+	return float64(units) / unitsPerWhole
+}
+
+// PostRequest records the movements of a completed request, which its
+// operation's posting rules turn into lines. Posting the same request again
+// returns its existing entry.
+func PostRequest(db *gorm.DB, request models.Request, lines []models.JournalLine) (*models.JournalEntry, error) {
+	if request.Status != "completed" {
+		return nil, ErrNotPostable
+	}
+
+	existing, err := findEntry(db, request.ID, KindPosting)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	entry := models.JournalEntry{
+		RequestID: request.ID,
+		Kind:      KindPosting,
+		Memo:      fmt.Sprintf("%s %s %s on %s", request.Type, FormatUnits(ToUnits(request.Amount)), request.Token, request.Chain),
+		Lines:     lines,
+	}
+	if err := post(db, &entry); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// Reverse posts an entry that undoes the request's posting, swapping every
+// debit and credit. The original entry is left untouched.
+func Reverse(db *gorm.DB, requestID uuid.UUID, memo string) (*models.JournalEntry, error) {
+	original, err := findEntry(db, requestID, KindPosting)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNoPosting
+		}
+		return nil, err
+	}
+
+	lines := make([]models.JournalLine, 0, len(original.Lines))
+	for _, line := range original.Lines {
+		lines = append(lines, models.JournalLine{
+			Account:  line.Account,
+			Currency: line.Currency,
+			Debit:    line.Credit,
+			Credit:   line.Debit,
+		})
+	}
+
+	entry := models.JournalEntry{
+		RequestID:  requestID,
+		Kind:       KindReversal,
+		ReversesID: &original.ID,
+		Memo:       memo,
+		Lines:      lines,
+	}
+	if err := post(db, &entry); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// post writes the entry, its lines and then marks it posted in one
+// transaction. The triggers on posting reject the whole entry if it is missing
+// lines or doesn't balance.
+func post(db *gorm.DB, entry *models.JournalEntry) error {
+	entry.ID = uuid.New()
+	entry.LineCount = len(entry.Lines)
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(entry).Error; err != nil {
+			return err
+		}
+
+		for i := range entry.Lines {
+			entry.Lines[i].EntryID = entry.ID
+		}
+		if err := tx.Create(&entry.Lines).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.JournalEntry{ID: entry.ID}).Update("posted", true).Error; err != nil {
+			return err
+		}
+		entry.Posted = true
+		return nil
+	})
+}
+
+func findEntry(db *gorm.DB, requestID uuid.UUID, kind string) (*models.JournalEntry, error) {
+	var entry models.JournalEntry
+	if err := db.Preload("Lines").First(&entry, "request_id = ? AND kind = ? AND posted = ?", requestID, kind, true).Error; err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// Balance is the total of one account in one currency. Balance is debits less
+// credits, so asset accounts are positive and liability accounts negative.
+type Balance struct {
+	Account  string
+	Currency string
+	Debits   int64
+	Credits  int64
+	Balance  int64
+}
+
+// Balances returns the balance of every account and currency, or of one
+// account when account isn't empty. Only lines of posted entries count.
+func Balances(db *gorm.DB, account string) ([]Balance, error) {
+	query := db.Model(&models.JournalLine{}).
+		Select("journal_lines.account, journal_lines.currency, SUM(journal_lines.debit) AS debits, SUM(journal_lines.credit) AS credits, SUM(journal_lines.debit) - SUM(journal_lines.credit) AS balance").
+		Joins("JOIN journal_entries ON journal_entries.id = journal_lines.entry_id AND journal_entries.posted = ?", true).
+		Group("journal_lines.account, journal_lines.currency").
+		Order("journal_lines.account, journal_lines.currency")
+	if account != "" {
+		query = query.Where("journal_lines.account = ?", account)
+	}
+
+	var balances []Balance
+	if err := query.Scan(&balances).Error; err != nil {
+		return nil, err
+	}
+
+	return balances, nil
+}
+
+// EntryFilter narrows Entries. Empty fields match everything.
+type EntryFilter struct {
+	RequestID string
+	Account   string
+	Limit     int
+}
+
+// Entries returns posted journal entries with their lines, newest first.
+func Entries(db *gorm.DB, filter EntryFilter) ([]models.JournalEntry, error) {
+	query := db.Preload("Lines").Where("posted = ?", true).Order("created_at desc")
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.Account != "" {
+		query = query.Where("id IN (?)", db.Model(&models.JournalLine{}).Select("entry_id").Where("account = ?", filter.Account))
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var entries []models.JournalEntry
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// FormatUnits formats ledger units as a decimal amount.
+func FormatUnits(units int64) string {
+	return fmt.Sprintf("%.6f", FromUnits(units))
+}
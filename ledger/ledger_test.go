@@ -0,0 +1,186 @@
+package ledger
+
+import (
+	"mint-redeem-workflow/models"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, Migrate(db))
+
+	return db
+}
+
+func completedRequest(requestType string, amount float64) models.Request {
+	return models.Request{
+		ID:        uuid.New(),
+		Type:      requestType,
+		Amount:    amount,
+		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "base",
+		Status:    "completed",
+	}
+}
+
+// requestLines moves the request's amount from the customer to the reserve.
+func requestLines(request models.Request) []models.JournalLine {
+	units := ToUnits(request.Amount)
+	return []models.JournalLine{
+		{Account: FiatReserveAccount, Currency: FiatCurrency, Debit: units},
+		{Account: CustomerAccount(request.Recipient), Currency: FiatCurrency, Credit: units},
+	}
+}
+
+func balancesByAccount(t *testing.T, db *gorm.DB) map[string]int64 {
+	balances, err := Balances(db, "")
+	require.NoError(t, err)
+
+	byAccount := map[string]int64{}
+	for _, balance := range balances {
+		byAccount[balance.Account+"/"+balance.Currency] = balance.Balance
+	}
+
+	return byAccount
+}
+
+func TestPostRequest_SameRequestIsPostedOnce(t *testing.T) {
+	db := openTestDB(t)
+	request := completedRequest("mint", 10)
+
+	first, err := PostRequest(db, request, requestLines(request))
+	require.NoError(t, err)
+	second, err := PostRequest(db, request, requestLines(request))
+	require.NoError(t, err)
+
+	assert.Equal(t, first.ID, second.ID)
+	entries, err := Entries(db, EntryFilter{RequestID: request.ID.String()})
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestPostRequest_IncompleteRequestIsNotPosted(t *testing.T) {
+	db := openTestDB(t)
+	request := completedRequest("mint", 10)
+	request.Status = "failed"
+
+	_, err := PostRequest(db, request, requestLines(request))
+
+	assert.ErrorIs(t, err, ErrNotPostable)
+}
+
+func TestReverse_UndoesPostingAndKeepsOriginal(t *testing.T) {
+	db := openTestDB(t)
+	request := completedRequest("mint", 10)
+	posting, err := PostRequest(db, request, requestLines(request))
+	require.NoError(t, err)
+
+	reversal, err := Reverse(db, request.ID, "refunded")
+	require.NoError(t, err)
+
+	assert.Equal(t, posting.ID, *reversal.ReversesID)
+	for _, balance := range balancesByAccount(t, db) {
+		assert.Equal(t, int64(0), balance)
+	}
+	entries, err := Entries(db, EntryFilter{RequestID: request.ID.String()})
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	_, err = Reverse(db, uuid.New(), "refunded")
+	assert.ErrorIs(t, err, ErrNoPosting)
+}
+
+func TestDatabase_RejectsUnbalancedEntries(t *testing.T) {
+	db := openTestDB(t)
+
+	entry := models.JournalEntry{
+		RequestID: uuid.New(),
+		Kind:      KindPosting,
+		Lines: []models.JournalLine{
+			{Account: FiatReserveAccount, Currency: FiatCurrency, Debit: 100},
+			{Account: TokenSupplyAccount("USDC", "base"), Currency: "USDC", Credit: 90},
+		},
+	}
+	err := post(db, &entry)
+
+	assert.ErrorContains(t, err, "journal entry debits must equal credits")
+	var count int64
+	db.Model(&models.JournalLine{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestDatabase_RejectsEntriesThatOnlyBalanceAcrossCurrencies(t *testing.T) {
+	db := openTestDB(t)
+
+	entry := models.JournalEntry{
+		RequestID: uuid.New(),
+		Kind:      KindPosting,
+		Lines: []models.JournalLine{
+			{Account: FiatReserveAccount, Currency: FiatCurrency, Debit: 100},
+			{Account: TokenSupplyAccount("USDC", "base"), Currency: "USDC", Credit: 100},
+		},
+	}
+	err := post(db, &entry)
+
+	assert.ErrorContains(t, err, "journal entry debits must equal credits")
+	var count int64
+	db.Model(&models.JournalEntry{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestDatabase_RejectsLinesWithoutEntry(t *testing.T) {
+	db := openTestDB(t)
+
+	err := db.Create(&models.JournalLine{EntryID: uuid.New(), Account: FeesAccount, Currency: FiatCurrency, Credit: 1}).Error
+	assert.ErrorContains(t, err, "journal lines must belong to an entry")
+
+	err = db.Create(&models.JournalEntry{RequestID: uuid.New(), Kind: KindPosting}).Error
+	assert.ErrorContains(t, err, "journal entries need at least two lines")
+}
+
+func TestDatabase_EntryMissingLinesIsNotPosted(t *testing.T) {
+	db := openTestDB(t)
+
+	entry := models.JournalEntry{RequestID: uuid.New(), Kind: KindPosting, LineCount: 3}
+	require.NoError(t, db.Create(&entry).Error)
+	require.NoError(t, db.Create(&[]models.JournalLine{
+		{EntryID: entry.ID, Account: FiatReserveAccount, Currency: FiatCurrency, Debit: 100},
+		{EntryID: entry.ID, Account: FeesAccount, Currency: FiatCurrency, Credit: 100},
+	}).Error)
+
+	err := db.Model(&models.JournalEntry{ID: entry.ID}).Update("posted", true).Error
+	assert.ErrorContains(t, err, "journal entry is missing lines")
+
+	assert.Empty(t, balancesByAccount(t, db))
+	entries, err := Entries(db, EntryFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestDatabase_RejectsChangesToPostedEntries(t *testing.T) {
+	db := openTestDB(t)
+	request := completedRequest("mint", 10)
+	posting, err := PostRequest(db, request, requestLines(request))
+	require.NoError(t, err)
+
+	err = db.Model(&models.JournalLine{}).Where("entry_id = ?", posting.ID).Update("debit", 1).Error
+	assert.ErrorContains(t, err, "append-only")
+
+	err = db.Delete(&models.JournalEntry{}, "id = ?", posting.ID).Error
+	assert.ErrorContains(t, err, "append-only")
+
+	err = db.Model(&models.JournalEntry{ID: posting.ID}).Update("memo", "changed").Error
+	assert.ErrorContains(t, err, "append-only")
+
+	err = db.Create(&models.JournalLine{EntryID: posting.ID, Account: FeesAccount, Currency: FiatCurrency, Credit: 1}).Error
+	assert.ErrorContains(t, err, "append-only")
+}
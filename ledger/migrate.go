@@ -0,0 +1,125 @@
+package ledger
+
+import (
+	"mint-redeem-workflow/models"
+
+	"gorm.io/gorm"
+)
+
+// triggers keep the journal balanced and append-only in the database itself.
+// An entry is written unposted before its lines, so every line has to belong
+// to an entry, and is then marked posted. Posting checks the entry as a whole:
+// it needs exactly its line count of lines, and debits have to equal credits
+// in each currency. A posted entry can't change or get more lines, and only
+// posted entries count, so an entry whose transaction stopped short of posting
+// it never reaches the balances.
+var triggers = []string{
+	`CREATE TRIGGER IF NOT EXISTS journal_entries_line_count
+	BEFORE INSERT ON journal_entries
+	WHEN NEW.line_count < 2
+	BEGIN
+		SELECT RAISE(ABORT, 'journal entries need at least two lines');
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS journal_entries_unposted
+	BEFORE INSERT ON journal_entries
+	WHEN NEW.posted
+	BEGIN
+		SELECT RAISE(ABORT, 'journal entries are posted once their lines are written');
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS journal_lines_entry
+	BEFORE INSERT ON journal_lines
+	WHEN NOT EXISTS (SELECT 1 FROM journal_entries WHERE id = NEW.entry_id)
+	BEGIN
+		SELECT RAISE(ABORT, 'journal lines must belong to an entry');
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS journal_lines_complete
+	BEFORE INSERT ON journal_lines
+	WHEN (SELECT posted FROM journal_entries WHERE id = NEW.entry_id)
+		OR (SELECT COUNT(*) FROM journal_lines WHERE entry_id = NEW.entry_id)
+		>= (SELECT line_count FROM journal_entries WHERE id = NEW.entry_id)
+	BEGIN
+		SELECT RAISE(ABORT, 'journal entries are append-only');
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS journal_entries_post_only
+	BEFORE UPDATE ON journal_entries
+	WHEN OLD.posted OR NOT NEW.posted
+		OR NEW.id IS NOT OLD.id
+		OR NEW.request_id IS NOT OLD.request_id
+		OR NEW.kind IS NOT OLD.kind
+		OR NEW.reverses_id IS NOT OLD.reverses_id
+		OR NEW.memo IS NOT OLD.memo
+		OR NEW.line_count IS NOT OLD.line_count
+		OR NEW.created_at IS NOT OLD.created_at
+	BEGIN
+		SELECT RAISE(ABORT, 'journal entries are append-only');
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS journal_entries_complete
+	BEFORE UPDATE OF posted ON journal_entries
+	WHEN NEW.posted
+		AND (SELECT COUNT(*) FROM journal_lines WHERE entry_id = NEW.id) <> NEW.line_count
+	BEGIN
+		SELECT RAISE(ABORT, 'journal entry is missing lines');
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS journal_entries_balanced
+	BEFORE UPDATE OF posted ON journal_entries
+	WHEN NEW.posted
+		AND EXISTS (
+			SELECT currency FROM journal_lines WHERE entry_id = NEW.id
+			GROUP BY currency HAVING SUM(debit) - SUM(credit) <> 0
+		)
+	BEGIN
+		SELECT RAISE(ABORT, 'journal entry debits must equal credits');
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS journal_entries_no_delete
+	BEFORE DELETE ON journal_entries
+	BEGIN
+		SELECT RAISE(ABORT, 'journal entries are append-only');
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS journal_lines_no_update
+	BEFORE UPDATE ON journal_lines
+	BEGIN
+		SELECT RAISE(ABORT, 'journal entries are append-only');
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS journal_lines_no_delete
+	BEFORE DELETE ON journal_lines
+	BEGIN
+		SELECT RAISE(ABORT, 'journal entries are append-only');
+	END`,
+}
+
+// droppedTriggers expected lines to be written before their entry, or checked
+// the balance as the last line was written, which missed entries that never
+// got all their lines.
+var droppedTriggers = []string{"journal_entries_balanced", "journal_lines_posted", "journal_lines_balanced", "journal_entries_no_update"}
+
+// Migrate creates the journal tables and their triggers.
+func Migrate(db *gorm.DB) error {
+	addingPosted := db.Migrator().HasTable(&models.JournalEntry{}) && !db.Migrator().HasColumn(&models.JournalEntry{}, "Posted")
+	if err := db.AutoMigrate(&models.JournalEntry{}, &models.JournalLine{}); err != nil {
+		return err
+	}
+
+	for _, trigger := range droppedTriggers {
+		if err := db.Exec("DROP TRIGGER IF EXISTS " + trigger).Error; err != nil {
+			return err
+		}
+	}
+
+	// Entries from before the posted column were balanced when their last
+	// line was written, so the ones that have all their lines count as posted.
+	if addingPosted {
+		err := db.Exec(`UPDATE journal_entries SET posted = true
+			WHERE line_count = (SELECT COUNT(*) FROM journal_lines WHERE entry_id = journal_entries.id)`).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, trigger := range triggers {
+		if err := db.Exec(trigger).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,61 +1,136 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"errors"
+	"flag"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"mint-redeem-workflow/activities"
-	"mint-redeem-workflow/api/mint"
-	"mint-redeem-workflow/api/redeem"
+	"mint-redeem-workflow/api"
+	"mint-redeem-workflow/config"
 	"mint-redeem-workflow/db"
 	"mint-redeem-workflow/deps"
 	"mint-redeem-workflow/infra/cadence"
+	"mint-redeem-workflow/infra/sanctions"
+	"mint-redeem-workflow/repository"
+	"mint-redeem-workflow/service"
+	"mint-redeem-workflow/webhooks"
 	"mint-redeem-workflow/worker/workflows"
 
-	"github.com/gin-gonic/gin"
 	"go.uber.org/cadence/activity"
+	"go.uber.org/cadence/worker"
 	"go.uber.org/cadence/workflow"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
+// startupTimeout bounds how long startup waits on Cadence for the domain and
+// for the worker to start polling.
+const startupTimeout = time.Second * 30
+
 func main() {
-	db.InitDB()
+	dev := flag.Bool("dev", false, "keep the database in memory instead of mint-redeem.db")
+	flag.Parse()
 
-	go startAPIServer()
+	if *dev {
+		db.InitMemoryDB()
+	} else {
+		db.InitDB()
+	}
+	requestRepository := repository.NewGormRequestRepository(db.Db)
 
-	startCadenceWorker()
-}
+	cfg, err := config.NewServiceConfig()
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+
+	sanctions.InitList(cfg.SanctionsListPath)
+	sanctions.SDN.StartRefresh(cfg.SanctionsRefreshInterval, buildLogger(), nil)
+
+	// The API and the worker share one connection to Cadence.
+	cadenceConn, err := deps.DialCadence(cfg.CadenceDomain)
+	if err != nil {
+		log.Fatal("Failed to connect to Cadence:", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	startupCtx, cancelStartup := context.WithTimeout(ctx, startupTimeout)
+	defer cancelStartup()
+	if err := cadence.EnsureDomain(startupCtx, cadenceConn.Service, cfg.CadenceDomain, cfg.CadenceDomainRetention, cfg.RegisterCadenceDomain); err != nil {
+		log.Fatal("Failed to set up Cadence domain: ", err)
+	}
+
+	worker, workerServer := startCadenceWorker(cadenceConn, requestRepository, cfg)
+	svc := service.New(db.Db, requestRepository, cadenceConn.Client, cfg)
 
-func startAPIServer() {
-	r := gin.Default()
+	// Status changes are delivered to webhooks from the request event
+	// history, where the status writes commit them.
+	webhooks.StartDispatch(db.Db, requestRepository, cadenceConn.Client, cfg.CadenceTaskList, cfg.WebhookDispatchInterval, buildLogger(), ctx.Done())
 
-	r.POST("/mint", func(c *gin.Context) {
-		mint.HandleMintRedeemRequest(c)
-	})
+	// Requests the API takes would sit in the task list if nothing polled it.
+	if err := cadence.WaitForPollers(startupCtx, cadenceConn.Service, cfg.CadenceDomain, cfg.CadenceTaskList, time.Second); err != nil {
+		log.Fatal("Worker isn't polling: ", err)
+	}
+	apiServer := startAPIServer(cadenceConn, svc, cfg)
 
-	r.POST("/redeem", func(c *gin.Context) {
-		redeem.HandleRedeemRequest(c)
-	})
+	<-ctx.Done()
 
-	if err := r.Run(":8090"); err != nil {
-		log.Fatal("Failed to run API server:", err)
+	// Stop taking requests and tasks before the connection they use closes.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	if err := apiServer.Shutdown(shutdownCtx); err != nil {
+		log.Println("Failed to shut down API server:", err)
+	}
+	if err := workerServer.Shutdown(shutdownCtx); err != nil {
+		log.Println("Failed to shut down worker server:", err)
+	}
+	worker.Stop()
+	if err := cadenceConn.Close(); err != nil {
+		log.Println("Failed to close Cadence connection:", err)
 	}
 }
 
-func startCadenceWorker() {
-	cadenceClient, err := deps.BuildCadenceServiceClient()
-	if err != nil {
-		fmt.Printf("Error creating Cadence client: %v\n", err)
-		return
+func startAPIServer(cadenceConn *deps.Cadence, svc *service.Service, cfg *config.ServiceConfig) *http.Server {
+	ready := func(ctx context.Context) error {
+		return cadence.CheckPollers(ctx, cadenceConn.Service, cfg.CadenceDomain, cfg.CadenceTaskList)
 	}
+	server := &http.Server{
+		Addr:    ":8090",
+		Handler: api.NewRouter(svc, cfg, ready),
+	}
+	go serve(server, "API")
+
+	return server
+}
 
-	cadence.StartWorker("test-worker", "test-domain2", buildLogger(), cadenceClient)
+func startCadenceWorker(cadenceConn *deps.Cadence, requestRepository repository.RequestRepository, cfg *config.ServiceConfig) (worker.Worker, *http.Server) {
+	dependencies := deps.NewDependencies(cfg)
 
-	err = http.ListenAndServe(":8080", nil)
+	logger := buildLogger()
+	activity.RegisterWithOptions(activities.New(dependencies.BraleClient, db.Db, requestRepository, sanctions.SDN, logger, dependencies.Config), activities.RegisterOptions)
+
+	worker, err := cadence.StartWorker(cfg.CadenceTaskList, cfg.CadenceDomain, logger, cadenceConn.Service)
 	if err != nil {
-		log.Fatal("Failed to start worker server:", err)
+		log.Fatal("Failed to start worker: ", err)
+	}
+
+	server := &http.Server{Addr: ":8080"}
+	go serve(server, "worker")
+
+	return worker, server
+}
+
+func serve(server *http.Server, name string) {
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("Failed to run %s server: %v", name, err)
 	}
 }
 
@@ -72,9 +147,11 @@ func buildLogger() *zap.Logger {
 }
 
 func init() {
+	workflow.Register(workflows.RequestWorkflow)
 	workflow.Register(workflows.MintWorkflow)
 	workflow.Register(workflows.RedeemWorkflow)
-	activity.Register(activities.MintActivity)
-	activity.Register(activities.RedeemActivity)
-	activity.Register(activities.UpdateStatusActivity)
+	workflow.Register(workflows.BatchWorkflow)
+	workflow.Register(workflows.ScheduledRequestWorkflow)
+	workflow.Register(workflows.ReconciliationWorkflow)
+	workflow.RegisterWithOptions(workflows.WebhookDeliveryWorkflow, workflow.RegisterOptions{Name: webhooks.DeliveryWorkflowName})
 }
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Approval is one entry of a request's approval trail.
+type Approval struct {
+	ID        uint      `gorm:"primaryKey"`
+	RequestID uuid.UUID `gorm:"type:uuid;index;not null"`
+	Approver  string    `gorm:"type:varchar(255);not null"`
+	Approved  bool      `gorm:"not null"`
+	Reason    string    `gorm:"type:varchar(255)"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
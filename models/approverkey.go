@@ -0,0 +1,45 @@
+package models
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ApproverKey is the ed25519 public key an approver signs approvals with.
+// Client is the API client the approver decides as, so that a decision is
+// tied to the caller that sent it rather than a name in the request body.
+type ApproverKey struct {
+	Approver  string    `gorm:"type:varchar(255);primaryKey"`
+	Client    string    `gorm:"type:varchar(255);index"`
+	PublicKey string    `gorm:"type:varchar(64);not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// RotationPayload is what the approver signs with this key to replace it
+// with newPublicKey: the word rotate, the approver and the new key, one per
+// line.
+func (k *ApproverKey) RotationPayload(newPublicKey string) []byte {
+	return []byte(fmt.Sprintf("rotate\n%s\n%s", k.Approver, newPublicKey))
+}
+
+// Verify checks the base64 encoded signature of the payload against the key.
+func (k *ApproverKey) Verify(payload []byte, signature string) error {
+	publicKey, err := base64.StdEncoding.DecodeString(k.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid public key for approver %s", k.Approver)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return errors.New("signature is not valid base64")
+	}
+
+	if !ed25519.Verify(publicKey, payload, sig) {
+		return errors.New("signature does not match the request")
+	}
+
+	return nil
+}
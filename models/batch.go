@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Batch groups requests submitted together through the batches endpoint.
+type Batch struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Status    string    `gorm:"type:varchar(20)"`
+	Submitter string    `gorm:"type:varchar(255)"`
+	Requests  []Request `gorm:"foreignKey:BatchID"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	RunID     string    `gorm:"type:varchar(20)"`
+}
+
+func (b *Batch) BeforeCreate(tx *gorm.DB) (err error) {
+	b.ID = uuid.New()
+	return
+}
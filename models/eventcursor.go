@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// EventCursor is how far a reader of the request event history got. EventID
+// is the last event it handled, and Failures counts the attempts in a row
+// that failed to handle the events after it.
+type EventCursor struct {
+	Name      string    `gorm:"type:varchar(50);primaryKey"`
+	EventID   uint      `gorm:"not null"`
+	Failures  int       `gorm:"not null;default:0"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// JournalEntry is a balanced, append-only ledger posting. A request has at most
+// one entry of each kind: its posting and, after a refund, the reversal.
+// LineCount is how many lines the entry is posted with, so the database can
+// refuse any more. Posted is set once they are all written, which is when the
+// database checks the entry balances, and only posted entries count.
+type JournalEntry struct {
+	ID         uuid.UUID     `gorm:"type:uuid;primaryKey"`
+	RequestID  uuid.UUID     `gorm:"type:uuid;not null;uniqueIndex:idx_journal_entries_request_kind"`
+	Kind       string        `gorm:"type:varchar(20);not null;uniqueIndex:idx_journal_entries_request_kind"`
+	ReversesID *uuid.UUID    `gorm:"type:uuid"`
+	Memo       string        `gorm:"type:varchar(255)"`
+	LineCount  int           `gorm:"not null;default:0"`
+	Posted     bool          `gorm:"not null;default:false"`
+	Lines      []JournalLine `gorm:"foreignKey:EntryID"`
+	CreatedAt  time.Time     `gorm:"autoCreateTime"`
+}
+
+func (e *JournalEntry) BeforeCreate(tx *gorm.DB) (err error) {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return
+}
+
+// JournalLine debits or credits one account. Amounts are in millionths of the
+// currency unit so that entries balance exactly.
+type JournalLine struct {
+	ID       uint      `gorm:"primaryKey"`
+	EntryID  uuid.UUID `gorm:"type:uuid;index;not null"`
+	Account  string    `gorm:"type:varchar(255);index;not null"`
+	Currency string    `gorm:"type:varchar(20);not null"`
+	Debit    int64     `gorm:"not null;default:0;check:debit >= 0"`
+	Credit   int64     `gorm:"not null;default:0;check:credit >= 0"`
+}
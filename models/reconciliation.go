@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReconciliationReport compares our requests with Brale's orders for the
+// range [From, To).
+type ReconciliationReport struct {
+	ID              uuid.UUID            `gorm:"type:uuid;primaryKey"`
+	From            time.Time            `gorm:"not null"`
+	To              time.Time            `gorm:"not null"`
+	Status          string               `gorm:"type:varchar(20)"`
+	Error           string               `gorm:"type:text"`
+	Matched         int                  `gorm:"not null;default:0"`
+	MissingLocally  int                  `gorm:"not null;default:0"`
+	MissingRemotely int                  `gorm:"not null;default:0"`
+	AmountMismatch  int                  `gorm:"not null;default:0"`
+	Items           []ReconciliationItem `gorm:"foreignKey:ReportID"`
+	CreatedAt       time.Time            `gorm:"autoCreateTime"`
+	CompletedAt     *time.Time
+	RunID           string `gorm:"type:varchar(64)"`
+}
+
+func (r *ReconciliationReport) BeforeCreate(tx *gorm.DB) (err error) {
+	r.ID = uuid.New()
+	return
+}
+
+// ReconciliationItem is one request, order or transfer and how it was
+// classified. Kind says whether the request was compared with Brale's orders
+// or with its on-chain transfers.
+type ReconciliationItem struct {
+	ID             uint       `gorm:"primaryKey"`
+	ReportID       uuid.UUID  `gorm:"type:uuid;index;not null"`
+	Kind           string     `gorm:"type:varchar(20);not null;default:'order'"`
+	Classification string     `gorm:"type:varchar(30);not null"`
+	RequestID      *uuid.UUID `gorm:"type:uuid"`
+	OrderID        string     `gorm:"type:varchar(64)"`
+	TxHash         string     `gorm:"type:varchar(128)"`
+	LocalAmount    *float64   `gorm:"type:numeric(24,6)"`
+	RemoteAmount   *float64   `gorm:"type:numeric(24,6)"`
+	Detail         string     `gorm:"type:varchar(255)"`
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,16 +10,67 @@ import (
 )
 
 type Request struct {
-	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
-	Type      string    `gorm:"type:varchar(20);not null"`
-	Amount    float64   `gorm:"type:numeric(18,2);not null"`
-	Recipient string    `gorm:"type:varchar(255);not null"`
-	Status    string    `gorm:"type:varchar(20);"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
-	RunID     string    `gorm:"type:varchar(20)"`
+	ID              uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	Type            string     `gorm:"type:varchar(20);not null"`
+	Amount          float64    `gorm:"type:numeric(24,6);not null"`
+	Recipient       string     `gorm:"type:varchar(255);not null"`
+	Token           string     `gorm:"type:varchar(20)"`
+	Chain           string     `gorm:"type:varchar(20)"`
+	Status          string     `gorm:"type:varchar(20);"`
+	Reason          string     `gorm:"type:varchar(255)"`
+	ProviderOrderID string     `gorm:"type:varchar(64)"`
+	TxHash          string     `gorm:"type:varchar(128)"`
+	ErrorCode       string     `gorm:"type:varchar(50)"`
+	ErrorDetail     string     `gorm:"type:text"`
+	Submitter       string     `gorm:"type:varchar(255)"`
+	ClientReference *string    `gorm:"type:varchar(255);uniqueIndex"`
+	BatchID         *uuid.UUID `gorm:"type:uuid;index"`
+	ScheduleID      *uuid.UUID `gorm:"type:uuid;index"`
+	Approvals       []Approval `gorm:"foreignKey:RequestID"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime"`
+	RunID           string     `gorm:"type:varchar(20)"`
+	Attempt         int        `gorm:"not null;default:1"`
+	Version         int        `gorm:"not null;default:1"`
+	ExecuteAt       *time.Time `gorm:"index"`
+	CompletedAt     *time.Time
+	FailedAt        *time.Time
+	// OrderSentAt is when the request's order was first sent to Brale. From
+	// then on Brale may have the order, even before its ID is saved.
+	OrderSentAt *time.Time
 }
 
+// BeforeCreate assigns a random ID unless the caller chose one, as scheduled
+// requests do so that each occurrence can only be created once.
 func (r *Request) BeforeCreate(tx *gorm.DB) (err error) {
-	r.ID = uuid.New()
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
 	return
 }
+
+// ApprovalPayload is the canonical encoding of the request: ID, type, amount,
+// token, chain and recipient, one per line. Approvers of workflows started
+// before decisions were signed sign it alone.
+func (r *Request) ApprovalPayload() []byte {
+	return []byte(fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n%s",
+		r.ID.String(),
+		r.Type,
+		strconv.FormatFloat(r.Amount, 'f', -1, 64),
+		r.Token,
+		r.Chain,
+		r.Recipient,
+	))
+}
+
+// ApprovalDecisionPayload is what an approver signs to decide on the request:
+// the approval payload followed by the decision, approve or reject, and the
+// approver, one per line. A signature can't be replayed as the other decision
+// or by another approver.
+func (r *Request) ApprovalDecisionPayload(approver string, approved bool) []byte {
+	decision := "reject"
+	if approved {
+		decision = "approve"
+	}
+
+	return []byte(fmt.Sprintf("%s\n%s\n%s", r.ApprovalPayload(), decision, approver))
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RequestAttempt records a retry of a failed request. It links the new
+// workflow run to the run it replaced and keeps the failure that run ended
+// with, which the retry clears from the request.
+type RequestAttempt struct {
+	ID                  uint      `gorm:"primaryKey"`
+	RequestID           uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_request_attempts_attempt"`
+	Attempt             int       `gorm:"not null;uniqueIndex:idx_request_attempts_attempt"`
+	RunID               string    `gorm:"type:varchar(64)"`
+	PreviousRunID       string    `gorm:"type:varchar(64)"`
+	PreviousErrorCode   string    `gorm:"type:varchar(50)"`
+	PreviousErrorDetail string    `gorm:"type:text"`
+	RetriedBy           string    `gorm:"type:varchar(255)"`
+	CreatedAt           time.Time `gorm:"autoCreateTime"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RequestEvent is an entry in a request's event history. Data holds the event
+// specific details as JSON.
+type RequestEvent struct {
+	ID        uint      `gorm:"primaryKey"`
+	RequestID uuid.UUID `gorm:"type:uuid;index;not null"`
+	Type      string    `gorm:"type:varchar(50);not null"`
+	Data      string    `gorm:"type:text"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Schedule is a recurring mint or redeem. Each firing of its cron workflow
+// creates a Request linked back to it.
+type Schedule struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Type         string    `gorm:"type:varchar(20);not null"`
+	Amount       float64   `gorm:"type:numeric(24,6);not null"`
+	Recipient    string    `gorm:"type:varchar(255);not null"`
+	Token        string    `gorm:"type:varchar(20)"`
+	Chain        string    `gorm:"type:varchar(20)"`
+	Submitter    string    `gorm:"type:varchar(255);index"`
+	CronSchedule string    `gorm:"type:varchar(100);not null"`
+	Status       string    `gorm:"type:varchar(20)"`
+	Requests     []Request `gorm:"foreignKey:ScheduleID"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+	WorkflowID   string    `gorm:"type:varchar(64)"`
+	RunID        string    `gorm:"type:varchar(64)"`
+}
+
+func (s *Schedule) BeforeCreate(tx *gorm.DB) (err error) {
+	s.ID = uuid.New()
+	return
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Webhook is a URL an API client wants request events posted to. Client is
+// matched against the submitter of each request. EventTypes is a comma
+// separated list such as "request.completed,request.failed", or "*" for every
+// event.
+type Webhook struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Client     string    `gorm:"type:varchar(255);index;not null"`
+	URL        string    `gorm:"type:varchar(2048);not null"`
+	EventTypes string    `gorm:"type:varchar(1024);not null"`
+	Secret     string    `gorm:"type:varchar(128);not null"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+func (w *Webhook) BeforeCreate(tx *gorm.DB) (err error) {
+	w.ID = uuid.New()
+	return
+}
+
+// Subscribes reports whether the webhook wants events of the type.
+func (w *Webhook) Subscribes(eventType string) bool {
+	for _, subscribed := range strings.Split(w.EventTypes, ",") {
+		if subscribed == "*" || subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one event sent to one webhook. EventID is the
+// status_changed request event it reports, so each status change is delivered
+// once, including a status a retried request reaches again. Payload is the
+// exact body posted on every attempt.
+type WebhookDelivery struct {
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey"`
+	WebhookID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_webhook_deliveries_status_change"`
+	Webhook        Webhook
+	RequestID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_webhook_deliveries_status_change"`
+	EventType      string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_webhook_deliveries_status_change"`
+	EventID        uint      `gorm:"not null;default:0;uniqueIndex:idx_webhook_deliveries_status_change"`
+	Payload        string    `gorm:"type:text;not null"`
+	Status         string    `gorm:"type:varchar(20)"`
+	Attempts       int       `gorm:"not null;default:0"`
+	LastStatusCode int
+	LastError      string `gorm:"type:text"`
+	LastAttemptAt  *time.Time
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+}
+
+func (d *WebhookDelivery) BeforeCreate(tx *gorm.DB) (err error) {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,55 @@
+package operations
+
+import (
+	"mint-redeem-workflow/infra/brale"
+	"mint-redeem-workflow/ledger"
+	"mint-redeem-workflow/models"
+)
+
+func init() {
+	Register(Operation{
+		Type: "mint",
+		Execute: func(client brale.BraleClient, params Params, requestID string) (*brale.APIResponse, error) {
+			return client.Mint(params.Amount, params.Recipient, params.Chain, params.Token, requestID)
+		},
+		ActivityOptions: BraleActivityOptions,
+		Postings:        mintPostings,
+	})
+
+	Register(Operation{
+		Type: "redeem",
+		Execute: func(client brale.BraleClient, params Params, requestID string) (*brale.APIResponse, error) {
+			return client.Redeem(params.Amount, params.Recipient, params.Chain, params.Token, requestID)
+		},
+		ActivityOptions: BraleActivityOptions,
+		Postings:        redeemPostings,
+	})
+}
+
+// mintPostings has the customer pay fiat into the reserve and receive newly
+// issued tokens, so the reserve and token supply move together.
+func mintPostings(params Params) []models.JournalLine {
+	units := ledger.ToUnits(params.Amount)
+	customer := ledger.CustomerAccount(params.Recipient)
+
+	return []models.JournalLine{
+		{Account: ledger.FiatReserveAccount, Currency: ledger.FiatCurrency, Debit: units},
+		{Account: customer, Currency: ledger.FiatCurrency, Credit: units},
+		{Account: customer, Currency: params.Token, Debit: units},
+		{Account: ledger.TokenSupplyAccount(params.Token, params.Chain), Currency: params.Token, Credit: units},
+	}
+}
+
+// redeemPostings is the reverse of a mint: the tokens are taken out of supply
+// and the customer is paid out of the reserve.
+func redeemPostings(params Params) []models.JournalLine {
+	units := ledger.ToUnits(params.Amount)
+	customer := ledger.CustomerAccount(params.Recipient)
+
+	return []models.JournalLine{
+		{Account: ledger.TokenSupplyAccount(params.Token, params.Chain), Currency: params.Token, Debit: units},
+		{Account: customer, Currency: params.Token, Credit: units},
+		{Account: customer, Currency: ledger.FiatCurrency, Debit: units},
+		{Account: ledger.FiatReserveAccount, Currency: ledger.FiatCurrency, Credit: units},
+	}
+}
@@ -0,0 +1,53 @@
+package operations
+
+import (
+	"mint-redeem-workflow/ledger"
+	"mint-redeem-workflow/models"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func completedRequest(requestType string, amount float64) models.Request {
+	return models.Request{
+		ID:        uuid.New(),
+		Type:      requestType,
+		Amount:    amount,
+		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "base",
+		Status:    "completed",
+	}
+}
+
+func TestPostRequest_MintAndRedeemMoveReserveAndSupply(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, ledger.Migrate(db))
+
+	_, err = PostRequest(db, completedRequest("mint", 100.5))
+	require.NoError(t, err)
+	_, err = PostRequest(db, completedRequest("redeem", 40.25))
+	require.NoError(t, err)
+
+	balances, err := ledger.Balances(db, "")
+	require.NoError(t, err)
+	byAccount := map[string]int64{}
+	for _, balance := range balances {
+		byAccount[balance.Account+"/"+balance.Currency] = balance.Balance
+	}
+	assert.Equal(t, map[string]int64{
+		"customer:0xnotdeadbeef/USD":  -60_250_000,
+		"customer:0xnotdeadbeef/USDC": 60_250_000,
+		"fiat_reserve/USD":            60_250_000,
+		"token_supply:USDC:base/USDC": -60_250_000,
+	}, byAccount)
+
+	_, err = PostRequest(db, completedRequest("burn", 1))
+	assert.ErrorContains(t, err, "type must be")
+}
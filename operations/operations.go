@@ -0,0 +1,151 @@
+// Package operations registers the kinds of request the service runs, like
+// mints and redeems. Everything that differs between them is registered once
+// here, and the API handler, service, workflow and activity look the request's
+// type up instead of having a copy per kind.
+package operations
+
+import (
+	"fmt"
+	"mint-redeem-workflow/config"
+	"mint-redeem-workflow/infra/brale"
+	"mint-redeem-workflow/ledger"
+	"mint-redeem-workflow/models"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/cadence"
+	"go.uber.org/cadence/workflow"
+	"gorm.io/gorm"
+)
+
+// ReasonCanceled is the error reason of an operation whose request was
+// canceled before its order was sent to Brale.
+const ReasonCanceled = "canceled"
+
+// BraleRetryPolicy retries transient Brale failures (network, 5xx, 429) with
+// backoff. Brale dedupes on the idempotency key, so a retried order can't be
+// placed twice. Validation, auth and insufficient funds errors fail at once.
+var BraleRetryPolicy = &cadence.RetryPolicy{
+	InitialInterval:          time.Second,
+	BackoffCoefficient:       2,
+	MaximumInterval:          time.Minute,
+	ExpirationInterval:       time.Minute * 3,
+	MaximumAttempts:          6,
+	NonRetriableErrorReasons: append([]string{ReasonCanceled}, brale.NonRetryableReasons...),
+}
+
+// BraleActivityOptions are the options of an activity that calls Brale.
+var BraleActivityOptions = workflow.ActivityOptions{
+	ScheduleToStartTimeout: time.Minute,
+	StartToCloseTimeout:    time.Minute,
+	HeartbeatTimeout:       time.Second * 20,
+	RetryPolicy:            BraleRetryPolicy,
+}
+
+// Params are the fields of a request the operation acts on.
+type Params struct {
+	Amount    float64
+	Recipient string
+	Token     string
+	Chain     string
+}
+
+// Operation is one kind of request.
+type Operation struct {
+	// Type is the request type, and the path its requests are submitted to.
+	Type string
+
+	// Validate checks what is particular to the operation, after the checks
+	// every operation gets. It can be nil.
+	Validate func(cfg *config.ServiceConfig, asset *config.Asset, params Params) error
+
+	// Execute places the order with Brale. The request ID is the idempotency
+	// key.
+	Execute func(client brale.BraleClient, params Params, requestID string) (*brale.APIResponse, error)
+
+	// ActivityOptions are used for the activity that runs Execute.
+	ActivityOptions workflow.ActivityOptions
+
+	// Postings are the ledger lines a completed request posts. They have to
+	// balance in each currency.
+	Postings func(params Params) []models.JournalLine
+}
+
+var registry = map[string]*Operation{}
+
+// Register adds an operation. It panics if the type is empty, already
+// registered or has no Execute or Postings, so mistakes show up at startup.
+func Register(op Operation) {
+	if op.Type == "" || op.Execute == nil || op.Postings == nil {
+		panic("operations: an operation needs a type, an execute func and postings")
+	}
+	if _, ok := registry[op.Type]; ok {
+		panic(fmt.Sprintf("operations: %s is already registered", op.Type))
+	}
+
+	registry[op.Type] = &op
+}
+
+// Lookup returns the operation registered for the type.
+func Lookup(opType string) (*Operation, error) {
+	op, ok := registry[opType]
+	if !ok {
+		return nil, fmt.Errorf("type must be %s", strings.Join(Types(), " or "))
+	}
+
+	return op, nil
+}
+
+// Types returns the registered types in alphabetical order.
+func Types() []string {
+	types := make([]string, 0, len(registry))
+	for opType := range registry {
+		types = append(types, opType)
+	}
+	sort.Strings(types)
+
+	return types
+}
+
+// ValidateRequest checks the request's asset is supported, its amount fits
+// the asset and it has a recipient, then runs the operation's own checks. It
+// returns the asset.
+func (op *Operation) ValidateRequest(cfg *config.ServiceConfig, params Params) (*config.Asset, error) {
+	if params.Recipient == "" {
+		return nil, fmt.Errorf("recipient is required")
+	}
+
+	asset, err := cfg.LookupAsset(params.Token, params.Chain)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := asset.ValidateAmount(params.Amount); err != nil {
+		return nil, err
+	}
+
+	if op.Validate != nil {
+		if err := op.Validate(cfg, asset, params); err != nil {
+			return nil, err
+		}
+	}
+
+	return asset, nil
+}
+
+// ParamsOf returns the fields of the request the operation acts on.
+func ParamsOf(request models.Request) Params {
+	return Params{Amount: request.Amount, Recipient: request.Recipient, Token: request.Token, Chain: request.Chain}
+}
+
+// PostRequest posts a completed request to the ledger with its operation's
+// postings.
+func PostRequest(db *gorm.DB, request models.Request) (*models.JournalEntry, error) {
+	op, err := Lookup(request.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	return ledger.PostRequest(db, request, op.Postings(ParamsOf(request)))
+}
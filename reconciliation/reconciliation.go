@@ -0,0 +1,187 @@
+// Package reconciliation matches our requests against the orders and on-chain
+// transfers Brale has on record.
+package reconciliation
+
+import (
+	"fmt"
+	"mint-redeem-workflow/infra/brale"
+	"mint-redeem-workflow/ledger"
+	"mint-redeem-workflow/models"
+	"strconv"
+)
+
+const (
+	Matched         = "matched"
+	MissingLocally  = "missing_locally"
+	MissingRemotely = "missing_remotely"
+	AmountMismatch  = "amount_mismatch"
+)
+
+// Kinds of item.
+const (
+	KindOrder    = "order"
+	KindTransfer = "transfer"
+)
+
+// Reconcile classifies every request and order. A request is matched to an
+// order by the order's idempotency key, which is the request ID, or failing
+// that by the Brale order ID saved on the request. Requests should only
+// include those that reached Brale: completed ones or ones with an order ID.
+func Reconcile(requests []models.Request, orders []brale.Order) []models.ReconciliationItem {
+	byKey := map[string]int{}
+	byOrderID := map[string]int{}
+	for i, order := range orders {
+		if order.Attributes.IdempotencyKey != "" {
+			byKey[order.Attributes.IdempotencyKey] = i
+		}
+		byOrderID[order.ID] = i
+	}
+
+	var items []models.ReconciliationItem
+	seen := map[int]bool{}
+	for _, request := range requests {
+		requestID := request.ID
+		localAmount := request.Amount
+		item := models.ReconciliationItem{
+			Kind:        KindOrder,
+			RequestID:   &requestID,
+			LocalAmount: &localAmount,
+		}
+
+		i, ok := byKey[request.ID.String()]
+		if !ok && request.ProviderOrderID != "" {
+			i, ok = byOrderID[request.ProviderOrderID]
+		}
+		if !ok || seen[i] {
+			item.Classification = MissingRemotely
+			item.OrderID = request.ProviderOrderID
+			item.Detail = "no Brale order for the request"
+			items = append(items, item)
+			continue
+		}
+		seen[i] = true
+
+		order := orders[i]
+		item.OrderID = order.ID
+		compareAmount(&item, order.Attributes.Amount.Value)
+		items = append(items, item)
+	}
+
+	for i, order := range orders {
+		if seen[i] {
+			continue
+		}
+
+		item := models.ReconciliationItem{
+			Kind:           KindOrder,
+			Classification: MissingLocally,
+			OrderID:        order.ID,
+			Detail:         fmt.Sprintf("%s order with idempotency key %q has no request", order.Attributes.Type, order.Attributes.IdempotencyKey),
+		}
+		if remoteAmount, err := strconv.ParseFloat(order.Attributes.Amount.Value, 64); err == nil {
+			item.RemoteAmount = &remoteAmount
+		}
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// ReconcileTransfers classifies every request that settled on chain and every
+// transfer. A request is matched to the transfer with its transaction hash.
+// Requests without a transaction hash are left out, since there is no
+// transfer to expect yet.
+func ReconcileTransfers(requests []models.Request, transfers []brale.Transfer) []models.ReconciliationItem {
+	byHash := map[string]int{}
+	for i, transfer := range transfers {
+		if transfer.Attributes.Hash != "" {
+			byHash[transfer.Attributes.Hash] = i
+		}
+	}
+
+	var items []models.ReconciliationItem
+	seen := map[int]bool{}
+	for _, request := range requests {
+		if request.TxHash == "" {
+			continue
+		}
+
+		requestID := request.ID
+		localAmount := request.Amount
+		item := models.ReconciliationItem{
+			Kind:        KindTransfer,
+			RequestID:   &requestID,
+			OrderID:     request.ProviderOrderID,
+			TxHash:      request.TxHash,
+			LocalAmount: &localAmount,
+		}
+
+		i, ok := byHash[request.TxHash]
+		if !ok || seen[i] {
+			item.Classification = MissingRemotely
+			item.Detail = "no Brale transfer for the request's transaction"
+			items = append(items, item)
+			continue
+		}
+		seen[i] = true
+
+		compareAmount(&item, transfers[i].Attributes.Amount.Value)
+		items = append(items, item)
+	}
+
+	for i, transfer := range transfers {
+		if seen[i] {
+			continue
+		}
+
+		item := models.ReconciliationItem{
+			Kind:           KindTransfer,
+			Classification: MissingLocally,
+			OrderID:        transfer.Attributes.OrderID,
+			TxHash:         transfer.Attributes.Hash,
+			Detail:         fmt.Sprintf("%s transfer %s has no request", transfer.Attributes.Type, transfer.ID),
+		}
+		if remoteAmount, err := strconv.ParseFloat(transfer.Attributes.Amount.Value, 64); err == nil {
+			item.RemoteAmount = &remoteAmount
+		}
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// compareAmount classifies a matched item by whether Brale's amount, value,
+// agrees with the request's.
+func compareAmount(item *models.ReconciliationItem, value string) {
+	remoteAmount, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		item.Classification = AmountMismatch
+		item.Detail = fmt.Sprintf("Brale amount %q is not a number", value)
+		return
+	}
+	item.RemoteAmount = &remoteAmount
+
+	if ledger.ToUnits(remoteAmount) != ledger.ToUnits(*item.LocalAmount) {
+		item.Classification = AmountMismatch
+		item.Detail = fmt.Sprintf("request amount %v, Brale amount %v", *item.LocalAmount, remoteAmount)
+	} else {
+		item.Classification = Matched
+	}
+}
+
+// Summarize counts the items of each classification onto the report.
+func Summarize(report *models.ReconciliationReport, items []models.ReconciliationItem) {
+	report.Matched, report.MissingLocally, report.MissingRemotely, report.AmountMismatch = 0, 0, 0, 0
+	for _, item := range items {
+		switch item.Classification {
+		case Matched:
+			report.Matched++
+		case MissingLocally:
+			report.MissingLocally++
+		case MissingRemotely:
+			report.MissingRemotely++
+		case AmountMismatch:
+			report.AmountMismatch++
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package reconciliation
+
+import (
+	"mint-redeem-workflow/infra/brale"
+	"mint-redeem-workflow/models"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func order(id string, idempotencyKey string, amount string) brale.Order {
+	o := brale.Order{ID: id, Type: "order"}
+	o.Attributes.IdempotencyKey = idempotencyKey
+	o.Attributes.Amount.Value = amount
+	o.Attributes.Type = "mint"
+	return o
+}
+
+func transfer(id string, hash string, amount string) brale.Transfer {
+	t := brale.Transfer{ID: id, Type: "transaction"}
+	t.Attributes.Hash = hash
+	t.Attributes.Amount.Value = amount
+	t.Attributes.Type = "transfer"
+	return t
+}
+
+func TestReconcile_ClassifiesEveryRequestAndOrder(t *testing.T) {
+	byKey := models.Request{ID: uuid.New(), Amount: 100}
+	byOrderID := models.Request{ID: uuid.New(), Amount: 50, ProviderOrderID: "order-2"}
+	wrongAmount := models.Request{ID: uuid.New(), Amount: 10}
+	notAtBrale := models.Request{ID: uuid.New(), Amount: 5, ProviderOrderID: "order-9"}
+
+	items := Reconcile(
+		[]models.Request{byKey, byOrderID, wrongAmount, notAtBrale},
+		[]brale.Order{
+			order("order-1", byKey.ID.String(), "100.00"),
+			order("order-2", "", "50"),
+			order("order-3", wrongAmount.ID.String(), "10.01"),
+			order("order-4", "someone-else", "7.5"),
+		},
+	)
+
+	classifications := map[string]string{}
+	for _, item := range items {
+		classifications[item.OrderID] = item.Classification
+	}
+	assert.Equal(t, map[string]string{
+		"order-1": Matched,
+		"order-2": Matched,
+		"order-3": AmountMismatch,
+		"order-9": MissingRemotely,
+		"order-4": MissingLocally,
+	}, classifications)
+
+	var report models.ReconciliationReport
+	Summarize(&report, items)
+	assert.Equal(t, 2, report.Matched)
+	assert.Equal(t, 1, report.AmountMismatch)
+	assert.Equal(t, 1, report.MissingRemotely)
+	assert.Equal(t, 1, report.MissingLocally)
+}
+
+func TestReconcile_OrderOnlyMatchesOneRequest(t *testing.T) {
+	first := models.Request{ID: uuid.New(), Amount: 1, ProviderOrderID: "order-1"}
+	second := models.Request{ID: uuid.New(), Amount: 1, ProviderOrderID: "order-1"}
+
+	items := Reconcile([]models.Request{first, second}, []brale.Order{order("order-1", "", "1")})
+
+	assert.Len(t, items, 2)
+	assert.Equal(t, Matched, items[0].Classification)
+	assert.Equal(t, MissingRemotely, items[1].Classification)
+}
+
+func TestReconcileTransfers_ClassifiesEveryRequestAndTransfer(t *testing.T) {
+	settled := models.Request{ID: uuid.New(), Amount: 100, TxHash: "0x1"}
+	wrongAmount := models.Request{ID: uuid.New(), Amount: 10, TxHash: "0x2"}
+	notOnChain := models.Request{ID: uuid.New(), Amount: 5, TxHash: "0x3"}
+	pending := models.Request{ID: uuid.New(), Amount: 1, ProviderOrderID: "order-1"}
+
+	items := ReconcileTransfers(
+		[]models.Request{settled, wrongAmount, notOnChain, pending},
+		[]brale.Transfer{
+			transfer("transfer-1", "0x1", "100.00"),
+			transfer("transfer-2", "0x2", "10.01"),
+			transfer("transfer-4", "0x4", "7.5"),
+		},
+	)
+
+	classifications := map[string]string{}
+	for _, item := range items {
+		assert.Equal(t, KindTransfer, item.Kind)
+		classifications[item.TxHash] = item.Classification
+	}
+	assert.Equal(t, map[string]string{
+		"0x1": Matched,
+		"0x2": AmountMismatch,
+		"0x3": MissingRemotely,
+		"0x4": MissingLocally,
+	}, classifications)
+}
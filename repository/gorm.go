@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"errors"
+	"mint-redeem-workflow/events"
+	"mint-redeem-workflow/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormRequestRepository stores requests in the requests, request_events,
+// request_attempts and approvals tables.
+type GormRequestRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRequestRepository(db *gorm.DB) *GormRequestRepository {
+	return &GormRequestRepository{db: db}
+}
+
+func (r *GormRequestRepository) Create(request *models.Request) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// The unique index on the column backs this up for concurrent creates.
+		if request.ClientReference != nil {
+			var count int64
+			if err := tx.Model(&models.Request{}).Where("client_reference = ?", *request.ClientReference).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return ErrDuplicateClientReference
+			}
+		}
+
+		if err := tx.Create(request).Error; err != nil {
+			return err
+		}
+		return events.RecordStatusChange(tx, request.ID, "", request.Status, "")
+	})
+}
+
+func (r *GormRequestRepository) Get(requestID string) (*models.Request, error) {
+	return get(r.db, requestID)
+}
+
+func get(db *gorm.DB, requestID string) (*models.Request, error) {
+	var request models.Request
+	if err := db.First(&request, "id = ?", requestID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRequestNotFound
+		}
+		return nil, err
+	}
+
+	return &request, nil
+}
+
+func (r *GormRequestRepository) FindByClientReference(clientReference string) (*models.Request, error) {
+	var request models.Request
+	if err := r.db.First(&request, "client_reference = ?", clientReference).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRequestNotFound
+		}
+		return nil, err
+	}
+
+	return &request, nil
+}
+
+// UpdateStatus reads the request outside a transaction and only writes it
+// back if its version hasn't changed since, so no lock is held while the
+// update is applied. It also works on a repository built on a transaction,
+// for callers that write other rows along with the request.
+func (r *GormRequestRepository) UpdateStatus(requestID string, update StatusUpdate) (*models.Request, error) {
+	return retryOnConflict(func() (*models.Request, error) {
+		return r.updateStatus(requestID, update)
+	})
+}
+
+func (r *GormRequestRepository) updateStatus(requestID string, update StatusUpdate) (*models.Request, error) {
+	request, err := get(r.db, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	previousStatus, err := update.apply(request)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := save(tx, request); err != nil {
+			return err
+		}
+		return events.RecordStatusChange(tx, request.ID, previousStatus, request.Status, update.Reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// save writes every column of the request if the row is still at the
+// request's version, and moves both to the next version.
+func save(tx *gorm.DB, request *models.Request) error {
+	version := request.Version
+	request.Version++
+
+	result := tx.Model(request).Where("version = ?", version).Select("*").Omit(clause.Associations).Updates(request)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrVersionConflict
+	}
+	if result.Error != nil {
+		request.Version = version
+		return result.Error
+	}
+
+	return nil
+}
+
+func (r *GormRequestRepository) List(filter RequestFilter) ([]models.Request, error) {
+	query := r.db.Order("created_at desc")
+	if filter.OldestFirst {
+		query = r.db.Order("created_at")
+	}
+	if filter.Submitter != "" {
+		query = query.Where("submitter = ?", filter.Submitter)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.ErrorCode != "" {
+		query = query.Where("error_code = ?", filter.ErrorCode)
+	}
+	if filter.ExecuteAfter != nil {
+		query = query.Where("execute_at >= ?", *filter.ExecuteAfter)
+	}
+	if filter.ExecuteBefore != nil {
+		query = query.Where("execute_at < ?", *filter.ExecuteBefore)
+	}
+
+	var requests []models.Request
+	if err := query.Limit(filter.limit()).Find(&requests).Error; err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+func (r *GormRequestRepository) AppendEvent(event *models.RequestEvent) error {
+	return r.db.Create(event).Error
+}
+
+func (r *GormRequestRepository) ListEvents(requestID string, afterID uint) ([]models.RequestEvent, error) {
+	request, err := get(r.db, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	var requestEvents []models.RequestEvent
+	if err := r.db.Where("request_id = ? AND id > ?", request.ID, afterID).Order("id").Find(&requestEvents).Error; err != nil {
+		return nil, err
+	}
+
+	return requestEvents, nil
+}
+
+func (r *GormRequestRepository) SaveAttempt(attempt *models.RequestAttempt) error {
+	return r.db.Save(attempt).Error
+}
+
+func (r *GormRequestRepository) DeleteAttempt(attempt *models.RequestAttempt) error {
+	return r.db.Delete(attempt).Error
+}
+
+func (r *GormRequestRepository) ListAttempts(requestID string) ([]models.RequestAttempt, error) {
+	request, err := get(r.db, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	var attempts []models.RequestAttempt
+	if err := r.db.Where("request_id = ?", request.ID).Order("attempt").Find(&attempts).Error; err != nil {
+		return nil, err
+	}
+
+	return attempts, nil
+}
+
+func (r *GormRequestRepository) CreateApproval(approval *models.Approval) error {
+	return r.db.Create(approval).Error
+}
+
+func (r *GormRequestRepository) ListApprovals(requestID string) ([]models.Approval, error) {
+	request, err := get(r.db, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	var approvals []models.Approval
+	if err := r.db.Where("request_id = ?", request.ID).Order("created_at, id").Find(&approvals).Error; err != nil {
+		return nil, err
+	}
+
+	return approvals, nil
+}
+
+func (r *GormRequestRepository) Transaction(fn func(requests RequestRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(NewGormRequestRepository(tx))
+	})
+}
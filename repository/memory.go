@@ -0,0 +1,368 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"mint-redeem-workflow/events"
+	"mint-redeem-workflow/models"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemoryRequestRepository keeps requests and what is recorded about them in
+// memory. It behaves like GormRequestRepository, for tests that don't need a
+// database.
+type MemoryRequestRepository struct {
+	mu        sync.Mutex
+	requests  map[uuid.UUID]models.Request
+	events    []models.RequestEvent
+	attempts  []models.RequestAttempt
+	approvals []models.Approval
+
+	// lastAttemptID and lastApprovalID number new rows like the tables'
+	// autoincrement keys, so a deleted ID isn't handed out again.
+	lastAttemptID  uint
+	lastApprovalID uint
+}
+
+func NewMemoryRequestRepository() *MemoryRequestRepository {
+	return &MemoryRequestRepository{requests: map[uuid.UUID]models.Request{}}
+}
+
+func (r *MemoryRequestRepository) Create(request *models.Request) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if request.ClientReference != nil {
+		for _, existing := range r.requests {
+			if existing.ClientReference != nil && *existing.ClientReference == *request.ClientReference {
+				return ErrDuplicateClientReference
+			}
+		}
+	}
+
+	if request.ID == uuid.Nil {
+		request.ID = uuid.New()
+	}
+	if _, ok := r.requests[request.ID]; ok {
+		return fmt.Errorf("request %s already exists", request.ID)
+	}
+	if request.CreatedAt.IsZero() {
+		request.CreatedAt = time.Now()
+	}
+	if request.Attempt == 0 {
+		request.Attempt = 1
+	}
+	if request.Version == 0 {
+		request.Version = 1
+	}
+
+	r.requests[request.ID] = *request
+
+	return r.recordStatusChange(request.ID, "", request.Status, "")
+}
+
+func (r *MemoryRequestRepository) Get(requestID string) (*models.Request, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.get(requestID)
+}
+
+func (r *MemoryRequestRepository) get(requestID string) (*models.Request, error) {
+	id, err := uuid.Parse(requestID)
+	if err != nil {
+		return nil, ErrRequestNotFound
+	}
+
+	request, ok := r.requests[id]
+	if !ok {
+		return nil, ErrRequestNotFound
+	}
+
+	return &request, nil
+}
+
+func (r *MemoryRequestRepository) FindByClientReference(clientReference string) (*models.Request, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, request := range r.requests {
+		if request.ClientReference != nil && *request.ClientReference == clientReference {
+			return &request, nil
+		}
+	}
+
+	return nil, ErrRequestNotFound
+}
+
+// UpdateStatus applies the update without holding the lock, like
+// GormRequestRepository applies it outside a transaction, so an update that
+// races it is retried the same way.
+func (r *MemoryRequestRepository) UpdateStatus(requestID string, update StatusUpdate) (*models.Request, error) {
+	return retryOnConflict(func() (*models.Request, error) {
+		return r.updateStatus(requestID, update)
+	})
+}
+
+func (r *MemoryRequestRepository) updateStatus(requestID string, update StatusUpdate) (*models.Request, error) {
+	request, err := r.Get(requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	previousStatus, err := update.apply(request)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.requests[request.ID].Version != request.Version {
+		return nil, ErrVersionConflict
+	}
+	request.Version++
+
+	if err := r.recordStatusChange(request.ID, previousStatus, request.Status, update.Reason); err != nil {
+		return nil, err
+	}
+	r.requests[request.ID] = *request
+
+	return request, nil
+}
+
+func (r *MemoryRequestRepository) List(filter RequestFilter) ([]models.Request, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var requests []models.Request
+	for _, request := range r.requests {
+		if matches(request, filter) {
+			requests = append(requests, request)
+		}
+	}
+
+	sort.Slice(requests, func(i, j int) bool {
+		if filter.OldestFirst {
+			return requests[i].CreatedAt.Before(requests[j].CreatedAt)
+		}
+		return requests[i].CreatedAt.After(requests[j].CreatedAt)
+	})
+	if len(requests) > filter.limit() {
+		requests = requests[:filter.limit()]
+	}
+
+	return requests, nil
+}
+
+func matches(request models.Request, filter RequestFilter) bool {
+	if filter.Submitter != "" && request.Submitter != filter.Submitter {
+		return false
+	}
+	if filter.Type != "" && request.Type != filter.Type {
+		return false
+	}
+	if filter.Status != "" && request.Status != filter.Status {
+		return false
+	}
+	if filter.ErrorCode != "" && request.ErrorCode != filter.ErrorCode {
+		return false
+	}
+	if filter.ExecuteAfter != nil && (request.ExecuteAt == nil || request.ExecuteAt.Before(*filter.ExecuteAfter)) {
+		return false
+	}
+	if filter.ExecuteBefore != nil && (request.ExecuteAt == nil || !request.ExecuteAt.Before(*filter.ExecuteBefore)) {
+		return false
+	}
+
+	return true
+}
+
+func (r *MemoryRequestRepository) AppendEvent(event *models.RequestEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.appendEvent(event)
+
+	return nil
+}
+
+func (r *MemoryRequestRepository) appendEvent(event *models.RequestEvent) {
+	event.ID = uint(len(r.events) + 1)
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	r.events = append(r.events, *event)
+}
+
+func (r *MemoryRequestRepository) ListEvents(requestID string, afterID uint) ([]models.RequestEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	request, err := r.get(requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	var requestEvents []models.RequestEvent
+	for _, event := range r.events {
+		if event.RequestID == request.ID && event.ID > afterID {
+			requestEvents = append(requestEvents, event)
+		}
+	}
+
+	return requestEvents, nil
+}
+
+// Events returns the request's events, oldest first.
+func (r *MemoryRequestRepository) Events(requestID uuid.UUID) []models.RequestEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var requestEvents []models.RequestEvent
+	for _, event := range r.events {
+		if event.RequestID == requestID {
+			requestEvents = append(requestEvents, event)
+		}
+	}
+
+	return requestEvents
+}
+
+// recordStatusChange records a transition the way events.RecordStatusChange
+// does.
+func (r *MemoryRequestRepository) recordStatusChange(requestID uuid.UUID, from string, to string, reason string) error {
+	if from == to {
+		return nil
+	}
+
+	data, err := json.Marshal(events.StatusChange{From: from, To: to, Reason: reason})
+	if err != nil {
+		return err
+	}
+
+	r.appendEvent(&models.RequestEvent{RequestID: requestID, Type: events.TypeStatusChanged, Data: string(data)})
+
+	return nil
+}
+
+func (r *MemoryRequestRepository) SaveAttempt(attempt *models.RequestAttempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.attempts {
+		if attempt.ID != 0 && existing.ID == attempt.ID {
+			r.attempts[i] = *attempt
+			return nil
+		}
+		// Like the unique index on the table.
+		if existing.RequestID == attempt.RequestID && existing.Attempt == attempt.Attempt {
+			return fmt.Errorf("attempt %d of request %s already exists", attempt.Attempt, attempt.RequestID)
+		}
+	}
+
+	r.lastAttemptID++
+	attempt.ID = r.lastAttemptID
+	if attempt.CreatedAt.IsZero() {
+		attempt.CreatedAt = time.Now()
+	}
+	r.attempts = append(r.attempts, *attempt)
+
+	return nil
+}
+
+func (r *MemoryRequestRepository) DeleteAttempt(attempt *models.RequestAttempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.attempts {
+		if existing.ID == attempt.ID {
+			r.attempts = append(r.attempts[:i:i], r.attempts[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+func (r *MemoryRequestRepository) ListAttempts(requestID string) ([]models.RequestAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	request, err := r.get(requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	var attempts []models.RequestAttempt
+	for _, attempt := range r.attempts {
+		if attempt.RequestID == request.ID {
+			attempts = append(attempts, attempt)
+		}
+	}
+	sort.Slice(attempts, func(i, j int) bool {
+		return attempts[i].Attempt < attempts[j].Attempt
+	})
+
+	return attempts, nil
+}
+
+func (r *MemoryRequestRepository) CreateApproval(approval *models.Approval) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastApprovalID++
+	approval.ID = r.lastApprovalID
+	if approval.CreatedAt.IsZero() {
+		approval.CreatedAt = time.Now()
+	}
+	r.approvals = append(r.approvals, *approval)
+
+	return nil
+}
+
+func (r *MemoryRequestRepository) ListApprovals(requestID string) ([]models.Approval, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	request, err := r.get(requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	var approvals []models.Approval
+	for _, approval := range r.approvals {
+		if approval.RequestID == request.ID {
+			approvals = append(approvals, approval)
+		}
+	}
+
+	return approvals, nil
+}
+
+// Transaction puts back everything fn wrote if it fails. Unlike a database
+// transaction it doesn't hide fn's writes from other callers in the meantime.
+func (r *MemoryRequestRepository) Transaction(fn func(requests RequestRepository) error) error {
+	r.mu.Lock()
+	requests := make(map[uuid.UUID]models.Request, len(r.requests))
+	for id, request := range r.requests {
+		requests[id] = request
+	}
+	requestEvents := append([]models.RequestEvent(nil), r.events...)
+	attempts := append([]models.RequestAttempt(nil), r.attempts...)
+	approvals := append([]models.Approval(nil), r.approvals...)
+	r.mu.Unlock()
+
+	if err := fn(r); err != nil {
+		r.mu.Lock()
+		r.requests, r.events, r.attempts, r.approvals = requests, requestEvents, attempts, approvals
+		r.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
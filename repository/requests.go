@@ -0,0 +1,167 @@
+// Package repository stores requests with their event history, retry attempts
+// and approval trail. The GORM
+// implementation is what the service runs on, and the in-memory one lets tests
+// run without a database.
+package repository
+
+import (
+	"errors"
+	"mint-redeem-workflow/models"
+	"time"
+)
+
+const (
+	defaultListLimit = 100
+
+	// maxUpdateAttempts is how many times UpdateStatus reads and writes a
+	// request before giving up on updates that keep racing it.
+	maxUpdateAttempts = 5
+)
+
+var (
+	ErrRequestNotFound          = errors.New("request not found")
+	ErrDuplicateClientReference = errors.New("a request with this client reference already exists")
+	ErrStatusConflict           = errors.New("request status has changed")
+	ErrVersionConflict          = errors.New("request was changed by another update")
+)
+
+// RequestRepository stores requests. Status changes go through UpdateStatus,
+// which records each transition in the request's event history together with
+// the change. Every write bumps the request's Version and only succeeds if the
+// version is still the one that was read, so concurrent writers can't
+// overwrite each other.
+type RequestRepository interface {
+	// Create saves a new request and records the status it starts in. It
+	// returns ErrDuplicateClientReference if the client reference is taken.
+	Create(request *models.Request) error
+
+	// Get returns the request, or ErrRequestNotFound.
+	Get(requestID string) (*models.Request, error)
+
+	// FindByClientReference returns the request submitted with the client
+	// reference, or ErrRequestNotFound.
+	FindByClientReference(clientReference string) (*models.Request, error)
+
+	// UpdateStatus applies the update if the request is still in
+	// update.From and returns the request as saved. It returns
+	// ErrStatusConflict if the request has moved on. If another write lands
+	// between reading and writing the request, the update is applied again
+	// to the new version, and ErrVersionConflict is returned once that has
+	// failed maxUpdateAttempts times.
+	UpdateStatus(requestID string, update StatusUpdate) (*models.Request, error)
+
+	// List returns the newest requests first, or the oldest first if
+	// filter.OldestFirst is set.
+	List(filter RequestFilter) ([]models.Request, error)
+
+	// AppendEvent adds an event to a request's history.
+	AppendEvent(event *models.RequestEvent) error
+
+	// ListEvents returns the request's events after afterID, oldest first,
+	// or ErrRequestNotFound.
+	ListEvents(requestID string, afterID uint) ([]models.RequestEvent, error)
+
+	// SaveAttempt creates the retry attempt, or updates it once it has an ID.
+	SaveAttempt(attempt *models.RequestAttempt) error
+
+	// DeleteAttempt removes a retry attempt whose run never started.
+	DeleteAttempt(attempt *models.RequestAttempt) error
+
+	// ListAttempts returns the request's retry attempts, oldest first, or
+	// ErrRequestNotFound.
+	ListAttempts(requestID string) ([]models.RequestAttempt, error)
+
+	// CreateApproval adds a decision to the request's approval trail.
+	CreateApproval(approval *models.Approval) error
+
+	// ListApprovals returns the request's approval trail, oldest first, or
+	// ErrRequestNotFound.
+	ListApprovals(requestID string) ([]models.Approval, error)
+
+	// Transaction calls fn with a repository whose writes are kept together
+	// if fn returns nil and undone together if it returns an error.
+	Transaction(fn func(requests RequestRepository) error) error
+}
+
+// StatusUpdate moves a request from one status to another.
+type StatusUpdate struct {
+	// From is the status the request has to be in. Empty matches any status.
+	From string
+
+	// To is the new status. Empty keeps the status, so Apply can change other
+	// fields without recording a transition.
+	To string
+
+	// Reason is recorded with the transition.
+	Reason string
+
+	// Check can refuse the update with an error. Like From, it sees the
+	// request as it is when the update lands, so the update only happens
+	// while the check holds.
+	Check func(request *models.Request) error
+
+	// Apply sets the fields that change along with the status. It can be nil.
+	// It is called again on a fresh copy of the request when the update is
+	// retried, so it should only set fields.
+	Apply func(request *models.Request)
+}
+
+// retryOnConflict runs update until it doesn't fail with ErrVersionConflict,
+// at most maxUpdateAttempts times.
+func retryOnConflict(update func() (*models.Request, error)) (*models.Request, error) {
+	var (
+		request *models.Request
+		err     error
+	)
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		request, err = update()
+		if !errors.Is(err, ErrVersionConflict) {
+			break
+		}
+	}
+
+	return request, err
+}
+
+// apply checks update.From against the request and applies the update to it.
+// It returns the status the request was in.
+func (update StatusUpdate) apply(request *models.Request) (string, error) {
+	previousStatus := request.Status
+	if update.From != "" && previousStatus != update.From {
+		return "", ErrStatusConflict
+	}
+	if update.Check != nil {
+		if err := update.Check(request); err != nil {
+			return "", err
+		}
+	}
+	if update.Apply != nil {
+		update.Apply(request)
+	}
+	if update.To != "" {
+		request.Status = update.To
+	}
+
+	return previousStatus, nil
+}
+
+// RequestFilter narrows List. Empty fields match everything. ExecuteAfter and
+// ExecuteBefore only match future-dated requests.
+type RequestFilter struct {
+	Submitter     string
+	Type          string
+	Status        string
+	ErrorCode     string
+	ExecuteAfter  *time.Time
+	ExecuteBefore *time.Time
+	OldestFirst   bool
+	Limit         int
+}
+
+func (f RequestFilter) limit() int {
+	if f.Limit <= 0 || f.Limit > defaultListLimit {
+		return defaultListLimit
+	}
+
+	return f.Limit
+}
@@ -0,0 +1,452 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"mint-redeem-workflow/events"
+	"mint-redeem-workflow/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type repositoryTest struct {
+	repo RequestRepository
+
+	// events returns the request's events, oldest first.
+	events func(requestID uuid.UUID) []models.RequestEvent
+}
+
+// forEachRepository runs the test against both implementations, so the
+// in-memory one stays a stand-in for the database.
+func forEachRepository(t *testing.T, test func(t *testing.T, rt repositoryTest)) {
+	t.Run("gorm", func(t *testing.T) {
+		db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+		require.NoError(t, err)
+		require.NoError(t, db.AutoMigrate(&models.Request{}, &models.RequestEvent{}, &models.RequestAttempt{}, &models.Approval{}))
+
+		test(t, repositoryTest{
+			repo: NewGormRequestRepository(db),
+			events: func(requestID uuid.UUID) []models.RequestEvent {
+				var requestEvents []models.RequestEvent
+				require.NoError(t, db.Order("id").Find(&requestEvents, "request_id = ?", requestID).Error)
+				return requestEvents
+			},
+		})
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		repo := NewMemoryRequestRepository()
+		test(t, repositoryTest{repo: repo, events: repo.Events})
+	})
+}
+
+func newRequest(requestType string, status string) *models.Request {
+	return &models.Request{Type: requestType, Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", Status: status}
+}
+
+func transitions(t *testing.T, requestEvents []models.RequestEvent) []events.StatusChange {
+	var changes []events.StatusChange
+	for _, event := range requestEvents {
+		require.Equal(t, events.TypeStatusChanged, event.Type)
+		var change events.StatusChange
+		require.NoError(t, json.Unmarshal([]byte(event.Data), &change))
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+func TestCreate_AssignsIDAndRecordsStatus(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, rt repositoryTest) {
+		request := newRequest("mint", "pending")
+		require.NoError(t, rt.repo.Create(request))
+		assert.NotEqual(t, uuid.Nil, request.ID)
+
+		saved, err := rt.repo.Get(request.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "mint", saved.Type)
+		assert.Equal(t, 1, saved.Attempt)
+		assert.Equal(t, []events.StatusChange{{To: "pending"}}, transitions(t, rt.events(request.ID)))
+	})
+}
+
+func TestCreate_DuplicateClientReference(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, rt repositoryTest) {
+		clientReference := "ref-1"
+		first := newRequest("mint", "pending")
+		first.ClientReference = &clientReference
+		require.NoError(t, rt.repo.Create(first))
+
+		second := newRequest("mint", "pending")
+		second.ClientReference = &clientReference
+		assert.ErrorIs(t, rt.repo.Create(second), ErrDuplicateClientReference)
+	})
+}
+
+func TestGet_UnknownIDReturnsNotFound(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, rt repositoryTest) {
+		_, err := rt.repo.Get(uuid.New().String())
+		assert.ErrorIs(t, err, ErrRequestNotFound)
+
+		_, err = rt.repo.Get("not-a-uuid")
+		assert.ErrorIs(t, err, ErrRequestNotFound)
+	})
+}
+
+func TestFindByClientReference_ReturnsTheRequest(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, rt repositoryTest) {
+		clientReference := "ref-1"
+		request := newRequest("mint", "pending")
+		request.ClientReference = &clientReference
+		require.NoError(t, rt.repo.Create(request))
+
+		found, err := rt.repo.FindByClientReference(clientReference)
+		require.NoError(t, err)
+		assert.Equal(t, request.ID, found.ID)
+
+		_, err = rt.repo.FindByClientReference("ref-2")
+		assert.ErrorIs(t, err, ErrRequestNotFound)
+	})
+}
+
+func TestUpdateStatus_AppliesChangeAndRecordsTransition(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, rt repositoryTest) {
+		request := newRequest("mint", "started")
+		require.NoError(t, rt.repo.Create(request))
+
+		updated, err := rt.repo.UpdateStatus(request.ID.String(), StatusUpdate{
+			From:   "started",
+			To:     "failed",
+			Reason: "brale_validation_error",
+			Apply: func(request *models.Request) {
+				request.ErrorCode = "brale_validation_error"
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "failed", updated.Status)
+
+		saved, err := rt.repo.Get(request.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "failed", saved.Status)
+		assert.Equal(t, "brale_validation_error", saved.ErrorCode)
+		assert.Equal(t, []events.StatusChange{
+			{To: "started"},
+			{From: "started", To: "failed", Reason: "brale_validation_error"},
+		}, transitions(t, rt.events(request.ID)))
+	})
+}
+
+func TestUpdateStatus_WrongFromStatusConflicts(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, rt repositoryTest) {
+		request := newRequest("mint", "completed")
+		require.NoError(t, rt.repo.Create(request))
+
+		_, err := rt.repo.UpdateStatus(request.ID.String(), StatusUpdate{From: "started", To: "failed"})
+		assert.ErrorIs(t, err, ErrStatusConflict)
+
+		saved, err := rt.repo.Get(request.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "completed", saved.Status)
+		assert.Len(t, rt.events(request.ID), 1)
+	})
+}
+
+func TestUpdateStatus_EmptyToKeepsStatus(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, rt repositoryTest) {
+		request := newRequest("mint", "started")
+		require.NoError(t, rt.repo.Create(request))
+
+		_, err := rt.repo.UpdateStatus(request.ID.String(), StatusUpdate{Apply: func(request *models.Request) {
+			request.RunID = "run-id"
+		}})
+		require.NoError(t, err)
+
+		saved, err := rt.repo.Get(request.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "started", saved.Status)
+		assert.Equal(t, "run-id", saved.RunID)
+		assert.Len(t, rt.events(request.ID), 1)
+
+		_, err = rt.repo.UpdateStatus(uuid.New().String(), StatusUpdate{To: "failed"})
+		assert.ErrorIs(t, err, ErrRequestNotFound)
+	})
+}
+
+func TestUpdateStatus_BumpsVersion(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, rt repositoryTest) {
+		request := newRequest("mint", "pending")
+		require.NoError(t, rt.repo.Create(request))
+
+		saved, err := rt.repo.Get(request.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, 1, saved.Version)
+
+		updated, err := rt.repo.UpdateStatus(request.ID.String(), StatusUpdate{To: "started"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, updated.Version)
+
+		saved, err = rt.repo.Get(request.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, 2, saved.Version)
+	})
+}
+
+func TestUpdateStatus_RetriesWhenAnotherUpdateRacesIt(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, rt repositoryTest) {
+		request := newRequest("mint", "started")
+		require.NoError(t, rt.repo.Create(request))
+
+		calls := 0
+		updated, err := rt.repo.UpdateStatus(request.ID.String(), StatusUpdate{
+			From: "started",
+			To:   "completed",
+			Apply: func(request *models.Request) {
+				calls++
+				if calls == 1 {
+					// Another writer saves the request after it was read.
+					_, err := rt.repo.UpdateStatus(request.ID.String(), StatusUpdate{Apply: func(request *models.Request) {
+						request.ProviderOrderID = "order-1"
+					}})
+					require.NoError(t, err)
+				}
+				request.TxHash = "0xhash"
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, 3, updated.Version)
+
+		saved, err := rt.repo.Get(request.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "completed", saved.Status)
+		assert.Equal(t, "order-1", saved.ProviderOrderID)
+		assert.Equal(t, "0xhash", saved.TxHash)
+		assert.Equal(t, []events.StatusChange{
+			{To: "started"},
+			{From: "started", To: "completed"},
+		}, transitions(t, rt.events(request.ID)))
+	})
+}
+
+func TestUpdateStatus_RaceThatMovesStatusConflicts(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, rt repositoryTest) {
+		request := newRequest("mint", "started")
+		require.NoError(t, rt.repo.Create(request))
+
+		calls := 0
+		_, err := rt.repo.UpdateStatus(request.ID.String(), StatusUpdate{
+			From: "started",
+			To:   "canceled",
+			Apply: func(request *models.Request) {
+				calls++
+				_, err := rt.repo.UpdateStatus(request.ID.String(), StatusUpdate{From: "started", To: "completed"})
+				require.NoError(t, err)
+			},
+		})
+		assert.ErrorIs(t, err, ErrStatusConflict)
+		assert.Equal(t, 1, calls)
+
+		saved, err := rt.repo.Get(request.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "completed", saved.Status)
+	})
+}
+
+func TestUpdateStatus_GivesUpOnUpdatesThatKeepRacing(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, rt repositoryTest) {
+		request := newRequest("mint", "started")
+		require.NoError(t, rt.repo.Create(request))
+
+		calls := 0
+		_, err := rt.repo.UpdateStatus(request.ID.String(), StatusUpdate{
+			Apply: func(request *models.Request) {
+				calls++
+				_, err := rt.repo.UpdateStatus(request.ID.String(), StatusUpdate{})
+				require.NoError(t, err)
+				request.TxHash = "0xhash"
+			},
+		})
+		assert.ErrorIs(t, err, ErrVersionConflict)
+		assert.Equal(t, maxUpdateAttempts, calls)
+
+		saved, err := rt.repo.Get(request.ID.String())
+		require.NoError(t, err)
+		assert.Empty(t, saved.TxHash)
+		assert.Equal(t, 1+maxUpdateAttempts, saved.Version)
+	})
+}
+
+func TestList_FiltersNewestFirst(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, rt repositoryTest) {
+		now := time.Now()
+		executeAt := now.Add(time.Hour)
+		for i, request := range []*models.Request{
+			newRequest("mint", "failed"),
+			newRequest("mint", "completed"),
+			newRequest("redeem", "failed"),
+			newRequest("mint", "scheduled"),
+		} {
+			request.CreatedAt = now.Add(time.Duration(i) * time.Second)
+			if request.Status == "scheduled" {
+				request.ExecuteAt = &executeAt
+			}
+			if request.Status == "completed" {
+				request.Submitter = "globex"
+			}
+			require.NoError(t, rt.repo.Create(request))
+		}
+
+		requests, err := rt.repo.List(RequestFilter{Status: "failed"})
+		require.NoError(t, err)
+		require.Len(t, requests, 2)
+		assert.Equal(t, "redeem", requests[0].Type)
+		assert.Equal(t, "mint", requests[1].Type)
+
+		requests, err = rt.repo.List(RequestFilter{Status: "failed", OldestFirst: true})
+		require.NoError(t, err)
+		require.Len(t, requests, 2)
+		assert.Equal(t, "mint", requests[0].Type)
+		assert.Equal(t, "redeem", requests[1].Type)
+
+		requests, err = rt.repo.List(RequestFilter{Type: "mint", Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, requests, 2)
+		assert.Equal(t, "scheduled", requests[0].Status)
+		assert.Equal(t, "completed", requests[1].Status)
+
+		requests, err = rt.repo.List(RequestFilter{Submitter: "globex"})
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+		assert.Equal(t, "completed", requests[0].Status)
+
+		after := now
+		requests, err = rt.repo.List(RequestFilter{ExecuteAfter: &after})
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+		assert.Equal(t, "scheduled", requests[0].Status)
+	})
+}
+
+func TestAppendEvent_AddsToHistory(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, rt repositoryTest) {
+		request := newRequest("mint", "pending")
+		require.NoError(t, rt.repo.Create(request))
+
+		event := models.RequestEvent{RequestID: request.ID, Type: "note", Data: `{"text":"called the client"}`}
+		require.NoError(t, rt.repo.AppendEvent(&event))
+		assert.NotZero(t, event.ID)
+
+		requestEvents := rt.events(request.ID)
+		require.Len(t, requestEvents, 2)
+		assert.Equal(t, "note", requestEvents[1].Type)
+	})
+}
+
+func TestListEvents_ReturnsEventsAfterID(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, rt repositoryTest) {
+		request := newRequest("mint", "pending")
+		require.NoError(t, rt.repo.Create(request))
+		_, err := rt.repo.UpdateStatus(request.ID.String(), StatusUpdate{From: "pending", To: "started"})
+		require.NoError(t, err)
+
+		requestEvents, err := rt.repo.ListEvents(request.ID.String(), 0)
+		require.NoError(t, err)
+		require.Len(t, requestEvents, 2)
+
+		requestEvents, err = rt.repo.ListEvents(request.ID.String(), requestEvents[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, []events.StatusChange{{From: "pending", To: "started"}}, transitions(t, requestEvents))
+
+		_, err = rt.repo.ListEvents(uuid.New().String(), 0)
+		assert.ErrorIs(t, err, ErrRequestNotFound)
+	})
+}
+
+func TestSaveAttempt_CreatesUpdatesAndDeletes(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, rt repositoryTest) {
+		request := newRequest("mint", "failed")
+		require.NoError(t, rt.repo.Create(request))
+
+		attempt := models.RequestAttempt{RequestID: request.ID, Attempt: 2, RetriedBy: "operator"}
+		require.NoError(t, rt.repo.SaveAttempt(&attempt))
+		assert.NotZero(t, attempt.ID)
+		assert.Error(t, rt.repo.SaveAttempt(&models.RequestAttempt{RequestID: request.ID, Attempt: 2}))
+
+		attempt.RunID = "run-2"
+		require.NoError(t, rt.repo.SaveAttempt(&attempt))
+		attempts, err := rt.repo.ListAttempts(request.ID.String())
+		require.NoError(t, err)
+		require.Len(t, attempts, 1)
+		assert.Equal(t, "run-2", attempts[0].RunID)
+
+		require.NoError(t, rt.repo.DeleteAttempt(&attempt))
+		attempts, err = rt.repo.ListAttempts(request.ID.String())
+		require.NoError(t, err)
+		assert.Empty(t, attempts)
+
+		_, err = rt.repo.ListAttempts(uuid.New().String())
+		assert.ErrorIs(t, err, ErrRequestNotFound)
+	})
+}
+
+func TestCreateApproval_AddsToTrail(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, rt repositoryTest) {
+		request := newRequest("mint", "awaiting_approval")
+		require.NoError(t, rt.repo.Create(request))
+
+		require.NoError(t, rt.repo.CreateApproval(&models.Approval{RequestID: request.ID, Approver: "alice", Approved: true}))
+		require.NoError(t, rt.repo.CreateApproval(&models.Approval{RequestID: request.ID, Approver: "bob", Approved: false}))
+
+		approvals, err := rt.repo.ListApprovals(request.ID.String())
+		require.NoError(t, err)
+		require.Len(t, approvals, 2)
+		assert.Equal(t, "alice", approvals[0].Approver)
+		assert.Equal(t, "bob", approvals[1].Approver)
+
+		_, err = rt.repo.ListApprovals(uuid.New().String())
+		assert.ErrorIs(t, err, ErrRequestNotFound)
+	})
+}
+
+func TestTransaction_UndoesEveryWriteWhenFnFails(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, rt repositoryTest) {
+		request := newRequest("mint", "failed")
+		require.NoError(t, rt.repo.Create(request))
+
+		failure := errors.New("start failed")
+		err := rt.repo.Transaction(func(requests RequestRepository) error {
+			if err := requests.SaveAttempt(&models.RequestAttempt{RequestID: request.ID, Attempt: 2}); err != nil {
+				return err
+			}
+			if _, err := requests.UpdateStatus(request.ID.String(), StatusUpdate{From: "failed", To: "started"}); err != nil {
+				return err
+			}
+			return failure
+		})
+		assert.ErrorIs(t, err, failure)
+
+		saved, err := rt.repo.Get(request.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "failed", saved.Status)
+		attempts, err := rt.repo.ListAttempts(request.ID.String())
+		require.NoError(t, err)
+		assert.Empty(t, attempts)
+		assert.Len(t, rt.events(request.ID), 1)
+
+		err = rt.repo.Transaction(func(requests RequestRepository) error {
+			_, err := requests.UpdateStatus(request.ID.String(), StatusUpdate{From: "failed", To: "started"})
+			return err
+		})
+		require.NoError(t, err)
+		saved, err = rt.repo.Get(request.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "started", saved.Status)
+	})
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/repository"
+	"mint-redeem-workflow/worker/workflows"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRequestNotFound        = repository.ErrRequestNotFound
+	ErrRequestConflict        = repository.ErrVersionConflict
+	ErrNotAwaitingApproval    = errors.New("request is not awaiting approval")
+	ErrSubmitterCannotApprove = errors.New("submitter can't approve their own request")
+	ErrDuplicateApprover      = errors.New("approver has already decided on this request")
+	ErrUnknownApprover        = errors.New("approver has no registered key")
+	ErrInvalidSignature       = errors.New("invalid approval signature")
+)
+
+// SignalApproval sends the calling client's approval decision to the
+// request's workflow. The approver is the one whose key is bound to the
+// client, and the signal carries both. Only requests that are waiting on the
+// approval gate can be approved or rejected, and only by a client other than
+// the submitter whose approver hasn't decided yet, with a valid signature of
+// the decision. The workflow enforces the same rules, these checks just fail
+// fast. Workflows started before decisions were signed take a signature of the
+// request's approval payload, so their approval state says which one to check.
+func (s *Service) SignalApproval(client string, requestID string, signal workflows.ApprovalSignal) error {
+	request, err := s.requests.Get(requestID)
+	if err != nil {
+		return err
+	}
+
+	if request.Submitter != "" && request.Submitter == client {
+		return ErrSubmitterCannotApprove
+	}
+
+	if request.Status != "awaiting_approval" {
+		return ErrNotAwaitingApproval
+	}
+
+	var key models.ApproverKey
+	if err := s.db.First(&key, "client = ?", client).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUnknownApprover
+		}
+		return err
+	}
+	signal.Approver = key.Approver
+	signal.Client = client
+
+	trail, err := s.requests.ListApprovals(requestID)
+	if err != nil {
+		return err
+	}
+	for _, approval := range trail {
+		if approval.Approver == signal.Approver {
+			return ErrDuplicateApprover
+		}
+	}
+
+	value, err := s.cadenceClient.QueryWorkflow(context.Background(), request.ID.String(), "", workflows.ApprovalStateQuery)
+	if err != nil {
+		return err
+	}
+	var state workflows.ApprovalState
+	if err := value.Get(&state); err != nil {
+		return err
+	}
+
+	payload := request.ApprovalPayload()
+	if state.SignsDecision {
+		payload = request.ApprovalDecisionPayload(key.Approver, signal.Approved)
+	}
+	if err := key.Verify(payload, signal.Signature); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	return s.cadenceClient.SignalWorkflow(context.Background(), request.ID.String(), "", workflows.ApprovalSignalName, signal)
+}
+
+// GetApprovals returns the live approval state from the workflow of the
+// client's request along with the approval trail recorded on the request.
+func (s *Service) GetApprovals(client string, requestID string) (*workflows.ApprovalState, []models.Approval, error) {
+	request, err := s.GetRequest(client, requestID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	value, err := s.cadenceClient.QueryWorkflow(context.Background(), request.ID.String(), "", workflows.ApprovalStateQuery)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var state workflows.ApprovalState
+	if err := value.Get(&state); err != nil {
+		return nil, nil, err
+	}
+
+	trail, err := s.requests.ListApprovals(requestID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &state, trail, nil
+}
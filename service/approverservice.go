@@ -0,0 +1,108 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mint-redeem-workflow/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidPublicKey         = errors.New("public key must be a base64 encoded ed25519 key")
+	ErrApproverKeyExists        = errors.New("approver already has a registered key")
+	ErrClientHasApproverKey     = errors.New("client already has a registered approver key")
+	ErrUnknownClient            = errors.New("client is not a configured API client")
+	ErrInvalidRotationSignature = errors.New("key rotation must be signed with the approver's current key")
+)
+
+// RegisterApproverKey stores the approver's ed25519 public key and binds it
+// to the API client the approver calls the API as. Each approver and each
+// client can have one key, and only operators may register it. Changing it
+// afterwards takes RotateApproverKey.
+func (s *Service) RegisterApproverKey(approver string, client string, publicKey string) (*models.ApproverKey, error) {
+	if err := validatePublicKey(publicKey); err != nil {
+		return nil, err
+	}
+	if !s.isAPIClient(client) {
+		return nil, ErrUnknownClient
+	}
+
+	var existing []models.ApproverKey
+	if err := s.db.Where("approver = ? OR client = ?", approver, client).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	for _, key := range existing {
+		if key.Approver == approver {
+			return nil, ErrApproverKeyExists
+		}
+		return nil, ErrClientHasApproverKey
+	}
+
+	key := models.ApproverKey{
+		Approver:  approver,
+		Client:    client,
+		PublicKey: publicKey,
+	}
+	if err := s.db.Create(&key).Error; err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// RotateApproverKey replaces the approver's key with publicKey. signature is
+// the base64 signature of the old key's RotationPayload for publicKey, made
+// with the old private key, so only the holder of the current key can replace
+// it.
+func (s *Service) RotateApproverKey(approver string, publicKey string, signature string) (*models.ApproverKey, error) {
+	if err := validatePublicKey(publicKey); err != nil {
+		return nil, err
+	}
+
+	var key models.ApproverKey
+	if err := s.db.First(&key, "approver = ?", approver).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUnknownApprover
+		}
+		return nil, err
+	}
+
+	if err := key.Verify(key.RotationPayload(publicKey), signature); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRotationSignature, err)
+	}
+
+	// The old key in the condition keeps two rotations signed with it from
+	// both going through.
+	result := s.db.Model(&models.ApproverKey{}).Where("approver = ? AND public_key = ?", approver, key.PublicKey).Update("public_key", publicKey)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrInvalidRotationSignature
+	}
+
+	key.PublicKey = publicKey
+	return &key, nil
+}
+
+func (s *Service) isAPIClient(name string) bool {
+	for _, client := range s.cfg.APIClients {
+		if client.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validatePublicKey(publicKey string) error {
+	decoded, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(decoded) != ed25519.PublicKeySize {
+		return ErrInvalidPublicKey
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/repository"
+	"mint-redeem-workflow/worker/workflows"
+	"time"
+
+	"go.uber.org/cadence/client"
+	"gorm.io/gorm"
+)
+
+var ErrBatchNotFound = errors.New("batch not found")
+
+// errorCodeStartFailed is the error code of a batch's requests whose batch
+// workflow couldn't be started.
+const errorCodeStartFailed = "workflow_start_failed"
+
+// ProcessBatch saves the batch and its requests and starts the batch workflow.
+// batchRequests and workflowParam.Items must be in the same order. If the
+// workflow can't be started, the batch and its requests are marked failed so
+// that the requests can be retried.
+func (s *Service) ProcessBatch(batch *models.Batch, batchRequests []models.Request, workflowParam workflows.BatchInput) error {
+	batch.Status = "pending"
+	if err := s.db.Create(batch).Error; err != nil {
+		return err
+	}
+
+	err := s.requests.Transaction(func(tx repository.RequestRepository) error {
+		for i := range batchRequests {
+			batchRequests[i].BatchID = &batch.ID
+			batchRequests[i].Status = "pending"
+			if err := tx.Create(&batchRequests[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		if deleteErr := s.db.Delete(batch).Error; deleteErr != nil {
+			return fmt.Errorf("%v, and deleting the batch failed: %w", err, deleteErr)
+		}
+		return err
+	}
+
+	workflowParam.BatchID = batch.ID.String()
+	for i, item := range workflowParam.Items {
+		item.Input().RequestID = batchRequests[i].ID.String()
+	}
+
+	workflowOptions := client.StartWorkflowOptions{
+		ID:                           batch.ID.String(),
+		TaskList:                     s.cfg.CadenceTaskList,
+		ExecutionStartToCloseTimeout: batchExecutionTimeout(workflowParam),
+	}
+
+	workflowRun, err := s.cadenceClient.ExecuteWorkflow(context.Background(), workflowOptions, workflows.BatchWorkflow, workflowParam)
+	if err != nil {
+		if failErr := s.failBatchStart(batch, batchRequests, err); failErr != nil {
+			return fmt.Errorf("%v, and marking the batch failed failed: %w", err, failErr)
+		}
+		return err
+	}
+
+	batch.Status = "started"
+	batch.RunID = workflowRun.GetRunID()
+
+	return s.db.Model(batch).Updates(map[string]interface{}{"status": batch.Status, "run_id": batch.RunID}).Error
+}
+
+// failBatchStart marks the batch and its requests, which are all still
+// pending, as failed with the error that kept the batch workflow from
+// starting.
+func (s *Service) failBatchStart(batch *models.Batch, batchRequests []models.Request, startErr error) error {
+	for i := range batchRequests {
+		saved, err := s.requests.UpdateStatus(batchRequests[i].ID.String(), repository.StatusUpdate{
+			From:   "pending",
+			To:     "failed",
+			Reason: errorCodeStartFailed,
+			Apply: func(request *models.Request) {
+				now := time.Now()
+				request.ErrorCode = errorCodeStartFailed
+				request.ErrorDetail = startErr.Error()
+				request.FailedAt = &now
+			},
+		})
+		if err != nil {
+			return err
+		}
+		batchRequests[i] = *saved
+	}
+
+	batch.Status = "failed"
+	return s.db.Model(batch).Update("status", batch.Status).Error
+}
+
+// batchExecutionTimeout leaves room for every wave of children to take as long
+// as the slowest child is allowed to.
+func batchExecutionTimeout(workflowParam workflows.BatchInput) time.Duration {
+	var slowest time.Duration
+	for _, item := range workflowParam.Items {
+		timeout := time.Minute*5 + item.Input().ApprovalTimeout
+		if timeout > slowest {
+			slowest = timeout
+		}
+	}
+
+	concurrency := workflowParam.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	waves := (len(workflowParam.Items) + concurrency - 1) / concurrency
+
+	return time.Duration(waves)*slowest + time.Minute*5
+}
+
+func (s *Service) GetBatch(batchID string) (*models.Batch, error) {
+	var batch models.Batch
+	if err := s.db.Preload("Requests", func(query *gorm.DB) *gorm.DB {
+		return query.Order("created_at")
+	}).First(&batch, "id = ?", batchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBatchNotFound
+		}
+		return nil, err
+	}
+
+	return &batch, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"mint-redeem-workflow/ledger"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/operations"
+	"mint-redeem-workflow/repository"
+
+	"gorm.io/gorm"
+)
+
+var ErrNotRefundable = errors.New("only completed requests can be refunded")
+
+func (s *Service) GetBalances(account string) ([]ledger.Balance, error) {
+	return ledger.Balances(s.db, account)
+}
+
+func (s *Service) ListJournalEntries(filter ledger.EntryFilter) ([]models.JournalEntry, error) {
+	if filter.Limit <= 0 || filter.Limit > defaultListLimit {
+		filter.Limit = defaultListLimit
+	}
+
+	return ledger.Entries(s.db, filter)
+}
+
+// RefundRequest records that a completed request was refunded: it moves the
+// request to "refunded" and reverses its ledger posting, then notifies the
+// submitter's webhooks. The status change claims the refund, so two refunds of
+// the same request can't both reverse it; if the reversal fails, the request
+// goes back to "completed".
+func (s *Service) RefundRequest(requestID string, reason string) (*models.JournalEntry, error) {
+	request, err := s.requests.UpdateStatus(requestID, repository.StatusUpdate{
+		From:   "completed",
+		To:     "refunded",
+		Reason: reason,
+		Apply: func(request *models.Request) {
+			request.Reason = reason
+		},
+	})
+	if errors.Is(err, repository.ErrStatusConflict) {
+		return nil, ErrNotRefundable
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var reversal *models.JournalEntry
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// Completed requests are posted by the workflow, but post here too in
+		// case the refund lands before the posting activity has run. The
+		// posting is of the request as it completed, not as it is now.
+		completed := *request
+		completed.Status = "completed"
+		if _, err := operations.PostRequest(tx, completed); err != nil {
+			return err
+		}
+
+		reversal, err = ledger.Reverse(tx, request.ID, reason)
+		return err
+	})
+	if err != nil {
+		if _, restoreErr := s.requests.UpdateStatus(requestID, repository.StatusUpdate{
+			From:   "refunded",
+			To:     "completed",
+			Reason: "refund failed: " + err.Error(),
+		}); restoreErr != nil {
+			return nil, fmt.Errorf("%v, and restoring the request failed: %w", err, restoreErr)
+		}
+		return nil, err
+	}
+
+	return reversal, nil
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/repository"
+
+	"go.uber.org/cadence/.gen/go/shared"
+)
+
+var (
+	ErrNotCancelable = errors.New("only requests that haven't finished can be canceled")
+	ErrOrderPlaced   = errors.New("request's order was already sent to Brale and can't be canceled, reconcile it instead")
+)
+
+// activeStatuses are the statuses of a request whose workflow is still running.
+var activeStatuses = []string{"pending", "scheduled", "started", "awaiting_approval"}
+
+// IsActiveStatus reports whether a request in the status still has a running
+// workflow.
+func IsActiveStatus(status string) bool {
+	for _, active := range activeStatuses {
+		if active == status {
+			return true
+		}
+	}
+	return false
+}
+
+// ListRequestEvents returns the event history of the client's request after
+// afterID, oldest first.
+func (s *Service) ListRequestEvents(client string, requestID string, afterID uint) ([]models.RequestEvent, error) {
+	if _, err := s.GetRequest(client, requestID); err != nil {
+		return nil, err
+	}
+
+	return s.requests.ListEvents(requestID, afterID)
+}
+
+// CancelRequest stops a request that hasn't finished. A future-dated request
+// is canceled through its workflow like the API does. Any other request is
+// marked canceled with the reason and then its workflow is terminated. A
+// request whose order was already sent to Brale is refused: the request is
+// only marked canceled while it hasn't been sent, and the operation activity
+// won't send an order for a canceled request, so only one of the two happens.
+func (s *Service) CancelRequest(requestID string, reason string) (*models.Request, error) {
+	request, err := s.requests.Get(requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Status == "scheduled" {
+		if err := cancelable(request); err != nil {
+			return nil, err
+		}
+		return request, s.CancelScheduledRequest("", requestID)
+	}
+
+	request, err = s.requests.UpdateStatus(requestID, repository.StatusUpdate{
+		To:     "canceled",
+		Reason: reason,
+		Check:  cancelable,
+		Apply: func(request *models.Request) {
+			request.Reason = reason
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.cadenceClient.TerminateWorkflow(context.Background(), request.ID.String(), "", reason, nil)
+	var notExists *shared.EntityNotExistsError
+	if err != nil && !errors.As(err, &notExists) {
+		return nil, fmt.Errorf("request was canceled but its workflow couldn't be terminated: %w", err)
+	}
+
+	return request, nil
+}
+
+// cancelable reports why a request can't be canceled, if it can't.
+func cancelable(request *models.Request) error {
+	if !IsActiveStatus(request.Status) {
+		return ErrNotCancelable
+	}
+	if request.ProviderOrderID != "" || request.OrderSentAt != nil {
+		return ErrOrderPlaced
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/repository"
+	"mint-redeem-workflow/worker/workflows"
+
+	"go.uber.org/cadence/.gen/go/shared"
+	"go.uber.org/cadence/client"
+)
+
+// ErrWorkflowStarted is returned by StartRequest for a request whose workflow
+// was already started.
+var ErrWorkflowStarted = errors.New("request workflow already started")
+
+// ProcessRequest saves the request and starts its RequestWorkflow. The
+// request's Type is the registered operation the workflow runs.
+func (s *Service) ProcessRequest(request *models.Request, workflowParam workflows.RequestInput) error {
+	request.Status = "pending"
+	if request.ExecuteAt != nil {
+		request.Status = "scheduled"
+		workflowParam.ExecuteAt = *request.ExecuteAt
+	}
+	if err := s.requests.Create(request); err != nil {
+		return err
+	}
+
+	return s.startRequest(request, workflowParam)
+}
+
+// StartRequest starts the RequestWorkflow of a saved request that never got
+// one, e.g. because ExecuteWorkflow failed after the request was saved. A
+// request that already has a workflow returns ErrWorkflowStarted.
+func (s *Service) StartRequest(request *models.Request, workflowParam workflows.RequestInput) error {
+	if request.RunID != "" || (request.Status != "pending" && request.Status != "scheduled") {
+		return ErrWorkflowStarted
+	}
+	if request.ExecuteAt != nil {
+		workflowParam.ExecuteAt = *request.ExecuteAt
+	}
+
+	err := s.startRequest(request, workflowParam)
+	var alreadyStarted *shared.WorkflowExecutionAlreadyStartedError
+	if errors.As(err, &alreadyStarted) {
+		return ErrWorkflowStarted
+	}
+
+	return err
+}
+
+func (s *Service) startRequest(request *models.Request, workflowParam workflows.RequestInput) error {
+	workflowParam.Type = request.Type
+	workflowParam.RequestID = request.ID.String()
+
+	workflowOptions := client.StartWorkflowOptions{
+		ID:                           request.ID.String(),
+		TaskList:                     s.cfg.CadenceTaskList,
+		ExecutionStartToCloseTimeout: s.executionTimeout(workflowParam.ApprovalTimeout, request.ExecuteAt),
+	}
+
+	workflowRun, err := s.cadenceClient.ExecuteWorkflow(context.Background(), workflowOptions, workflows.RequestWorkflow, workflowParam)
+	if err != nil {
+		return err
+	}
+
+	status := request.Status
+	if request.ExecuteAt == nil {
+		status = "started"
+	}
+	setRunID := func(request *models.Request) {
+		request.RunID = workflowRun.GetRunID()
+	}
+
+	saved, err := s.requests.UpdateStatus(request.ID.String(), repository.StatusUpdate{From: request.Status, To: status, Apply: setRunID})
+	if errors.Is(err, repository.ErrStatusConflict) {
+		// The workflow has already moved the request on, so only the run ID
+		// is left to save.
+		saved, err = s.requests.UpdateStatus(request.ID.String(), repository.StatusUpdate{Apply: setRunID})
+	}
+	if err != nil {
+		return err
+	}
+
+	*request = *saved
+	return nil
+}
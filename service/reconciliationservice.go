@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/worker/workflows"
+	"time"
+
+	"go.uber.org/cadence/client"
+	"gorm.io/gorm"
+)
+
+var ErrReconciliationNotFound = errors.New("reconciliation report not found")
+
+// StartReconciliation saves the report and starts its workflow.
+func (s *Service) StartReconciliation(report *models.ReconciliationReport) error {
+	report.Status = "pending"
+	if err := s.db.Create(report).Error; err != nil {
+		return err
+	}
+
+	workflowOptions := client.StartWorkflowOptions{
+		ID:                           "reconciliation-" + report.ID.String(),
+		TaskList:                     s.cfg.CadenceTaskList,
+		ExecutionStartToCloseTimeout: time.Minute * 30,
+	}
+
+	workflowRun, err := s.cadenceClient.ExecuteWorkflow(context.Background(), workflowOptions, workflows.ReconciliationWorkflow, workflows.ReconciliationInput{
+		ReportID: report.ID.String(),
+		From:     report.From,
+		To:       report.To,
+	})
+	if err != nil {
+		return err
+	}
+
+	report.Status = "started"
+	report.RunID = workflowRun.GetRunID()
+
+	return s.db.Model(report).Updates(map[string]interface{}{"status": report.Status, "run_id": report.RunID}).Error
+}
+
+// GetReconciliation returns the report with its items, problems first.
+func (s *Service) GetReconciliation(reportID string) (*models.ReconciliationReport, error) {
+	var report models.ReconciliationReport
+	if err := s.db.Preload("Items", func(query *gorm.DB) *gorm.DB {
+		return query.Order("CASE classification WHEN 'matched' THEN 1 ELSE 0 END, id")
+	}).First(&report, "id = ?", reportID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReconciliationNotFound
+		}
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// ListReconciliations returns the newest reports without their items.
+func (s *Service) ListReconciliations(limit int) ([]models.ReconciliationReport, error) {
+	if limit <= 0 || limit > defaultListLimit {
+		limit = defaultListLimit
+	}
+
+	var reports []models.ReconciliationReport
+	if err := s.db.Order("created_at desc").Limit(limit).Find(&reports).Error; err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mint-redeem-workflow/events"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/repository"
+	"mint-redeem-workflow/worker/workflows"
+	"time"
+
+	"go.uber.org/cadence/.gen/go/shared"
+)
+
+const defaultListLimit = 100
+
+var (
+	ErrDuplicateClientReference = repository.ErrDuplicateClientReference
+	ErrNotScheduled             = errors.New("request is not waiting for its execute_at time")
+	ErrInvalidExecuteAt         = errors.New("invalid execute_at")
+)
+
+// GetRequest returns the client's request. Another client's request is
+// reported as not found. An empty client, as operators pass, matches any
+// request.
+func (s *Service) GetRequest(client string, requestID string) (*models.Request, error) {
+	request, err := s.requests.Get(requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if client != "" && request.Submitter != client {
+		return nil, ErrRequestNotFound
+	}
+
+	return request, nil
+}
+
+// ListRequests returns the newest requests first.
+func (s *Service) ListRequests(filter repository.RequestFilter) ([]models.Request, error) {
+	return s.requests.List(filter)
+}
+
+// ListStatusTransitions returns the status transitions after filter.AfterID,
+// oldest first, and the ID to read on from.
+func (s *Service) ListStatusTransitions(filter events.Filter) ([]events.Transition, uint, error) {
+	return events.Transitions(s.db, filter)
+}
+
+// FindByClientReference returns the request submitted with the client
+// reference, or ErrRequestNotFound.
+func (s *Service) FindByClientReference(clientReference string) (*models.Request, error) {
+	return s.requests.FindByClientReference(clientReference)
+}
+
+// CancelScheduledRequest cancels the client's request that is still waiting
+// for its execute_at time. The request is marked canceled only while it is
+// still scheduled, and the workflow won't start a canceled request when its
+// timer fires, so a cancel that comes too late returns ErrNotScheduled.
+func (s *Service) CancelScheduledRequest(client string, requestID string) error {
+	request, err := s.findScheduledRequest(client, requestID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.requests.UpdateStatus(requestID, repository.StatusUpdate{
+		From:   "scheduled",
+		To:     "canceled",
+		Reason: workflows.CanceledBeforeExecuteAt,
+		Apply: func(request *models.Request) {
+			request.Reason = workflows.CanceledBeforeExecuteAt
+		},
+	})
+	if err != nil {
+		return scheduleConflict(err)
+	}
+
+	return s.signalScheduledRequest(request, workflows.RescheduleSignal{Cancel: true})
+}
+
+// RescheduleRequest moves the execute_at time of the client's request that
+// hasn't fired yet. The new time must be in the future and within MaxExecuteAhead of when
+// the request was submitted, which the workflow's timeout allows for.
+func (s *Service) RescheduleRequest(client string, requestID string, executeAt time.Time) error {
+	request, err := s.findScheduledRequest(client, requestID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.cfg.ValidateExecuteAt(executeAt, request.CreatedAt, time.Now()); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidExecuteAt, err)
+	}
+
+	// The new time is saved only while the request is still scheduled. A
+	// workflow whose timer fires before the signal arrives finds it there
+	// and waits for it instead of starting the request.
+	_, err = s.requests.UpdateStatus(requestID, repository.StatusUpdate{
+		From: "scheduled",
+		Apply: func(request *models.Request) {
+			request.ExecuteAt = &executeAt
+		},
+	})
+	if err != nil {
+		return scheduleConflict(err)
+	}
+
+	return s.signalScheduledRequest(request, workflows.RescheduleSignal{ExecuteAt: executeAt})
+}
+
+// signalScheduledRequest tells the request's workflow about a change already
+// saved on the request. The saved change stands on its own, so a workflow
+// that is no longer running isn't an error.
+func (s *Service) signalScheduledRequest(request *models.Request, signal workflows.RescheduleSignal) error {
+	err := s.cadenceClient.SignalWorkflow(context.Background(), request.ID.String(), "", workflows.RescheduleSignalName, signal)
+	var notExists *shared.EntityNotExistsError
+	if err != nil && !errors.As(err, &notExists) {
+		return err
+	}
+
+	return nil
+}
+
+// scheduleConflict reports a request that left the scheduled status before a
+// cancel or reschedule could be saved as ErrNotScheduled.
+func scheduleConflict(err error) error {
+	if errors.Is(err, repository.ErrStatusConflict) {
+		return ErrNotScheduled
+	}
+	return err
+}
+
+func (s *Service) findScheduledRequest(client string, requestID string) (*models.Request, error) {
+	request, err := s.GetRequest(client, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Status != "scheduled" {
+		return nil, ErrNotScheduled
+	}
+
+	return request, nil
+}
+
+// executionTimeout is how long a request's workflow may run. Future-dated
+// requests get MaxExecuteAhead on top so that they can be rescheduled up to
+// that limit.
+func (s *Service) executionTimeout(approvalTimeout time.Duration, executeAt *time.Time) time.Duration {
+	timeout := time.Minute*5 + approvalTimeout
+	if executeAt == nil {
+		return timeout
+	}
+
+	return timeout + s.cfg.MaxExecuteAhead
+}
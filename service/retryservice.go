@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/repository"
+	"mint-redeem-workflow/worker/workflows"
+
+	"go.uber.org/cadence/client"
+)
+
+var (
+	ErrNotRetryable      = errors.New("only failed requests that weren't rejected can be retried")
+	ErrTooManyAttempts   = errors.New("request has used up its attempts")
+	ErrErrorCodeRequired = errors.New("error_code is required")
+)
+
+// RetryFilter picks the failed requests RetryFailedRequests retries. ErrorCode
+// is required, and Type narrows it to mints or redeems.
+type RetryFilter struct {
+	ErrorCode string
+	Type      string
+	Limit     int
+}
+
+// RetryResult is the outcome of retrying one request.
+type RetryResult struct {
+	RequestID string
+	Attempt   int
+	Err       error
+}
+
+// RetryRequest starts the next attempt of a failed request as a new run of its
+// workflow. The run keeps the request ID, which is also the Brale idempotency
+// key, so an order that did reach Brale isn't placed twice. The attempt is
+// recorded with the run it replaced and the failure that run ended with.
+// A request that was fully approved isn't approved again, and requests the
+// approvers rejected can't be retried. Only the client that submitted the
+// request, or an operator passing an empty client, can retry it.
+func (s *Service) RetryRequest(client string, requestID string, retriedBy string) (*models.Request, error) {
+	request, err := s.GetRequest(client, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.retry(request, retriedBy); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// RetryFailedRequests retries the oldest failed requests with the error code.
+// A request that can't be retried is reported in its result and doesn't stop
+// the rest.
+func (s *Service) RetryFailedRequests(filter RetryFilter, retriedBy string) ([]RetryResult, error) {
+	if filter.ErrorCode == "" {
+		return nil, ErrErrorCodeRequired
+	}
+
+	failed, err := s.requests.List(repository.RequestFilter{
+		Type:        filter.Type,
+		Status:      "failed",
+		ErrorCode:   filter.ErrorCode,
+		OldestFirst: true,
+		Limit:       filter.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]RetryResult, 0, len(failed))
+	for i := range failed {
+		request := &failed[i]
+		err := s.retry(request, retriedBy)
+		results = append(results, RetryResult{RequestID: request.ID.String(), Attempt: request.Attempt, Err: err})
+	}
+
+	return results, nil
+}
+
+func (s *Service) retry(request *models.Request, retriedBy string) error {
+	if request.Status != "failed" || request.ErrorCode == "rejected" {
+		return ErrNotRetryable
+	}
+	if request.Attempt >= s.cfg.MaxRequestAttempts {
+		return fmt.Errorf("%w: %d of %d", ErrTooManyAttempts, request.Attempt, s.cfg.MaxRequestAttempts)
+	}
+
+	requiredApprovals, err := s.remainingApprovals(request)
+	if err != nil {
+		return err
+	}
+	approvalTimeout := s.cfg.ApprovalTimeout
+	if requiredApprovals == 0 {
+		approvalTimeout = 0
+	}
+
+	previous := *request
+	attempt := models.RequestAttempt{
+		RequestID:           request.ID,
+		Attempt:             request.Attempt + 1,
+		PreviousRunID:       request.RunID,
+		PreviousErrorCode:   request.ErrorCode,
+		PreviousErrorDetail: request.ErrorDetail,
+		RetriedBy:           retriedBy,
+	}
+
+	// The attempt is claimed before the run starts, so a concurrent retry of
+	// the same request fails on the status instead of starting a second run.
+	err = s.requests.Transaction(func(tx repository.RequestRepository) error {
+		if err := tx.SaveAttempt(&attempt); err != nil {
+			return err
+		}
+
+		saved, err := tx.UpdateStatus(request.ID.String(), repository.StatusUpdate{
+			From:   "failed",
+			To:     "started",
+			Reason: fmt.Sprintf("retry attempt %d", attempt.Attempt),
+			Apply: func(request *models.Request) {
+				request.Attempt = attempt.Attempt
+				request.RunID = ""
+				request.ErrorCode = ""
+				request.ErrorDetail = ""
+				request.FailedAt = nil
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		*request = *saved
+		return nil
+	})
+	if errors.Is(err, repository.ErrStatusConflict) {
+		return ErrNotRetryable
+	}
+	if err != nil {
+		return err
+	}
+
+	// Only a failed run can be followed by another run of the same workflow
+	// ID, so a request whose workflow actually completed can't be retried.
+	workflowOptions := client.StartWorkflowOptions{
+		ID:                           request.ID.String(),
+		TaskList:                     s.cfg.CadenceTaskList,
+		ExecutionStartToCloseTimeout: s.executionTimeout(approvalTimeout, nil),
+		WorkflowIDReusePolicy:        client.WorkflowIDReusePolicyAllowDuplicateFailedOnly,
+	}
+
+	workflowRun, err := s.cadenceClient.ExecuteWorkflow(context.Background(), workflowOptions, workflows.RequestWorkflow, workflows.RequestInput{
+		Type:              request.Type,
+		Amount:            request.Amount,
+		Recipient:         request.Recipient,
+		Token:             request.Token,
+		Chain:             request.Chain,
+		RequestID:         request.ID.String(),
+		Submitter:         request.Submitter,
+		RequiredApprovals: requiredApprovals,
+		ApprovalTimeout:   approvalTimeout,
+	})
+	if err != nil {
+		if restoreErr := s.restoreFailedRequest(&previous, &attempt, err); restoreErr != nil {
+			return fmt.Errorf("%v, and restoring the request failed: %w", err, restoreErr)
+		}
+		*request = previous
+		return err
+	}
+
+	attempt.RunID = workflowRun.GetRunID()
+	return s.requests.Transaction(func(tx repository.RequestRepository) error {
+		if err := tx.SaveAttempt(&attempt); err != nil {
+			return err
+		}
+
+		// The new run may already have moved the request on, so only the run
+		// ID is set.
+		saved, err := tx.UpdateStatus(request.ID.String(), repository.StatusUpdate{
+			Apply: func(request *models.Request) {
+				request.RunID = attempt.RunID
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		*request = *saved
+		return nil
+	})
+}
+
+// restoreFailedRequest undoes a claimed attempt whose run couldn't be started:
+// the attempt is removed and the request goes back to failed with the error it
+// had, so it can be retried again.
+func (s *Service) restoreFailedRequest(previous *models.Request, attempt *models.RequestAttempt, startErr error) error {
+	return s.requests.Transaction(func(tx repository.RequestRepository) error {
+		if err := tx.DeleteAttempt(attempt); err != nil {
+			return err
+		}
+
+		saved, err := tx.UpdateStatus(previous.ID.String(), repository.StatusUpdate{
+			From:   "started",
+			To:     "failed",
+			Reason: fmt.Sprintf("retry attempt %d couldn't start: %v", attempt.Attempt, startErr),
+			Apply: func(request *models.Request) {
+				request.Attempt = previous.Attempt
+				request.RunID = previous.RunID
+				request.ErrorCode = previous.ErrorCode
+				request.ErrorDetail = previous.ErrorDetail
+				request.FailedAt = previous.FailedAt
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		*previous = *saved
+		return nil
+	})
+}
+
+// ListRequestAttempts returns the retries of the client's request, oldest
+// first.
+func (s *Service) ListRequestAttempts(client string, requestID string) ([]models.RequestAttempt, error) {
+	if _, err := s.GetRequest(client, requestID); err != nil {
+		return nil, err
+	}
+
+	return s.requests.ListAttempts(requestID)
+}
+
+// remainingApprovals is how many approvals the new run has to collect. A
+// request that already has the required distinct approvals needs none.
+// Otherwise the run asks for all of them again, because the approval gate
+// doesn't count decisions made in an earlier run.
+func (s *Service) remainingApprovals(request *models.Request) (int, error) {
+	required := s.cfg.RequiredApprovals(request.Token, request.Amount)
+	if required == 0 {
+		return 0, nil
+	}
+
+	trail, err := s.requests.ListApprovals(request.ID.String())
+	if err != nil {
+		return 0, err
+	}
+	approvedBy := map[string]bool{}
+	for _, approval := range trail {
+		if approval.Approved {
+			approvedBy[approval.Approver] = true
+		}
+	}
+	if len(approvedBy) >= required {
+		return 0, nil
+	}
+
+	return required, nil
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/worker/workflows"
+
+	"go.uber.org/cadence/.gen/go/shared"
+	"go.uber.org/cadence/client"
+	"gorm.io/gorm"
+)
+
+var ErrScheduleNotFound = errors.New("schedule not found")
+
+// CreateSchedule saves the schedule and starts its cron workflow.
+func (s *Service) CreateSchedule(schedule *models.Schedule, workflowParam workflows.ScheduleInput) error {
+	schedule.Status = "pending"
+	if err := s.db.Create(schedule).Error; err != nil {
+		return err
+	}
+	workflowParam.ScheduleID = schedule.ID.String()
+
+	workflowOptions := client.StartWorkflowOptions{
+		ID:                           "schedule-" + schedule.ID.String(),
+		TaskList:                     s.cfg.CadenceTaskList,
+		ExecutionStartToCloseTimeout: workflows.ScheduleExecutionTimeout(workflowParam.ApprovalTimeout),
+		CronSchedule:                 schedule.CronSchedule,
+	}
+
+	workflowRun, err := s.cadenceClient.ExecuteWorkflow(context.Background(), workflowOptions, workflows.ScheduledRequestWorkflow, workflowParam)
+	if err != nil {
+		return err
+	}
+
+	schedule.Status = "active"
+	schedule.WorkflowID = workflowRun.GetID()
+	schedule.RunID = workflowRun.GetRunID()
+
+	return s.db.Model(schedule).Updates(map[string]interface{}{
+		"status":      schedule.Status,
+		"workflow_id": schedule.WorkflowID,
+		"run_id":      schedule.RunID,
+	}).Error
+}
+
+// ListSchedules returns the client's schedules that haven't been deleted,
+// newest first. An empty client, as operators pass, lists every client's.
+func (s *Service) ListSchedules(client string) ([]models.Schedule, error) {
+	query := s.db.Where("status <> ?", "deleted").Order("created_at desc")
+	if client != "" {
+		query = query.Where("submitter = ?", client)
+	}
+
+	var schedules []models.Schedule
+	if err := query.Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// PauseSchedule stops the schedule creating requests. Its cron workflow keeps
+// firing and skips each occurrence until the schedule is resumed.
+func (s *Service) PauseSchedule(client string, scheduleID string) (*models.Schedule, error) {
+	return s.setScheduleStatus(client, scheduleID, "paused")
+}
+
+func (s *Service) ResumeSchedule(client string, scheduleID string) (*models.Schedule, error) {
+	return s.setScheduleStatus(client, scheduleID, "active")
+}
+
+// DeleteSchedule terminates the schedule's cron workflow, which stops any
+// further firings. The schedule row is kept for the requests linked to it.
+func (s *Service) DeleteSchedule(client string, scheduleID string) error {
+	schedule, err := s.findSchedule(client, scheduleID)
+	if err != nil {
+		return err
+	}
+
+	err = s.cadenceClient.TerminateWorkflow(context.Background(), schedule.WorkflowID, "", "schedule deleted", nil)
+	var notExists *shared.EntityNotExistsError
+	if err != nil && !errors.As(err, &notExists) {
+		return err
+	}
+
+	return s.db.Model(schedule).Update("status", "deleted").Error
+}
+
+func (s *Service) setScheduleStatus(client string, scheduleID string, status string) (*models.Schedule, error) {
+	schedule, err := s.findSchedule(client, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Model(schedule).Update("status", status).Error; err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// findSchedule returns the client's schedule. Another client's schedule is
+// reported as not found, and an empty client matches any schedule.
+func (s *Service) findSchedule(client string, scheduleID string) (*models.Schedule, error) {
+	query := s.db.Where("id = ? AND status <> ?", scheduleID, "deleted")
+	if client != "" {
+		query = query.Where("submitter = ?", client)
+	}
+
+	var schedule models.Schedule
+	if err := query.First(&schedule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrScheduleNotFound
+		}
+		return nil, err
+	}
+
+	return &schedule, nil
+}
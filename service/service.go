@@ -0,0 +1,24 @@
+package service
+
+import (
+	"mint-redeem-workflow/config"
+	"mint-redeem-workflow/infra/cadence"
+	"mint-redeem-workflow/repository"
+
+	"gorm.io/gorm"
+)
+
+// Service is the service layer the API and the admin tools call. It holds the
+// database, request repository, Cadence client and config it works with, so
+// they are built once at startup and handed to it by New. The handlers take
+// its methods as an interface so that tests can hand them a fake.
+type Service struct {
+	db            *gorm.DB
+	requests      repository.RequestRepository
+	cadenceClient cadence.WorkflowClient
+	cfg           *config.ServiceConfig
+}
+
+func New(db *gorm.DB, requests repository.RequestRepository, cadenceClient cadence.WorkflowClient, cfg *config.ServiceConfig) *Service {
+	return &Service{db: db, requests: requests, cadenceClient: cadenceClient, cfg: cfg}
+}
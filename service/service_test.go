@@ -2,16 +2,24 @@ package service
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/base64"
 	"errors"
+	"mint-redeem-workflow/config"
 	"mint-redeem-workflow/db"
+	"mint-redeem-workflow/infra/cadence"
+	"mint-redeem-workflow/ledger"
 	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/repository"
 	"mint-redeem-workflow/worker/workflows"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/cadence/client"
+	"go.uber.org/cadence/encoded"
 )
 
 type MockCadenceClient struct {
@@ -23,6 +31,35 @@ func (m *MockCadenceClient) ExecuteWorkflow(ctx context.Context, options client.
 	return mockArgs.Get(0).(client.WorkflowRun), mockArgs.Error(1)
 }
 
+func (m *MockCadenceClient) SignalWorkflow(ctx context.Context, workflowID string, runID string, signalName string, arg interface{}) error {
+	mockArgs := m.Called(ctx, workflowID, runID, signalName, arg)
+	return mockArgs.Error(0)
+}
+
+func (m *MockCadenceClient) TerminateWorkflow(ctx context.Context, workflowID string, runID string, reason string, details []byte) error {
+	mockArgs := m.Called(ctx, workflowID, runID, reason, details)
+	return mockArgs.Error(0)
+}
+
+func (m *MockCadenceClient) QueryWorkflow(ctx context.Context, workflowID string, runID string, queryType string, args ...interface{}) (encoded.Value, error) {
+	mockArgs := m.Called(ctx, workflowID, runID, queryType)
+	return mockArgs.Get(0).(encoded.Value), mockArgs.Error(1)
+}
+
+// approvalStateValue is the result of an approval_state query.
+type approvalStateValue struct {
+	state workflows.ApprovalState
+}
+
+func (v approvalStateValue) HasValue() bool {
+	return true
+}
+
+func (v approvalStateValue) Get(valuePtr interface{}) error {
+	*valuePtr.(*workflows.ApprovalState) = v.state
+	return nil
+}
+
 type MockWorkflowRun struct {
 	mock.Mock
 }
@@ -40,12 +77,36 @@ func (m *MockWorkflowRun) Get(ctx context.Context, valuePtr interface{}) error {
 }
 
 func InitTestDB() {
-	db.InitDB()
+	db.InitMemoryDB()
 	db.Db.Exec("DELETE FROM requests")
+	db.Db.Exec("DELETE FROM approvals")
+	db.Db.Exec("DELETE FROM approver_keys")
+	db.Db.Exec("DELETE FROM batches")
+	db.Db.Exec("DELETE FROM schedules")
+	db.Db.Exec("DELETE FROM webhooks")
+	db.Db.Exec("DELETE FROM webhook_deliveries")
+	db.Db.Exec("DELETE FROM request_events")
+	db.Db.Exec("DELETE FROM request_attempts")
 }
 
-func TestProcessMint_Success_SavesRequestToDbUpdatesToStarted(t *testing.T) {
-	InitTestDB()
+// newTestService returns a Service on the test database.
+func newTestService(requests repository.RequestRepository, cadenceClient cadence.WorkflowClient) *Service {
+	cfg, _ := config.NewServiceConfig()
+	return New(db.Db, requests, cadenceClient, cfg)
+}
+
+func registerTestKey(approver string, client string) ed25519.PrivateKey {
+	publicKey, privateKey, _ := ed25519.GenerateKey(nil)
+	db.Db.Create(&models.ApproverKey{Approver: approver, Client: client, PublicKey: base64.StdEncoding.EncodeToString(publicKey)})
+	return privateKey
+}
+
+func sign(privateKey ed25519.PrivateKey, payload []byte) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, payload))
+}
+
+func TestProcessRequest_Success_SavesRequestToDbUpdatesToStarted(t *testing.T) {
+	requests := repository.NewMemoryRequestRepository()
 
 	mockCadenceClient := new(MockCadenceClient)
 	mockWorkflowRun := new(MockWorkflowRun)
@@ -56,30 +117,95 @@ func TestProcessMint_Success_SavesRequestToDbUpdatesToStarted(t *testing.T) {
 		Type:      "mint",
 		Amount:    100.50,
 		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
 		Status:    "pending",
 	}
 	mockCadenceClient.On("ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockWorkflowRun, nil)
 
-	workflowInput := workflows.MintInput{
+	workflowInput := workflows.RequestInput{
 		Amount:    100.50,
 		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
 		RequestID: requestID.String(),
 	}
 
-	err := ProcessMint(db.Db, &request, workflowInput, mockCadenceClient)
+	svc := newTestService(requests, mockCadenceClient)
+	err := svc.ProcessRequest(&request, workflowInput)
 	assert.NoError(t, err)
 
-	var dbRequest models.Request
-	err = db.Db.First(&dbRequest, "id = ?", request.ID.String()).Error
+	dbRequest, err := requests.Get(request.ID.String())
 	assert.NoError(t, err)
 	assert.Equal(t, "started", dbRequest.Status)
+	assert.Equal(t, "USDC", dbRequest.Token)
+	assert.Equal(t, "ethereum", dbRequest.Chain)
+
+	executedInput := mockCadenceClient.Calls[0].Arguments.Get(3).([]interface{})[0].(workflows.RequestInput)
+	assert.Equal(t, request.ID.String(), executedInput.RequestID)
+	assert.Equal(t, "USDC", executedInput.Token)
+	assert.Equal(t, "ethereum", executedInput.Chain)
 
 	mockCadenceClient.AssertExpectations(t)
 	mockWorkflowRun.AssertExpectations(t)
 }
 
-func TestProcessMint_WorkflowExecutionError_SavesRequestDoesNotUpdateStatus(t *testing.T) {
-	InitTestDB()
+func TestStartRequest_SavedWithoutWorkflow_StartsIt(t *testing.T) {
+	requests := repository.NewMemoryRequestRepository()
+	mockCadenceClient := new(MockCadenceClient)
+	request := models.Request{Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum", Status: "pending"}
+	assert.NoError(t, requests.Create(&request))
+	mockCadenceClient.On("ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(new(MockWorkflowRun), nil)
+
+	svc := newTestService(requests, mockCadenceClient)
+	err := svc.StartRequest(&request, workflows.RequestInput{Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum"})
+	assert.NoError(t, err)
+
+	dbRequest, err := requests.Get(request.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "started", dbRequest.Status)
+	assert.Equal(t, "mock-run-id", dbRequest.RunID)
+}
+
+func TestStartRequest_WorkflowAlreadyStarted_ReturnsErrWorkflowStarted(t *testing.T) {
+	requests := repository.NewMemoryRequestRepository()
+	mockCadenceClient := new(MockCadenceClient)
+	request := models.Request{Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum", Status: "started", RunID: "run-1"}
+	assert.NoError(t, requests.Create(&request))
+
+	svc := newTestService(requests, mockCadenceClient)
+	err := svc.StartRequest(&request, workflows.RequestInput{})
+
+	assert.ErrorIs(t, err, ErrWorkflowStarted)
+	mockCadenceClient.AssertNotCalled(t, "ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProcessRequest_WorkflowMovedRequestOn_KeepsItsStatus(t *testing.T) {
+	requests := repository.NewMemoryRequestRepository()
+
+	mockCadenceClient := new(MockCadenceClient)
+	mockWorkflowRun := new(MockWorkflowRun)
+	request := models.Request{Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum"}
+	mockCadenceClient.On("ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			_, err := requests.UpdateStatus(request.ID.String(), repository.StatusUpdate{To: "blocked", Reason: "sanctioned"})
+			assert.NoError(t, err)
+		}).
+		Return(mockWorkflowRun, nil)
+
+	svc := newTestService(requests, mockCadenceClient)
+	err := svc.ProcessRequest(&request, workflows.RequestInput{Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum"})
+	assert.NoError(t, err)
+
+	dbRequest, err := requests.Get(request.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "blocked", dbRequest.Status)
+	assert.Equal(t, "mock-run-id", dbRequest.RunID)
+	assert.Len(t, requests.Events(request.ID), 2)
+}
+
+func TestProcessRequest_WorkflowExecutionError_SavesRequestDoesNotUpdateStatus(t *testing.T) {
+	requests := repository.NewMemoryRequestRepository()
 
 	mockCadenceClient := new(MockCadenceClient)
 	mockWorkflowRun := new(MockWorkflowRun)
@@ -90,23 +216,27 @@ func TestProcessMint_WorkflowExecutionError_SavesRequestDoesNotUpdateStatus(t *t
 		Type:      "mint",
 		Amount:    100.50,
 		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
 		Status:    "pending",
 	}
 
-	workflowInput := workflows.MintInput{
+	workflowInput := workflows.RequestInput{
 		Amount:    100.50,
 		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
 		RequestID: requestID.String(),
 	}
 
 	mockCadenceClient.On("ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return(mockWorkflowRun, errors.New("workflow execution error"))
 
-	err := ProcessMint(db.Db, &request, workflowInput, mockCadenceClient)
+	svc := newTestService(requests, mockCadenceClient)
+	err := svc.ProcessRequest(&request, workflowInput)
 	assert.EqualError(t, err, "workflow execution error")
 
-	var dbRequest models.Request
-	err = db.Db.First(&dbRequest, "id = ?", request.ID).Error
+	dbRequest, err := requests.Get(request.ID.String())
 	assert.NoError(t, err)
 	assert.Equal(t, "pending", dbRequest.Status)
 
@@ -114,8 +244,8 @@ func TestProcessMint_WorkflowExecutionError_SavesRequestDoesNotUpdateStatus(t *t
 
 }
 
-func TestProcessRedeem_SavesRequestToDbUpdatesToStarted(t *testing.T) {
-	InitTestDB()
+func TestProcessRequest_Redeem_SavesRequestToDbUpdatesToStarted(t *testing.T) {
+	requests := repository.NewMemoryRequestRepository()
 
 	mockCadenceClient := new(MockCadenceClient)
 	mockWorkflowRun := new(MockWorkflowRun)
@@ -126,32 +256,39 @@ func TestProcessRedeem_SavesRequestToDbUpdatesToStarted(t *testing.T) {
 		Type:      "redeem",
 		Amount:    100.50,
 		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
 		Status:    "pending",
 	}
 
 	mockCadenceClient.On("ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return(mockWorkflowRun, nil)
 
-	workflowInput := workflows.RedeemInput{
+	workflowInput := workflows.RequestInput{
 		Amount:    request.Amount,
 		Recipient: request.Recipient,
+		Token:     request.Token,
+		Chain:     request.Chain,
 		RequestID: request.ID.String(),
 	}
 
-	err := ProcessRedeem(db.Db, &request, workflowInput, mockCadenceClient)
+	svc := newTestService(requests, mockCadenceClient)
+	err := svc.ProcessRequest(&request, workflowInput)
 	assert.NoError(t, err)
 
-	var dbRequest models.Request
-	err = db.Db.First(&dbRequest, "id = ?", request.ID.String()).Error
+	dbRequest, err := requests.Get(request.ID.String())
 	assert.NoError(t, err)
 	assert.Equal(t, "started", dbRequest.Status)
 
+	executedInput := mockCadenceClient.Calls[0].Arguments.Get(3).([]interface{})[0].(workflows.RequestInput)
+	assert.Equal(t, "redeem", executedInput.Type)
+
 	mockCadenceClient.AssertExpectations(t)
 	mockWorkflowRun.AssertExpectations(t)
 }
 
-func TestProcessRedeem_WorkflowExecutionError(t *testing.T) {
-	InitTestDB()
+func TestProcessRequest_Redeem_WorkflowExecutionError(t *testing.T) {
+	requests := repository.NewMemoryRequestRepository()
 
 	mockCadenceClient := new(MockCadenceClient)
 	mockWorkflowRun := new(MockWorkflowRun)
@@ -162,25 +299,802 @@ func TestProcessRedeem_WorkflowExecutionError(t *testing.T) {
 		Type:      "redeem",
 		Amount:    10.50,
 		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
 		Status:    "pending",
 	}
 
-	workflowInput := workflows.RedeemInput{
+	workflowInput := workflows.RequestInput{
 		Amount:    request.Amount,
 		Recipient: request.Recipient,
+		Token:     request.Token,
+		Chain:     request.Chain,
 		RequestID: request.ID.String(),
 	}
 
 	mockCadenceClient.On("ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return(mockWorkflowRun, errors.New("workflow execution error"))
 
-	err := ProcessRedeem(db.Db, &request, workflowInput, mockCadenceClient)
+	svc := newTestService(requests, mockCadenceClient)
+	err := svc.ProcessRequest(&request, workflowInput)
 	assert.EqualError(t, err, "workflow execution error")
 
-	var dbRequest models.Request
-	err = db.Db.First(&dbRequest, "id = ?", request.ID).Error
+	dbRequest, err := requests.Get(request.ID.String())
 	assert.NoError(t, err)
 	assert.Equal(t, "pending", dbRequest.Status)
 
 	mockCadenceClient.AssertExpectations(t)
 }
+
+func TestSignalApproval_AwaitingApproval_SignalsWorkflow(t *testing.T) {
+	InitTestDB()
+
+	mockCadenceClient := new(MockCadenceClient)
+
+	request := models.Request{
+		Type:      "mint",
+		Amount:    50000,
+		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
+		Status:    "awaiting_approval",
+		Submitter: "acme",
+	}
+	db.Db.Create(&request)
+
+	signature := sign(registerTestKey("alice", "globex"), request.ApprovalDecisionPayload("alice", true))
+	sent := workflows.ApprovalSignal{Approved: true, Approver: "alice", Client: "globex", Signature: signature}
+	mockCadenceClient.On("QueryWorkflow", mock.Anything, request.ID.String(), "", workflows.ApprovalStateQuery).Return(approvalStateValue{workflows.ApprovalState{SignsDecision: true}}, nil)
+	mockCadenceClient.On("SignalWorkflow", mock.Anything, request.ID.String(), "", workflows.ApprovalSignalName, sent).Return(nil)
+
+	svc := newTestService(repository.NewGormRequestRepository(db.Db), mockCadenceClient)
+	err := svc.SignalApproval("globex", request.ID.String(), workflows.ApprovalSignal{Approved: true, Signature: signature})
+	assert.NoError(t, err)
+
+	mockCadenceClient.AssertExpectations(t)
+}
+
+func TestSignalApproval_WorkflowFromBeforeSignedDecisions_TakesApprovalPayload(t *testing.T) {
+	InitTestDB()
+
+	mockCadenceClient := new(MockCadenceClient)
+
+	request := models.Request{
+		Type:      "mint",
+		Amount:    50000,
+		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
+		Status:    "awaiting_approval",
+	}
+	db.Db.Create(&request)
+
+	privateKey := registerTestKey("alice", "globex")
+	signature := sign(privateKey, request.ApprovalPayload())
+	mockCadenceClient.On("QueryWorkflow", mock.Anything, request.ID.String(), "", workflows.ApprovalStateQuery).Return(approvalStateValue{workflows.ApprovalState{}}, nil)
+	mockCadenceClient.On("SignalWorkflow", mock.Anything, request.ID.String(), "", workflows.ApprovalSignalName, mock.Anything).Return(nil)
+
+	svc := newTestService(repository.NewGormRequestRepository(db.Db), mockCadenceClient)
+	err := svc.SignalApproval("globex", request.ID.String(), workflows.ApprovalSignal{Approved: true, Signature: sign(privateKey, request.ApprovalDecisionPayload("alice", true))})
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	err = svc.SignalApproval("globex", request.ID.String(), workflows.ApprovalSignal{Approved: true, Signature: signature})
+	assert.NoError(t, err)
+
+	mockCadenceClient.AssertNumberOfCalls(t, "SignalWorkflow", 1)
+}
+
+func TestSignalApproval_NotAwaitingApproval_ReturnsErrorWithoutSignal(t *testing.T) {
+	InitTestDB()
+
+	mockCadenceClient := new(MockCadenceClient)
+
+	request := models.Request{
+		Type:      "mint",
+		Amount:    50000,
+		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
+		Status:    "started",
+	}
+	db.Db.Create(&request)
+
+	svc := newTestService(repository.NewGormRequestRepository(db.Db), mockCadenceClient)
+	err := svc.SignalApproval("globex", request.ID.String(), workflows.ApprovalSignal{Approved: true})
+	assert.ErrorIs(t, err, ErrNotAwaitingApproval)
+
+	err = svc.SignalApproval("globex", uuid.New().String(), workflows.ApprovalSignal{Approved: true})
+	assert.ErrorIs(t, err, ErrRequestNotFound)
+
+	mockCadenceClient.AssertNotCalled(t, "SignalWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSignalApproval_SubmitterOrRepeatApprover_ReturnsErrorWithoutSignal(t *testing.T) {
+	InitTestDB()
+
+	mockCadenceClient := new(MockCadenceClient)
+
+	request := models.Request{
+		Type:      "mint",
+		Amount:    5000000,
+		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
+		Status:    "awaiting_approval",
+		Submitter: "acme",
+	}
+	db.Db.Create(&request)
+	db.Db.Create(&models.Approval{RequestID: request.ID, Approver: "alice", Approved: true})
+	makerKey := registerTestKey("maker", "acme")
+	registerTestKey("alice", "globex")
+
+	// The submitting client can't approve, even with a valid approver key.
+	svc := newTestService(repository.NewGormRequestRepository(db.Db), mockCadenceClient)
+	err := svc.SignalApproval("acme", request.ID.String(), workflows.ApprovalSignal{Approved: true, Signature: sign(makerKey, request.ApprovalDecisionPayload("maker", true))})
+	assert.ErrorIs(t, err, ErrSubmitterCannotApprove)
+
+	err = svc.SignalApproval("globex", request.ID.String(), workflows.ApprovalSignal{Approved: true})
+	assert.ErrorIs(t, err, ErrDuplicateApprover)
+
+	mockCadenceClient.AssertNotCalled(t, "SignalWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSignalApproval_MissingKeyOrBadSignature_ReturnsErrorWithoutSignal(t *testing.T) {
+	InitTestDB()
+
+	mockCadenceClient := new(MockCadenceClient)
+
+	request := models.Request{
+		Type:      "mint",
+		Amount:    50000,
+		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
+		Status:    "awaiting_approval",
+	}
+	db.Db.Create(&request)
+	otherRequest := request
+	otherRequest.Amount = 60000
+
+	svc := newTestService(repository.NewGormRequestRepository(db.Db), mockCadenceClient)
+	err := svc.SignalApproval("globex", request.ID.String(), workflows.ApprovalSignal{Approved: true, Signature: "c2ln"})
+	assert.ErrorIs(t, err, ErrUnknownApprover)
+
+	privateKey := registerTestKey("alice", "globex")
+	mockCadenceClient.On("QueryWorkflow", mock.Anything, request.ID.String(), "", workflows.ApprovalStateQuery).Return(approvalStateValue{workflows.ApprovalState{SignsDecision: true}}, nil)
+	err = svc.SignalApproval("globex", request.ID.String(), workflows.ApprovalSignal{Approved: true, Signature: sign(privateKey, otherRequest.ApprovalDecisionPayload("alice", true))})
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	// A signed rejection can't be sent as an approval, or as another
+	// approver's.
+	err = svc.SignalApproval("globex", request.ID.String(), workflows.ApprovalSignal{Approved: true, Signature: sign(privateKey, request.ApprovalDecisionPayload("alice", false))})
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	err = svc.SignalApproval("globex", request.ID.String(), workflows.ApprovalSignal{Approved: true, Signature: sign(privateKey, request.ApprovalDecisionPayload("bob", true))})
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	mockCadenceClient.AssertNotCalled(t, "SignalWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRegisterApproverKey_ValidatesKeyAndRejectsSecondKey(t *testing.T) {
+	InitTestDB()
+
+	publicKey, _, _ := ed25519.GenerateKey(nil)
+	encoded := base64.StdEncoding.EncodeToString(publicKey)
+
+	svc := newTestService(nil, nil)
+	_, err := svc.RegisterApproverKey("alice", "acme", "bm90IGEga2V5")
+	assert.ErrorIs(t, err, ErrInvalidPublicKey)
+	_, err = svc.RegisterApproverKey("alice", "nobody", encoded)
+	assert.ErrorIs(t, err, ErrUnknownClient)
+
+	key, err := svc.RegisterApproverKey("alice", "acme", encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, encoded, key.PublicKey)
+	assert.Equal(t, "acme", key.Client)
+
+	_, err = svc.RegisterApproverKey("alice", "operator", encoded)
+	assert.ErrorIs(t, err, ErrApproverKeyExists)
+	_, err = svc.RegisterApproverKey("bob", "acme", encoded)
+	assert.ErrorIs(t, err, ErrClientHasApproverKey)
+}
+
+func TestRotateApproverKey_RequiresSignatureOfCurrentKey(t *testing.T) {
+	InitTestDB()
+	oldKey := registerTestKey("alice", "globex")
+	newPublicKey, newPrivateKey, _ := ed25519.GenerateKey(nil)
+	encoded := base64.StdEncoding.EncodeToString(newPublicKey)
+	current := models.ApproverKey{Approver: "alice"}
+	payload := current.RotationPayload(encoded)
+
+	svc := newTestService(nil, nil)
+	_, err := svc.RotateApproverKey("bob", encoded, base64.StdEncoding.EncodeToString(ed25519.Sign(oldKey, payload)))
+	assert.ErrorIs(t, err, ErrUnknownApprover)
+	_, err = svc.RotateApproverKey("alice", encoded, base64.StdEncoding.EncodeToString(ed25519.Sign(newPrivateKey, payload)))
+	assert.ErrorIs(t, err, ErrInvalidRotationSignature)
+
+	key, err := svc.RotateApproverKey("alice", encoded, base64.StdEncoding.EncodeToString(ed25519.Sign(oldKey, payload)))
+	assert.NoError(t, err)
+	assert.Equal(t, encoded, key.PublicKey)
+
+	// The old key can't sign another rotation once it was replaced.
+	otherPublicKey, _, _ := ed25519.GenerateKey(nil)
+	other := base64.StdEncoding.EncodeToString(otherPublicKey)
+	_, err = svc.RotateApproverKey("alice", other, base64.StdEncoding.EncodeToString(ed25519.Sign(oldKey, current.RotationPayload(other))))
+	assert.ErrorIs(t, err, ErrInvalidRotationSignature)
+}
+
+func TestListRequests_FiltersByTypeAndStatus(t *testing.T) {
+	repo := repository.NewMemoryRequestRepository()
+
+	repo.Create(&models.Request{Type: "mint", Amount: 1, Recipient: "0xa", Status: "failed", ErrorCode: "brale_validation_error"})
+	repo.Create(&models.Request{Type: "mint", Amount: 2, Recipient: "0xb", Status: "completed"})
+	repo.Create(&models.Request{Type: "redeem", Amount: 3, Recipient: "0xc", Status: "failed"})
+
+	svc := newTestService(repo, nil)
+	requests, err := svc.ListRequests(repository.RequestFilter{Type: "mint", Status: "failed"})
+	assert.NoError(t, err)
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "brale_validation_error", requests[0].ErrorCode)
+
+	requests, err = svc.ListRequests(repository.RequestFilter{Status: "failed"})
+	assert.NoError(t, err)
+	assert.Len(t, requests, 2)
+}
+
+func TestProcessBatch_SavesBatchAndRequestsAndStartsOneWorkflow(t *testing.T) {
+	InitTestDB()
+
+	mockCadenceClient := new(MockCadenceClient)
+	mockWorkflowRun := new(MockWorkflowRun)
+	mockCadenceClient.On("ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockWorkflowRun, nil)
+
+	batch := models.Batch{Submitter: "alice"}
+	requests := []models.Request{
+		{Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum", Submitter: "alice"},
+		{Type: "redeem", Amount: 20, Recipient: "0xnotdeadbeef", Token: "SBC", Chain: "base", Submitter: "alice"},
+	}
+	workflowInput := workflows.BatchInput{
+		Concurrency: 5,
+		Items: []workflows.BatchItem{
+			{Request: &workflows.RequestInput{Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum"}},
+			{Request: &workflows.RequestInput{Type: "redeem", Amount: 20, Recipient: "0xnotdeadbeef", Token: "SBC", Chain: "base"}},
+		},
+	}
+
+	svc := newTestService(repository.NewGormRequestRepository(db.Db), mockCadenceClient)
+	err := svc.ProcessBatch(&batch, requests, workflowInput)
+	assert.NoError(t, err)
+
+	saved, err := svc.GetBatch(batch.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "started", saved.Status)
+	assert.Equal(t, "mock-run-id", saved.RunID)
+	assert.Len(t, saved.Requests, 2)
+	for _, request := range saved.Requests {
+		assert.Equal(t, "pending", request.Status)
+	}
+
+	mockCadenceClient.AssertNumberOfCalls(t, "ExecuteWorkflow", 1)
+	options := mockCadenceClient.Calls[0].Arguments.Get(1).(client.StartWorkflowOptions)
+	assert.Equal(t, batch.ID.String(), options.ID)
+
+	executedInput := mockCadenceClient.Calls[0].Arguments.Get(3).([]interface{})[0].(workflows.BatchInput)
+	assert.Equal(t, batch.ID.String(), executedInput.BatchID)
+	assert.Equal(t, requests[0].ID.String(), executedInput.Items[0].Request.RequestID)
+	assert.Equal(t, requests[1].ID.String(), executedInput.Items[1].Request.RequestID)
+}
+
+func TestProcessBatch_StartFails_MarksBatchAndRequestsFailed(t *testing.T) {
+	InitTestDB()
+
+	mockCadenceClient := new(MockCadenceClient)
+	mockCadenceClient.On("ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(new(MockWorkflowRun), errors.New("cadence unavailable"))
+
+	batch := models.Batch{Submitter: "alice"}
+	requests := []models.Request{
+		{Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum", Submitter: "alice"},
+		{Type: "redeem", Amount: 20, Recipient: "0xnotdeadbeef", Token: "SBC", Chain: "base", Submitter: "alice"},
+	}
+	workflowInput := workflows.BatchInput{
+		Items: []workflows.BatchItem{
+			{Request: &workflows.RequestInput{Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum"}},
+			{Request: &workflows.RequestInput{Type: "redeem", Amount: 20, Recipient: "0xnotdeadbeef", Token: "SBC", Chain: "base"}},
+		},
+	}
+
+	svc := newTestService(repository.NewGormRequestRepository(db.Db), mockCadenceClient)
+	err := svc.ProcessBatch(&batch, requests, workflowInput)
+	assert.ErrorContains(t, err, "cadence unavailable")
+
+	saved, err := svc.GetBatch(batch.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "failed", saved.Status)
+	assert.Len(t, saved.Requests, 2)
+	for _, request := range saved.Requests {
+		assert.Equal(t, "failed", request.Status)
+		assert.Equal(t, "workflow_start_failed", request.ErrorCode)
+		assert.Equal(t, "cadence unavailable", request.ErrorDetail)
+	}
+	assert.Equal(t, "failed", requests[0].Status)
+}
+
+func TestGetBatch_UnknownIDReturnsNotFound(t *testing.T) {
+	InitTestDB()
+
+	svc := newTestService(nil, nil)
+	_, err := svc.GetBatch(uuid.New().String())
+	assert.ErrorIs(t, err, ErrBatchNotFound)
+}
+
+func TestProcessRequest_DuplicateClientReference_ReturnsErrorWithoutStartingWorkflow(t *testing.T) {
+	requests := repository.NewMemoryRequestRepository()
+
+	mockCadenceClient := new(MockCadenceClient)
+	mockWorkflowRun := new(MockWorkflowRun)
+	mockCadenceClient.On("ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockWorkflowRun, nil)
+
+	clientReference := "ref-1"
+	first := models.Request{Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum", ClientReference: &clientReference}
+	svc := newTestService(requests, mockCadenceClient)
+	err := svc.ProcessRequest(&first, workflows.RequestInput{Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum"})
+	assert.NoError(t, err)
+
+	second := models.Request{Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum", ClientReference: &clientReference}
+	err = svc.ProcessRequest(&second, workflows.RequestInput{Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum"})
+	assert.ErrorIs(t, err, ErrDuplicateClientReference)
+
+	existing, err := requests.Get(first.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "started", existing.Status)
+	mockCadenceClient.AssertNumberOfCalls(t, "ExecuteWorkflow", 1)
+}
+
+func TestCreateSchedule_StartsCronWorkflowAndMarksActive(t *testing.T) {
+	InitTestDB()
+
+	mockCadenceClient := new(MockCadenceClient)
+	mockWorkflowRun := new(MockWorkflowRun)
+	mockCadenceClient.On("ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockWorkflowRun, nil)
+
+	schedule := models.Schedule{Type: "mint", Amount: 25, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", CronSchedule: "0 9 * * 1-5"}
+	svc := newTestService(nil, mockCadenceClient)
+	err := svc.CreateSchedule(&schedule, workflows.ScheduleInput{})
+	assert.NoError(t, err)
+
+	options := mockCadenceClient.Calls[0].Arguments.Get(1).(client.StartWorkflowOptions)
+	assert.Equal(t, "0 9 * * 1-5", options.CronSchedule)
+	assert.Equal(t, "schedule-"+schedule.ID.String(), options.ID)
+	executedInput := mockCadenceClient.Calls[0].Arguments.Get(3).([]interface{})[0].(workflows.ScheduleInput)
+	assert.Equal(t, schedule.ID.String(), executedInput.ScheduleID)
+
+	schedules, err := svc.ListSchedules("")
+	assert.NoError(t, err)
+	assert.Len(t, schedules, 1)
+	assert.Equal(t, "active", schedules[0].Status)
+	assert.Equal(t, "mock-workflow-id", schedules[0].WorkflowID)
+}
+
+func TestPauseAndResumeSchedule_UpdateStatus(t *testing.T) {
+	InitTestDB()
+	schedule := models.Schedule{Type: "mint", Amount: 25, Recipient: "0xnotdeadbeef", CronSchedule: "0 9 * * *", Status: "active", Submitter: "acme"}
+	db.Db.Create(&schedule)
+
+	svc := newTestService(nil, nil)
+	paused, err := svc.PauseSchedule("acme", schedule.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "paused", paused.Status)
+
+	resumed, err := svc.ResumeSchedule("", schedule.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "active", resumed.Status)
+
+	_, err = svc.PauseSchedule("acme", uuid.New().String())
+	assert.ErrorIs(t, err, ErrScheduleNotFound)
+
+	// Another client's schedule is reported as not found.
+	_, err = svc.PauseSchedule("globex", schedule.ID.String())
+	assert.ErrorIs(t, err, ErrScheduleNotFound)
+	schedules, err := svc.ListSchedules("globex")
+	assert.NoError(t, err)
+	assert.Empty(t, schedules)
+	schedules, err = svc.ListSchedules("acme")
+	assert.NoError(t, err)
+	assert.Len(t, schedules, 1)
+}
+
+func TestDeleteSchedule_TerminatesWorkflowAndHidesSchedule(t *testing.T) {
+	InitTestDB()
+	schedule := models.Schedule{Type: "mint", Amount: 25, Recipient: "0xnotdeadbeef", CronSchedule: "0 9 * * *", Status: "active", WorkflowID: "schedule-workflow", Submitter: "acme"}
+	db.Db.Create(&schedule)
+
+	mockCadenceClient := new(MockCadenceClient)
+	mockCadenceClient.On("TerminateWorkflow", mock.Anything, "schedule-workflow", "", "schedule deleted", mock.Anything).Return(nil)
+
+	svc := newTestService(nil, mockCadenceClient)
+	err := svc.DeleteSchedule("globex", schedule.ID.String())
+	assert.ErrorIs(t, err, ErrScheduleNotFound)
+
+	err = svc.DeleteSchedule("acme", schedule.ID.String())
+	assert.NoError(t, err)
+	mockCadenceClient.AssertExpectations(t)
+
+	schedules, err := svc.ListSchedules("")
+	assert.NoError(t, err)
+	assert.Empty(t, schedules)
+
+	err = svc.DeleteSchedule("acme", schedule.ID.String())
+	assert.ErrorIs(t, err, ErrScheduleNotFound)
+}
+
+func TestProcessRequest_ExecuteAt_SavesRequestAsScheduled(t *testing.T) {
+	requests := repository.NewMemoryRequestRepository()
+
+	mockCadenceClient := new(MockCadenceClient)
+	mockWorkflowRun := new(MockWorkflowRun)
+	mockCadenceClient.On("ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockWorkflowRun, nil)
+
+	executeAt := time.Now().Add(time.Hour * 48).UTC().Truncate(time.Second)
+	request := models.Request{Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum", ExecuteAt: &executeAt}
+	svc := newTestService(requests, mockCadenceClient)
+	err := svc.ProcessRequest(&request, workflows.RequestInput{Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum"})
+	assert.NoError(t, err)
+
+	dbRequest, err := requests.Get(request.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "scheduled", dbRequest.Status)
+	assert.Equal(t, "mock-run-id", dbRequest.RunID)
+
+	options := mockCadenceClient.Calls[0].Arguments.Get(1).(client.StartWorkflowOptions)
+	assert.Greater(t, options.ExecutionStartToCloseTimeout, time.Hour*48)
+	executedInput := mockCadenceClient.Calls[0].Arguments.Get(3).([]interface{})[0].(workflows.RequestInput)
+	assert.True(t, executedInput.ExecuteAt.Equal(executeAt))
+}
+
+func TestRescheduleRequest_SignalsOnlyScheduledRequestsWithValidTimes(t *testing.T) {
+	requests := repository.NewMemoryRequestRepository()
+	scheduled := models.Request{Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Status: "scheduled", Submitter: "acme"}
+	requests.Create(&scheduled)
+	started := models.Request{Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Status: "started", Submitter: "acme"}
+	requests.Create(&started)
+
+	mockCadenceClient := new(MockCadenceClient)
+	mockCadenceClient.On("SignalWorkflow", mock.Anything, scheduled.ID.String(), "", workflows.RescheduleSignalName, mock.Anything).Return(nil)
+
+	svc := newTestService(requests, mockCadenceClient)
+	err := svc.RescheduleRequest("acme", scheduled.ID.String(), time.Now().Add(-time.Hour))
+	assert.ErrorIs(t, err, ErrInvalidExecuteAt)
+
+	err = svc.RescheduleRequest("acme", scheduled.ID.String(), time.Now().Add(time.Hour*24*365))
+	assert.ErrorIs(t, err, ErrInvalidExecuteAt)
+
+	err = svc.RescheduleRequest("acme", started.ID.String(), time.Now().Add(time.Hour))
+	assert.ErrorIs(t, err, ErrNotScheduled)
+
+	err = svc.CancelScheduledRequest("acme", started.ID.String())
+	assert.ErrorIs(t, err, ErrNotScheduled)
+
+	// Another client's request is reported as not found.
+	err = svc.RescheduleRequest("globex", scheduled.ID.String(), time.Now().Add(time.Hour))
+	assert.ErrorIs(t, err, ErrRequestNotFound)
+	err = svc.CancelScheduledRequest("globex", scheduled.ID.String())
+	assert.ErrorIs(t, err, ErrRequestNotFound)
+
+	executeAt := time.Now().Add(time.Hour)
+	err = svc.RescheduleRequest("acme", scheduled.ID.String(), executeAt)
+	assert.NoError(t, err)
+	mockCadenceClient.AssertNumberOfCalls(t, "SignalWorkflow", 1)
+	saved, _ := requests.Get(scheduled.ID.String())
+	assert.True(t, saved.ExecuteAt.Equal(executeAt))
+
+	// The cancel is saved before the workflow is signaled, so a second one
+	// finds the request has already left the scheduled status.
+	err = svc.CancelScheduledRequest("acme", scheduled.ID.String())
+	assert.NoError(t, err)
+	saved, _ = requests.Get(scheduled.ID.String())
+	assert.Equal(t, "canceled", saved.Status)
+	err = svc.CancelScheduledRequest("acme", scheduled.ID.String())
+	assert.ErrorIs(t, err, ErrNotScheduled)
+	mockCadenceClient.AssertNumberOfCalls(t, "SignalWorkflow", 2)
+}
+
+func TestRefundRequest_ReversesPostingAndMarksRefunded(t *testing.T) {
+	InitTestDB()
+	request := models.Request{Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", Status: "completed", Submitter: "acme"}
+	db.Db.Create(&request)
+	pending := models.Request{Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", Status: "started"}
+	db.Db.Create(&pending)
+
+	svc := newTestService(repository.NewGormRequestRepository(db.Db), nil)
+	reversal, err := svc.RefundRequest(request.ID.String(), "paid back by wire")
+	assert.NoError(t, err)
+	assert.Equal(t, "reversal", reversal.Kind)
+
+	var dbRequest models.Request
+	db.Db.First(&dbRequest, "id = ?", request.ID)
+	assert.Equal(t, "refunded", dbRequest.Status)
+	assert.Equal(t, "paid back by wire", dbRequest.Reason)
+
+	entries, err := svc.ListJournalEntries(ledger.EntryFilter{RequestID: request.ID.String()})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	_, err = svc.RefundRequest(request.ID.String(), "again")
+	assert.ErrorIs(t, err, ErrNotRefundable)
+	_, err = svc.RefundRequest(pending.ID.String(), "too early")
+	assert.ErrorIs(t, err, ErrNotRefundable)
+}
+
+func TestStartReconciliation_StartsWorkflowForRange(t *testing.T) {
+	InitTestDB()
+
+	mockCadenceClient := new(MockCadenceClient)
+	mockWorkflowRun := new(MockWorkflowRun)
+	mockCadenceClient.On("ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockWorkflowRun, nil)
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	report := models.ReconciliationReport{From: from, To: from.Add(time.Hour * 24)}
+	svc := newTestService(nil, mockCadenceClient)
+	err := svc.StartReconciliation(&report)
+	assert.NoError(t, err)
+
+	executedInput := mockCadenceClient.Calls[0].Arguments.Get(3).([]interface{})[0].(workflows.ReconciliationInput)
+	assert.Equal(t, report.ID.String(), executedInput.ReportID)
+	assert.True(t, executedInput.From.Equal(from))
+
+	saved, err := svc.GetReconciliation(report.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "started", saved.Status)
+
+	_, err = svc.GetReconciliation(uuid.New().String())
+	assert.ErrorIs(t, err, ErrReconciliationNotFound)
+}
+
+func TestRegisterWebhook_ValidatesURLAndEventTypes(t *testing.T) {
+	InitTestDB()
+
+	svc := newTestService(nil, nil)
+	_, err := svc.RegisterWebhook("acme", "ftp://example.com/hook", []string{"request.completed"})
+	assert.ErrorIs(t, err, ErrInvalidWebhookURL)
+	_, err = svc.RegisterWebhook("acme", "https://169.254.169.254/latest/meta-data", []string{"request.completed"})
+	assert.ErrorIs(t, err, ErrInvalidWebhookURL)
+	_, err = svc.RegisterWebhook("acme", "https://example.com/hook", []string{"request.done"})
+	assert.ErrorIs(t, err, ErrInvalidEventType)
+
+	webhook, err := svc.RegisterWebhook("acme", "https://example.com/hook", []string{"request.completed", "request.failed"})
+	assert.NoError(t, err)
+	assert.Equal(t, "request.completed,request.failed", webhook.EventTypes)
+	assert.Len(t, webhook.Secret, 64)
+
+	webhooks, err := svc.ListWebhooks("acme")
+	assert.NoError(t, err)
+	assert.Len(t, webhooks, 1)
+}
+
+func TestRedeliverWebhook_FailedDeliveryStartsNewWorkflow(t *testing.T) {
+	InitTestDB()
+	webhook := models.Webhook{Client: "acme", URL: "https://example.com/hook", EventTypes: "*", Secret: "secret"}
+	db.Db.Create(&webhook)
+	failed := models.WebhookDelivery{WebhookID: webhook.ID, RequestID: uuid.New(), EventType: "request.completed", Payload: "{}", Status: "failed", Attempts: 20}
+	db.Db.Create(&failed)
+	pending := models.WebhookDelivery{WebhookID: webhook.ID, RequestID: uuid.New(), EventType: "request.completed", Payload: "{}", Status: "pending"}
+	db.Db.Create(&pending)
+
+	mockCadenceClient := new(MockCadenceClient)
+	mockCadenceClient.On("ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(new(MockWorkflowRun), nil)
+
+	svc := newTestService(nil, mockCadenceClient)
+	_, err := svc.RedeliverWebhook("acme", webhook.ID.String(), pending.ID.String())
+	assert.ErrorIs(t, err, ErrWebhookDeliveryInProcess)
+	_, err = svc.RedeliverWebhook("acme", uuid.NewString(), failed.ID.String())
+	assert.ErrorIs(t, err, ErrWebhookDeliveryNotFound)
+	_, err = svc.RedeliverWebhook("globex", webhook.ID.String(), failed.ID.String())
+	assert.ErrorIs(t, err, ErrWebhookDeliveryNotFound)
+
+	delivery, err := svc.RedeliverWebhook("acme", webhook.ID.String(), failed.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "pending", delivery.Status)
+	mockCadenceClient.AssertNumberOfCalls(t, "ExecuteWorkflow", 1)
+	options := mockCadenceClient.Calls[0].Arguments.Get(1).(client.StartWorkflowOptions)
+	assert.Equal(t, "webhook-delivery-"+failed.ID.String()+"-20", options.ID)
+
+	deliveries, err := svc.ListWebhookDeliveries("", webhook.ID.String(), "pending", 0)
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 2)
+	_, err = svc.ListWebhookDeliveries("globex", webhook.ID.String(), "pending", 0)
+	assert.ErrorIs(t, err, ErrWebhookNotFound)
+}
+
+func TestCancelRequest_TerminatesWorkflowAndRecordsTransition(t *testing.T) {
+	requests := repository.NewMemoryRequestRepository()
+	request := models.Request{Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", Status: "awaiting_approval"}
+	requests.Create(&request)
+	completed := models.Request{Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", Status: "completed"}
+	requests.Create(&completed)
+	ordered := models.Request{Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", Status: "started", ProviderOrderID: "order-1"}
+	requests.Create(&ordered)
+	sentAt := time.Now()
+	sent := models.Request{Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", Status: "started", OrderSentAt: &sentAt}
+	requests.Create(&sent)
+
+	mockCadenceClient := new(MockCadenceClient)
+	mockCadenceClient.On("TerminateWorkflow", mock.Anything, request.ID.String(), "", "wrong recipient", mock.Anything).Return(nil)
+
+	svc := newTestService(requests, mockCadenceClient)
+	_, err := svc.CancelRequest(completed.ID.String(), "wrong recipient")
+	assert.ErrorIs(t, err, ErrNotCancelable)
+	_, err = svc.CancelRequest(ordered.ID.String(), "wrong recipient")
+	assert.ErrorIs(t, err, ErrOrderPlaced)
+	_, err = svc.CancelRequest(sent.ID.String(), "wrong recipient")
+	assert.ErrorIs(t, err, ErrOrderPlaced)
+	saved, _ := requests.Get(sent.ID.String())
+	assert.Equal(t, "started", saved.Status)
+
+	canceled, err := svc.CancelRequest(request.ID.String(), "wrong recipient")
+	assert.NoError(t, err)
+	assert.Equal(t, "canceled", canceled.Status)
+	mockCadenceClient.AssertExpectations(t)
+
+	saved, _ = requests.Get(request.ID.String())
+	assert.Equal(t, "canceled", saved.Status)
+	assert.Equal(t, "wrong recipient", saved.Reason)
+
+	history, err := svc.ListRequestEvents("", request.ID.String(), 0)
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.JSONEq(t, `{"from":"awaiting_approval","to":"canceled","reason":"wrong recipient"}`, history[1].Data)
+}
+
+func TestRetryRequest_FailedRequestStartsNewRunWithSameID(t *testing.T) {
+	InitTestDB()
+	now := time.Now()
+	request := models.Request{Type: "redeem", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", Status: "failed", ErrorCode: "server_error", FailedAt: &now, Submitter: "acme"}
+	db.Db.Create(&request)
+	rejected := models.Request{Type: "mint", Amount: 20000, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", Status: "failed", ErrorCode: "rejected"}
+	db.Db.Create(&rejected)
+
+	mockCadenceClient := new(MockCadenceClient)
+	mockCadenceClient.On("ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(new(MockWorkflowRun), nil)
+
+	svc := newTestService(repository.NewGormRequestRepository(db.Db), mockCadenceClient)
+	_, err := svc.RetryRequest("", rejected.ID.String(), "alice")
+	assert.ErrorIs(t, err, ErrNotRetryable)
+
+	_, err = svc.RetryRequest("globex", request.ID.String(), "globex")
+	assert.ErrorIs(t, err, ErrRequestNotFound)
+
+	retried, err := svc.RetryRequest("acme", request.ID.String(), "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "started", retried.Status)
+	assert.Equal(t, "mock-run-id", retried.RunID)
+	assert.Equal(t, 2, retried.Attempt)
+
+	mockCadenceClient.AssertNumberOfCalls(t, "ExecuteWorkflow", 1)
+	options := mockCadenceClient.Calls[0].Arguments.Get(1).(client.StartWorkflowOptions)
+	assert.Equal(t, request.ID.String(), options.ID)
+	assert.Equal(t, client.WorkflowIDReusePolicyAllowDuplicateFailedOnly, options.WorkflowIDReusePolicy)
+	input := mockCadenceClient.Calls[0].Arguments.Get(3).([]interface{})[0].(workflows.RequestInput)
+	assert.Equal(t, request.ID.String(), input.RequestID)
+
+	var dbRequest models.Request
+	db.Db.First(&dbRequest, "id = ?", request.ID)
+	assert.Equal(t, "started", dbRequest.Status)
+	assert.Equal(t, 2, dbRequest.Attempt)
+	assert.Empty(t, dbRequest.ErrorCode)
+	assert.Nil(t, dbRequest.FailedAt)
+
+	attempts, err := svc.ListRequestAttempts("", request.ID.String())
+	assert.NoError(t, err)
+	assert.Len(t, attempts, 1)
+	assert.Equal(t, 2, attempts[0].Attempt)
+	assert.Equal(t, "mock-run-id", attempts[0].RunID)
+	assert.Equal(t, "server_error", attempts[0].PreviousErrorCode)
+	assert.Equal(t, "alice", attempts[0].RetriedBy)
+}
+
+func TestRetryRequest_NewRunMovedRequestOn_KeepsItsStatus(t *testing.T) {
+	InitTestDB()
+	request := models.Request{Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", Status: "failed", ErrorCode: "server_error"}
+	db.Db.Create(&request)
+
+	mockCadenceClient := new(MockCadenceClient)
+	mockCadenceClient.On("ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			_, err := repository.NewGormRequestRepository(db.Db).UpdateStatus(request.ID.String(), repository.StatusUpdate{To: "awaiting_approval"})
+			assert.NoError(t, err)
+		}).
+		Return(new(MockWorkflowRun), nil)
+
+	svc := newTestService(repository.NewGormRequestRepository(db.Db), mockCadenceClient)
+	retried, err := svc.RetryRequest("", request.ID.String(), "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "awaiting_approval", retried.Status)
+	assert.Equal(t, 2, retried.Attempt)
+	assert.Equal(t, "mock-run-id", retried.RunID)
+
+	var dbRequest models.Request
+	db.Db.First(&dbRequest, "id = ?", request.ID)
+	assert.Equal(t, "awaiting_approval", dbRequest.Status)
+	assert.Equal(t, 2, dbRequest.Attempt)
+	assert.Equal(t, 4, dbRequest.Version)
+}
+
+func TestRetryRequest_RunThatFailsToStart_RestoresFailedRequest(t *testing.T) {
+	InitTestDB()
+	now := time.Now()
+	request := models.Request{Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", Status: "failed", ErrorCode: "server_error", RunID: "first-run-id", FailedAt: &now}
+	db.Db.Create(&request)
+
+	mockCadenceClient := new(MockCadenceClient)
+	mockCadenceClient.On("ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			// The attempt is recorded before the run is started.
+			var claimed models.Request
+			db.Db.First(&claimed, "id = ?", request.ID)
+			assert.Equal(t, "started", claimed.Status)
+			assert.Equal(t, 2, claimed.Attempt)
+		}).
+		Return(new(MockWorkflowRun), errors.New("cadence unavailable"))
+
+	svc := newTestService(repository.NewGormRequestRepository(db.Db), mockCadenceClient)
+	_, err := svc.RetryRequest("", request.ID.String(), "alice")
+	assert.ErrorContains(t, err, "cadence unavailable")
+
+	var dbRequest models.Request
+	db.Db.First(&dbRequest, "id = ?", request.ID)
+	assert.Equal(t, "failed", dbRequest.Status)
+	assert.Equal(t, 1, dbRequest.Attempt)
+	assert.Equal(t, "first-run-id", dbRequest.RunID)
+	assert.Equal(t, "server_error", dbRequest.ErrorCode)
+	assert.NotNil(t, dbRequest.FailedAt)
+
+	attempts, err := svc.ListRequestAttempts("", request.ID.String())
+	assert.NoError(t, err)
+	assert.Empty(t, attempts)
+}
+
+func TestRetryRequest_StopsAtMaxAttempts(t *testing.T) {
+	InitTestDB()
+	request := models.Request{Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", Status: "failed", ErrorCode: "server_error", Attempt: 5}
+	db.Db.Create(&request)
+
+	mockCadenceClient := new(MockCadenceClient)
+
+	svc := newTestService(repository.NewGormRequestRepository(db.Db), mockCadenceClient)
+	_, err := svc.RetryRequest("", request.ID.String(), "alice")
+	assert.ErrorIs(t, err, ErrTooManyAttempts)
+	mockCadenceClient.AssertNotCalled(t, "ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRetryFailedRequests_RetriesMatchingErrorCode(t *testing.T) {
+	InitTestDB()
+	transient := models.Request{Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", Status: "failed", ErrorCode: "server_error"}
+	db.Db.Create(&transient)
+	exhausted := models.Request{Type: "redeem", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", Status: "failed", ErrorCode: "server_error", Attempt: 5}
+	db.Db.Create(&exhausted)
+	invalid := models.Request{Type: "mint", Amount: 10, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", Status: "failed", ErrorCode: "brale_validation_error"}
+	db.Db.Create(&invalid)
+
+	mockCadenceClient := new(MockCadenceClient)
+	mockCadenceClient.On("ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(new(MockWorkflowRun), nil)
+
+	svc := newTestService(repository.NewGormRequestRepository(db.Db), mockCadenceClient)
+	_, err := svc.RetryFailedRequests(RetryFilter{}, "alice")
+	assert.ErrorIs(t, err, ErrErrorCodeRequired)
+
+	results, err := svc.RetryFailedRequests(RetryFilter{ErrorCode: "server_error"}, "alice")
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	for _, result := range results {
+		if result.RequestID == transient.ID.String() {
+			assert.NoError(t, result.Err)
+			assert.Equal(t, 2, result.Attempt)
+		} else {
+			assert.ErrorIs(t, result.Err, ErrTooManyAttempts)
+		}
+	}
+	mockCadenceClient.AssertNumberOfCalls(t, "ExecuteWorkflow", 1)
+
+	var dbRequest models.Request
+	db.Db.First(&dbRequest, "id = ?", invalid.ID)
+	assert.Equal(t, "failed", dbRequest.Status)
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/webhooks"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWebhookNotFound          = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound  = errors.New("webhook delivery not found")
+	ErrInvalidWebhookURL        = webhooks.ErrInvalidURL
+	ErrInvalidEventType         = errors.New("unknown webhook event type")
+	ErrWebhookDeliveryInProcess = errors.New("webhook delivery is still being attempted")
+)
+
+// RegisterWebhook validates the webhook's URL and event types and saves it
+// for the client with a new signing secret.
+func (s *Service) RegisterWebhook(client string, webhookURL string, eventTypes []string) (*models.Webhook, error) {
+	if err := webhooks.ValidateURL(webhookURL); err != nil {
+		return nil, err
+	}
+
+	for _, eventType := range eventTypes {
+		if eventType != "*" && !webhooks.IsEventType(eventType) {
+			return nil, fmt.Errorf("%w %q", ErrInvalidEventType, eventType)
+		}
+	}
+
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := models.Webhook{
+		Client:     client,
+		URL:        webhookURL,
+		EventTypes: strings.Join(eventTypes, ","),
+		Secret:     secret,
+	}
+	if err := s.db.Create(&webhook).Error; err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// ListWebhooks returns the client's webhooks, or every webhook when client is
+// empty.
+func (s *Service) ListWebhooks(client string) ([]models.Webhook, error) {
+	query := s.db.Order("created_at desc")
+	if client != "" {
+		query = query.Where("client = ?", client)
+	}
+
+	var hooks []models.Webhook
+	if err := query.Find(&hooks).Error; err != nil {
+		return nil, err
+	}
+
+	return hooks, nil
+}
+
+// DeleteWebhook removes the client's webhook together with its delivery
+// history. Deliveries that are still being attempted fail on their next
+// attempt. An empty client, as operators pass, matches any webhook.
+func (s *Service) DeleteWebhook(client string, webhookID string) error {
+	webhook, err := s.findWebhook(client, webhookID)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", webhook.ID).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(webhook).Error
+	})
+}
+
+// ListWebhookDeliveries returns the newest deliveries of the client's webhook
+// first.
+func (s *Service) ListWebhookDeliveries(client string, webhookID string, status string, limit int) ([]models.WebhookDelivery, error) {
+	webhook, err := s.findWebhook(client, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.db.Where("webhook_id = ?", webhook.ID).Order("created_at desc")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if limit <= 0 || limit > defaultListLimit {
+		limit = defaultListLimit
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := query.Limit(limit).Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// RedeliverWebhook sends a delivered or failed delivery again with its
+// original payload. The attempt count carries on from the earlier run.
+func (s *Service) RedeliverWebhook(client string, webhookID string, deliveryID string) (*models.WebhookDelivery, error) {
+	webhook, err := s.findWebhook(client, webhookID)
+	if err != nil {
+		if errors.Is(err, ErrWebhookNotFound) {
+			return nil, ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+
+	var delivery models.WebhookDelivery
+	if err := s.db.First(&delivery, "id = ? AND webhook_id = ?", deliveryID, webhook.ID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+
+	if delivery.Status == "pending" {
+		return nil, ErrWebhookDeliveryInProcess
+	}
+
+	previousStatus := delivery.Status
+	if err := s.db.Model(&delivery).Update("status", "pending").Error; err != nil {
+		return nil, err
+	}
+	delivery.Status = "pending"
+
+	workflowID := "webhook-delivery-" + delivery.ID.String() + "-" + strconv.Itoa(delivery.Attempts)
+	if err := webhooks.StartDelivery(s.cadenceClient, s.cfg.CadenceTaskList, delivery, workflowID); err != nil {
+		s.db.Model(&delivery).Update("status", previousStatus)
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// findWebhook returns the client's webhook. Another client's webhook is
+// reported as not found.
+func (s *Service) findWebhook(client string, webhookID string) (*models.Webhook, error) {
+	query := s.db.Where("id = ?", webhookID)
+	if client != "" {
+		query = query.Where("client = ?", client)
+	}
+
+	var webhook models.Webhook
+	if err := query.First(&webhook).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	return &webhook, nil
+}
@@ -0,0 +1,163 @@
+package webhooks
+
+import (
+	"errors"
+	"fmt"
+	"mint-redeem-workflow/events"
+	"mint-redeem-workflow/infra/cadence"
+	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/repository"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// dispatchCursor is the name of the event cursor Dispatch reads on from.
+const dispatchCursor = "webhooks"
+
+// dispatchBatchSize is how many status changes one Dispatch call reads.
+const dispatchBatchSize = 100
+
+// maxDispatchAttempts is how many calls in a row Dispatch tries to queue and
+// start the deliveries of a status change before it gives up on it.
+const maxDispatchAttempts = 10
+
+// Dispatch queues and starts on the task list the deliveries of the status changes saved to the
+// request event history since the last call, and returns how many changes it
+// read.
+//
+// The event history is the outbox of status writes: a status change commits
+// its event in the same transaction as the status, so every saved change is
+// delivered, even when whatever saved it fails right after. A change whose
+// deliveries can't all be queued or started ends the call, and the next call
+// picks it up again. After maxDispatchAttempts calls in a row have failed on
+// it, the deliveries that couldn't be started are marked failed, where they
+// can be redelivered from, and the calls move on past it.
+func Dispatch(db *gorm.DB, requests repository.RequestRepository, cadenceClient cadence.WorkflowClient, taskList string) (int, error) {
+	cursor, err := loadCursor(db)
+	if err != nil {
+		return 0, err
+	}
+
+	changes, lastID, err := events.Transitions(db, events.Filter{AfterID: cursor.EventID, Limit: dispatchBatchSize})
+	if err != nil {
+		return 0, err
+	}
+
+	for i, change := range changes {
+		unstarted, err := dispatch(db, requests, cadenceClient, taskList, change)
+		if err == nil {
+			continue
+		}
+
+		failures := 1
+		previousID := cursor.EventID
+		if i > 0 {
+			previousID = changes[i-1].EventID
+		} else {
+			failures = cursor.Failures + 1
+		}
+		if failures < maxDispatchAttempts {
+			saveCursor(db, previousID, failures)
+			return i, err
+		}
+
+		if failErr := failDeliveries(db, unstarted, err); failErr != nil {
+			saveCursor(db, previousID, failures)
+			return i, failErr
+		}
+	}
+
+	return len(changes), saveCursor(db, lastID, 0)
+}
+
+// StartDispatch calls Dispatch straight away and then every interval until
+// stop is closed. A call that reads a full batch is followed by another
+// straight away.
+func StartDispatch(db *gorm.DB, requests repository.RequestRepository, cadenceClient cadence.WorkflowClient, taskList string, interval time.Duration, logger *zap.Logger, stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			for {
+				read, err := Dispatch(db, requests, cadenceClient, taskList)
+				if err != nil {
+					logger.Error("Failed to dispatch webhook deliveries.", zap.Error(err))
+				}
+				if err != nil || read < dispatchBatchSize {
+					break
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// dispatch queues and starts the deliveries of the change. It returns the
+// deliveries that were queued but couldn't be started.
+func dispatch(db *gorm.DB, requests repository.RequestRepository, cadenceClient cadence.WorkflowClient, taskList string, change events.Transition) ([]models.WebhookDelivery, error) {
+	if change.Submitter == "" {
+		return nil, nil
+	}
+
+	request, err := requests.Get(change.RequestID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := Enqueue(db, change, *request)
+	if err != nil || len(deliveries) == 0 {
+		return nil, err
+	}
+
+	return StartDeliveries(cadenceClient, taskList, deliveries)
+}
+
+// failDeliveries marks deliveries that were never started as failed with the
+// error that kept them from starting.
+func failDeliveries(db *gorm.DB, deliveries []models.WebhookDelivery, err error) error {
+	for _, delivery := range deliveries {
+		if updateErr := db.Model(&delivery).Where("status = ?", "pending").Updates(map[string]interface{}{
+			"status":     "failed",
+			"last_error": fmt.Sprintf("delivery could not be started: %v", err),
+		}).Error; updateErr != nil {
+			return updateErr
+		}
+	}
+
+	return nil
+}
+
+// loadCursor returns the dispatch cursor. The first call starts it at the
+// latest event, because status changes saved before there was a dispatcher
+// were delivered as they were saved.
+func loadCursor(db *gorm.DB) (models.EventCursor, error) {
+	var cursor models.EventCursor
+	err := db.First(&cursor, "name = ?", dispatchCursor).Error
+	if err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+		return cursor, err
+	}
+
+	cursor.Name = dispatchCursor
+	if err := db.Model(&models.RequestEvent{}).Select("COALESCE(MAX(id), 0)").Scan(&cursor.EventID).Error; err != nil {
+		return cursor, err
+	}
+
+	return cursor, db.FirstOrCreate(&cursor, models.EventCursor{Name: dispatchCursor}).Error
+}
+
+// saveCursor moves the cursor to the event with the failures on the events
+// after it. It never moves the cursor back.
+func saveCursor(db *gorm.DB, eventID uint, failures int) error {
+	return db.Model(&models.EventCursor{}).Where("name = ? AND event_id <= ?", dispatchCursor, eventID).Updates(map[string]interface{}{
+		"event_id": eventID,
+		"failures": failures,
+	}).Error
+}
@@ -0,0 +1,228 @@
+// Package webhooks posts signed request events to the URLs API clients
+// register.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mint-redeem-workflow/events"
+	"mint-redeem-workflow/infra/cadence"
+	"mint-redeem-workflow/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/cadence/.gen/go/shared"
+	"go.uber.org/cadence/client"
+	"gorm.io/gorm"
+)
+
+// DeliveryWorkflowName is the name the delivery workflow is registered under.
+// It is started by name because this package sits below the workflows.
+const DeliveryWorkflowName = "WebhookDeliveryWorkflow"
+
+// Event is the JSON body posted to webhooks.
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      EventData `json:"data"`
+}
+
+type EventData struct {
+	RequestID       string  `json:"request_id"`
+	Type            string  `json:"type"`
+	Status          string  `json:"status"`
+	Reason          string  `json:"reason,omitempty"`
+	Amount          float64 `json:"amount"`
+	Recipient       string  `json:"recipient"`
+	Token           string  `json:"token"`
+	Chain           string  `json:"chain"`
+	ProviderOrderID string  `json:"provider_order_id,omitempty"`
+	TxHash          string  `json:"tx_hash,omitempty"`
+	ErrorCode       string  `json:"error_code,omitempty"`
+}
+
+// EventTypes are the events a webhook can subscribe to, one for each status a
+// request moves to after it is submitted.
+var EventTypes = []string{
+	EventType("started"),
+	EventType("awaiting_approval"),
+	EventType("blocked"),
+	EventType("canceled"),
+	EventType("completed"),
+	EventType("failed"),
+	EventType("refunded"),
+}
+
+// EventType is the event sent when a request moves to the status.
+func EventType(status string) string {
+	return "request." + status
+}
+
+// IsEventType reports whether webhooks can subscribe to the event type.
+func IsEventType(eventType string) bool {
+	for _, known := range EventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Enqueue creates a pending delivery of the status change for every webhook
+// of the request's submitter that subscribes to it. Changes to a status that
+// isn't one of the EventTypes aren't delivered. A webhook gets each status
+// change once, so calling Enqueue again for the same change returns the
+// deliveries that haven't been sent yet.
+func Enqueue(db *gorm.DB, change events.Transition, request models.Request) ([]models.WebhookDelivery, error) {
+	eventType := EventType(change.To)
+	if request.Submitter == "" || !IsEventType(eventType) {
+		return nil, nil
+	}
+
+	var hooks []models.Webhook
+	if err := db.Where("client = ?", request.Submitter).Find(&hooks).Error; err != nil {
+		return nil, err
+	}
+
+	var deliveries []models.WebhookDelivery
+	for _, hook := range hooks {
+		if !hook.Subscribes(eventType) {
+			continue
+		}
+
+		delivery := models.WebhookDelivery{}
+		err := db.Where(models.WebhookDelivery{WebhookID: hook.ID, EventID: change.EventID}).First(&delivery).Error
+		if err == nil {
+			if delivery.Status == "pending" {
+				deliveries = append(deliveries, delivery)
+			}
+			continue
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		delivery, err = newDelivery(hook, change, request)
+		if err != nil {
+			return nil, err
+		}
+		if err := db.Create(&delivery).Error; err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+// newDelivery builds the delivery of the status change. The status and reason
+// are the ones of the change, since the request may have moved on since.
+func newDelivery(hook models.Webhook, change events.Transition, request models.Request) (models.WebhookDelivery, error) {
+	eventType := EventType(change.To)
+	delivery := models.WebhookDelivery{
+		ID:        uuid.New(),
+		WebhookID: hook.ID,
+		RequestID: request.ID,
+		EventType: eventType,
+		EventID:   change.EventID,
+		Status:    "pending",
+	}
+
+	payload, err := json.Marshal(Event{
+		ID:        delivery.ID.String(),
+		Type:      eventType,
+		CreatedAt: change.CreatedAt.UTC(),
+		Data: EventData{
+			RequestID:       request.ID.String(),
+			Type:            request.Type,
+			Status:          change.To,
+			Reason:          change.Reason,
+			Amount:          request.Amount,
+			Recipient:       request.Recipient,
+			Token:           request.Token,
+			Chain:           request.Chain,
+			ProviderOrderID: request.ProviderOrderID,
+			TxHash:          request.TxHash,
+			ErrorCode:       request.ErrorCode,
+		},
+	})
+	if err != nil {
+		return delivery, err
+	}
+	delivery.Payload = string(payload)
+
+	return delivery, nil
+}
+
+// StartDeliveries starts a delivery workflow on the task list for each
+// delivery. Starting one that is already running is not an error. A delivery
+// that can't be started doesn't hold up the others, so every one is tried and
+// those that failed are returned with the first error.
+func StartDeliveries(cadenceClient cadence.WorkflowClient, taskList string, deliveries []models.WebhookDelivery) ([]models.WebhookDelivery, error) {
+	var (
+		unstarted []models.WebhookDelivery
+		firstErr  error
+	)
+	for _, delivery := range deliveries {
+		if err := StartDelivery(cadenceClient, taskList, delivery, "webhook-delivery-"+delivery.ID.String()); err != nil {
+			unstarted = append(unstarted, delivery)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return unstarted, firstErr
+}
+
+func StartDelivery(cadenceClient cadence.WorkflowClient, taskList string, delivery models.WebhookDelivery, workflowID string) error {
+	workflowOptions := client.StartWorkflowOptions{
+		ID:                           workflowID,
+		TaskList:                     taskList,
+		ExecutionStartToCloseTimeout: time.Hour * 25,
+	}
+
+	_, err := cadenceClient.ExecuteWorkflow(context.Background(), workflowOptions, DeliveryWorkflowName, delivery.ID.String())
+	var alreadyStarted *shared.WorkflowExecutionAlreadyStartedError
+	if err != nil && !errors.As(err, &alreadyStarted) {
+		return err
+	}
+
+	return nil
+}
+
+// Deliver posts the delivery's payload to the webhook, signed with the
+// webhook's secret. Any response other than 2xx is an error.
+func Deliver(httpClient *http.Client, hook models.Webhook, delivery models.WebhookDelivery, now time.Time) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := now.Unix()
+
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, delivery.ID.String())
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(hook.Secret, timestamp, body))
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,157 @@
+package workflows
+
+import (
+	"mint-redeem-workflow/activities"
+	"time"
+
+	"go.uber.org/cadence"
+	"go.uber.org/cadence/workflow"
+	"go.uber.org/zap"
+)
+
+const (
+	ApprovalSignalName = "approval"
+	ApprovalStateQuery = "approval_state"
+)
+
+// ApprovalSignal is sent by the approve and reject endpoints. Client is the
+// API client that sent it, and Approver the approver whose key is bound to
+// that client. Signature is the approver's base64 ed25519 signature of the
+// request's approval payload.
+type ApprovalSignal struct {
+	Approved  bool
+	Approver  string
+	Client    string
+	Reason    string
+	Signature string
+}
+
+// ApprovalState is returned by the approval_state query. SignsDecision is set
+// when the workflow verifies signatures of the decision payload rather than
+// the request's approval payload alone.
+type ApprovalState struct {
+	Required      int
+	Submitter     string
+	Decision      string
+	SignsDecision bool
+	Approvals     []ApprovalSignal
+	Ignored       []ApprovalSignal
+}
+
+// awaitApprovals registers the approval_state query and, when approvals are
+// required, moves the request to "awaiting_approval" and collects approval
+// signals until the required number of distinct approvers have approved, anyone
+// rejects, or the timeout fires. The submitting client can't decide on its own
+// request, whichever approver it sends the decision as,
+// repeat decisions from the same approver are ignored, and decisions only count
+// once their signature of the decision is verified against the approver's key. A rejection or
+// timeout returns a "rejected" custom error so the workflow fails without
+// calling Brale. An activity that fails on the way marks the request failed.
+func awaitApprovals(ctx workflow.Context, requestID string, submitter string, required int, timeout time.Duration) error {
+	logger := workflow.GetLogger(ctx)
+
+	state := ApprovalState{
+		Required:  required,
+		Submitter: submitter,
+		Decision:  "pending",
+	}
+	if required <= 0 {
+		state.Decision = "not_required"
+	}
+	if err := workflow.SetQueryHandler(ctx, ApprovalStateQuery, func() (ApprovalState, error) {
+		return state, nil
+	}); err != nil {
+		return err
+	}
+
+	if state.Decision == "not_required" {
+		return nil
+	}
+
+	state.SignsDecision = workflow.GetVersion(ctx, approvalDecisionChangeID, workflow.DefaultVersion, 1) == 1
+
+	if err := workflow.ExecuteActivity(ctx, acts.UpdateStatusActivity, requestID, "awaiting_approval").Get(ctx, nil); err != nil {
+		return failRequest(ctx, requestID, err)
+	}
+
+	timerCtx, cancelTimer := workflow.WithCancel(ctx)
+	defer cancelTimer()
+	timer := workflow.NewTimer(timerCtx, timeout)
+	signals := workflow.GetSignalChannel(ctx, ApprovalSignalName)
+
+	decided := map[string]bool{}
+	reason := ""
+	for state.Decision == "pending" {
+		var signal ApprovalSignal
+		timedOut := false
+
+		selector := workflow.NewSelector(ctx)
+		selector.AddReceive(signals, func(c workflow.Channel, more bool) {
+			c.Receive(ctx, &signal)
+		})
+		selector.AddFuture(timer, func(f workflow.Future) {
+			timedOut = true
+		})
+		selector.Select(ctx)
+
+		if timedOut {
+			signal = ApprovalSignal{Approved: false, Approver: "system", Reason: "approval timed out"}
+		} else if submitter != "" && (signal.Client == submitter || signal.Approver == submitter) {
+			signal.Reason = "submitter can't approve their own request"
+			state.Ignored = append(state.Ignored, signal)
+			logger.Warn("Ignoring approval from submitter.", zap.String("RequestID", requestID), zap.String("Approver", signal.Approver), zap.String("Client", signal.Client))
+			continue
+		} else if decided[signal.Approver] {
+			signal.Reason = "approver already decided"
+			state.Ignored = append(state.Ignored, signal)
+			logger.Warn("Ignoring duplicate approval.", zap.String("RequestID", requestID), zap.String("Approver", signal.Approver))
+			continue
+		}
+
+		if !timedOut {
+			var verify workflow.Future
+			if state.SignsDecision {
+				verify = workflow.ExecuteActivity(ctx, acts.VerifyApprovalDecisionActivity, requestID, signal.Approver, signal.Client, signal.Approved, signal.Signature)
+			} else {
+				verify = workflow.ExecuteActivity(ctx, acts.VerifyApprovalActivity, requestID, signal.Approver, signal.Signature)
+			}
+			var verifyRes activities.VerifyApprovalActivityResponse
+			if err := verify.Get(ctx, &verifyRes); err != nil {
+				return failRequest(ctx, requestID, err)
+			}
+			if !verifyRes.Valid {
+				signal.Reason = verifyRes.Reason
+				state.Ignored = append(state.Ignored, signal)
+				logger.Warn("Ignoring approval with invalid signature.", zap.String("RequestID", requestID), zap.String("Approver", signal.Approver), zap.String("Reason", verifyRes.Reason))
+				continue
+			}
+		}
+		decided[signal.Approver] = true
+
+		logger.Info("Approval decision received.", zap.String("RequestID", requestID), zap.Bool("Approved", signal.Approved), zap.String("Approver", signal.Approver))
+		if err := workflow.ExecuteActivity(ctx, acts.RecordApprovalActivity, requestID, signal.Approver, signal.Approved, signal.Reason, signal.Signature).Get(ctx, nil); err != nil {
+			return failRequest(ctx, requestID, err)
+		}
+
+		if !signal.Approved {
+			state.Decision = "rejected"
+			reason = signal.Reason
+			continue
+		}
+
+		state.Approvals = append(state.Approvals, signal)
+		if len(state.Approvals) >= required {
+			state.Decision = "approved"
+		}
+	}
+
+	if err := workflow.ExecuteActivity(ctx, acts.UpdateStatusWithReasonActivity, requestID, state.Decision, reason).Get(ctx, nil); err != nil {
+		return failRequest(ctx, requestID, err)
+	}
+
+	if state.Decision == "rejected" {
+		return cadence.NewCustomError("rejected", reason)
+	}
+
+	return nil
+}
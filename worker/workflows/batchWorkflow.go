@@ -0,0 +1,109 @@
+package workflows
+
+import (
+	"time"
+
+	"go.uber.org/cadence/workflow"
+	"go.uber.org/zap"
+)
+
+// BatchItem is one request of a batch. Batches started before Request was
+// added set Mint or Redeem instead.
+type BatchItem struct {
+	Request *RequestInput
+	Mint    *RequestInput
+	Redeem  *RequestInput
+}
+
+// Input returns the item's request, with its type set.
+func (item BatchItem) Input() *RequestInput {
+	switch {
+	case item.Mint != nil:
+		item.Mint.Type = "mint"
+		return item.Mint
+	case item.Redeem != nil:
+		item.Redeem.Type = "redeem"
+		return item.Redeem
+	}
+	return item.Request
+}
+
+type BatchInput struct {
+	BatchID     string
+	Items       []BatchItem
+	Concurrency int
+}
+
+// childExecutionTimeout matches the execution timeout single requests are
+// started with.
+func childExecutionTimeout(approvalTimeout time.Duration) time.Duration {
+	return time.Minute*5 + approvalTimeout
+}
+
+// requestWorkflow is the workflow a child request is started as.
+func requestWorkflow(version workflow.Version, opType string) interface{} {
+	if version == workflow.DefaultVersion {
+		return legacyRequestWorkflows[opType]
+	}
+	return RequestWorkflow
+}
+
+// BatchWorkflow runs each item of the batch as a RequestWorkflow child, at most
+// Concurrency at a time, and records the aggregate status once every child has
+// finished. A failed child doesn't stop the rest of the batch.
+func BatchWorkflow(ctx workflow.Context, input BatchInput) error {
+	logger := workflow.GetLogger(ctx)
+	logger.Info("BatchWorkflow started", zap.String("BatchID", input.BatchID), zap.Int("Items", len(input.Items)))
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+
+	concurrency := input.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	version := workflow.GetVersion(ctx, requestWorkflowChangeID, workflow.DefaultVersion, 1)
+	selector := workflow.NewSelector(ctx)
+	running, completed, failed := 0, 0, 0
+
+	for _, item := range input.Items {
+		if running >= concurrency {
+			selector.Select(ctx)
+			running--
+		}
+
+		request := item.Input()
+		childCtx := workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{
+			WorkflowID:                   request.RequestID,
+			ExecutionStartToCloseTimeout: childExecutionTimeout(request.ApprovalTimeout),
+		})
+		future := workflow.ExecuteChildWorkflow(childCtx, requestWorkflow(version, request.Type), *request)
+
+		selector.AddFuture(future, func(f workflow.Future) {
+			if err := f.Get(ctx, nil); err != nil {
+				failed++
+				return
+			}
+			completed++
+		})
+		running++
+	}
+
+	for ; running > 0; running-- {
+		selector.Select(ctx)
+	}
+
+	status := "completed"
+	if failed > 0 && completed > 0 {
+		status = "partially_failed"
+	} else if failed > 0 {
+		status = "failed"
+	}
+
+	if err := workflow.ExecuteActivity(ctx, acts.UpdateBatchStatusActivity, input.BatchID, status).Get(ctx, nil); err != nil {
+		return err
+	}
+
+	logger.Info("Batch completed.", zap.String("BatchID", input.BatchID), zap.Int("Completed", completed), zap.Int("Failed", failed))
+
+	return nil
+}
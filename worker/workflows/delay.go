@@ -0,0 +1,108 @@
+package workflows
+
+import (
+	"mint-redeem-workflow/activities"
+	"time"
+
+	"go.uber.org/cadence"
+	"go.uber.org/cadence/workflow"
+	"go.uber.org/zap"
+)
+
+const RescheduleSignalName = "reschedule"
+
+// RescheduleSignal is sent by the cancel and reschedule endpoints while a
+// request is waiting for its execute_at time.
+type RescheduleSignal struct {
+	Cancel    bool
+	ExecuteAt time.Time
+}
+
+// CanceledBeforeExecuteAt is the reason recorded on a request canceled while
+// it waited for its execute_at time.
+const CanceledBeforeExecuteAt = "canceled before execute_at"
+
+// waitForExecuteAt sleeps on a durable timer until executeAt, then moves the
+// request to "started". A reschedule signal restarts the timer for the new time
+// and a cancel signal moves the request to "canceled" and returns a "canceled"
+// custom error so the workflow ends without calling Brale. A zero executeAt
+// runs the request straight away. The request is only started while it is
+// still scheduled for the time the timer fired at, so a cancel or reschedule
+// saved just before its signal arrives still wins.
+func waitForExecuteAt(ctx workflow.Context, requestID string, executeAt time.Time) error {
+	if executeAt.IsZero() {
+		return nil
+	}
+
+	logger := workflow.GetLogger(ctx)
+	signals := workflow.GetSignalChannel(ctx, RescheduleSignalName)
+
+	for {
+		delay := executeAt.Sub(workflow.Now(ctx))
+		if delay > 0 {
+			timerCtx, cancelTimer := workflow.WithCancel(ctx)
+			timer := workflow.NewTimer(timerCtx, delay)
+
+			var signal RescheduleSignal
+			fired := false
+			selector := workflow.NewSelector(ctx)
+			selector.AddFuture(timer, func(f workflow.Future) {
+				fired = true
+			})
+			selector.AddReceive(signals, func(c workflow.Channel, more bool) {
+				c.Receive(ctx, &signal)
+			})
+			selector.Select(ctx)
+			cancelTimer()
+
+			if !fired {
+				if signal.Cancel {
+					return cancelScheduled(ctx, requestID)
+				}
+
+				logger.Info("Scheduled request rescheduled.", zap.String("RequestID", requestID), zap.Time("ExecuteAt", signal.ExecuteAt))
+				executeAt = signal.ExecuteAt
+				if err := workflow.ExecuteActivity(ctx, acts.RescheduleRequestActivity, requestID, executeAt).Get(ctx, nil); err != nil {
+					return failRequest(ctx, requestID, err)
+				}
+				continue
+			}
+		}
+
+		if workflow.GetVersion(ctx, scheduledStartChangeID, workflow.DefaultVersion, 1) == workflow.DefaultVersion {
+			break
+		}
+
+		var started activities.StartScheduledRequestActivityResponse
+		if err := workflow.ExecuteActivity(ctx, acts.StartScheduledRequestActivity, requestID, executeAt).Get(ctx, &started); err != nil {
+			return failRequest(ctx, requestID, err)
+		}
+		if started.Canceled {
+			logger.Info("Scheduled request canceled.", zap.String("RequestID", requestID))
+			return cadence.NewCustomError("canceled", CanceledBeforeExecuteAt)
+		}
+		if !started.ExecuteAt.IsZero() {
+			logger.Info("Scheduled request rescheduled.", zap.String("RequestID", requestID), zap.Time("ExecuteAt", started.ExecuteAt))
+			executeAt = started.ExecuteAt
+			continue
+		}
+
+		return nil
+	}
+
+	if err := workflow.ExecuteActivity(ctx, acts.UpdateStatusActivity, requestID, "started").Get(ctx, nil); err != nil {
+		return failRequest(ctx, requestID, err)
+	}
+
+	return nil
+}
+
+// cancelScheduled marks the request canceled and returns the "canceled"
+// custom error that ends its workflow.
+func cancelScheduled(ctx workflow.Context, requestID string) error {
+	workflow.GetLogger(ctx).Info("Scheduled request canceled.", zap.String("RequestID", requestID))
+	if err := workflow.ExecuteActivity(ctx, acts.UpdateStatusWithReasonActivity, requestID, "canceled", CanceledBeforeExecuteAt).Get(ctx, nil); err != nil {
+		return failRequest(ctx, requestID, err)
+	}
+	return cadence.NewCustomError("canceled", CanceledBeforeExecuteAt)
+}
@@ -0,0 +1,76 @@
+package workflows
+
+import (
+	"mint-redeem-workflow/activities"
+	"mint-redeem-workflow/infra/brale"
+	"mint-redeem-workflow/operations"
+	"time"
+
+	"go.uber.org/cadence/workflow"
+	"go.uber.org/zap"
+)
+
+type ReconciliationInput struct {
+	ReportID string
+	From     time.Time
+	To       time.Time
+}
+
+// fetchOrdersActivityOptions allow for paging through a day of orders and
+// transfers.
+var fetchOrdersActivityOptions = workflow.ActivityOptions{
+	ScheduleToStartTimeout: time.Minute,
+	StartToCloseTimeout:    time.Minute * 5,
+	HeartbeatTimeout:       time.Minute,
+	RetryPolicy:            operations.BraleRetryPolicy,
+}
+
+// ReconciliationWorkflow reconciles Brale's orders and transfers for the
+// report's range against our requests. If Brale can't be read the report is
+// marked failed with the error.
+func ReconciliationWorkflow(ctx workflow.Context, input ReconciliationInput) error {
+	logger := workflow.GetLogger(ctx)
+	logger.Info("ReconciliationWorkflow started", zap.String("ReportID", input.ReportID), zap.Time("From", input.From), zap.Time("To", input.To))
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+
+	if workflow.GetVersion(ctx, reconcileActivityChangeID, workflow.DefaultVersion, 1) == workflow.DefaultVersion {
+		return legacyReconciliation(ctx, input)
+	}
+
+	var res activities.ReconcileActivityResponse
+	fetchCtx := workflow.WithActivityOptions(ctx, fetchOrdersActivityOptions)
+	if err := workflow.ExecuteActivity(fetchCtx, acts.ReconcileActivity, input.ReportID).Get(ctx, &res); err != nil {
+		return failReconciliation(ctx, input.ReportID, err)
+	}
+
+	logger.Info("Reconciliation completed.", zap.String("ReportID", input.ReportID), zap.Int("Orders", res.Orders), zap.Int("Transfers", res.Transfers))
+
+	return nil
+}
+
+// legacyReconciliation is what reconciliations started before
+// reconcileActivityChangeID do: the orders come back to the workflow and are
+// stored by a second activity.
+func legacyReconciliation(ctx workflow.Context, input ReconciliationInput) error {
+	var orders []brale.Order
+	fetchCtx := workflow.WithActivityOptions(ctx, fetchOrdersActivityOptions)
+	if err := workflow.ExecuteActivity(fetchCtx, acts.FetchBraleOrdersActivity, input.From, input.To).Get(ctx, &orders); err != nil {
+		return failReconciliation(ctx, input.ReportID, err)
+	}
+
+	if err := workflow.ExecuteActivity(ctx, acts.StoreReconciliationActivity, input.ReportID, orders).Get(ctx, nil); err != nil {
+		return err
+	}
+
+	workflow.GetLogger(ctx).Info("Reconciliation completed.", zap.String("ReportID", input.ReportID), zap.Int("Orders", len(orders)))
+
+	return nil
+}
+
+// failReconciliation marks the report failed with err and returns err.
+func failReconciliation(ctx workflow.Context, reportID string, err error) error {
+	if failErr := workflow.ExecuteActivity(ctx, acts.FailReconciliationActivity, reportID, errorDetail(err)).Get(ctx, nil); failErr != nil {
+		return failErr
+	}
+	return err
+}
@@ -0,0 +1,61 @@
+package workflows
+
+import (
+	"mint-redeem-workflow/webhooks"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/cadence/worker"
+	"go.uber.org/cadence/workflow"
+	"go.uber.org/zap/zaptest"
+)
+
+// TestReplay_Histories replays every history in testdata/histories through
+// the current workflow code. A failure means a change would break the
+// workflows that history stands for with non-determinism errors, and needs a
+// workflow.GetVersion branch (see version.go).
+//
+// testdata/histories/recorded is for histories exported from a Cadence
+// cluster, and says how to export them. None are committed yet, so that half
+// of the test is skipped. testdata/histories/synthetic is written by
+// testdata/histgen from a hand-written list of the decisions each earlier
+// version of a workflow made, so it only catches regressions that list
+// describes correctly.
+func TestReplay_Histories(t *testing.T) {
+	t.Run("recorded", func(t *testing.T) {
+		histories := replayHistories(t, "recorded")
+		if len(histories) == 0 {
+			t.Skip("no histories recorded from a Cadence cluster yet, see testdata/histories/recorded/README.md")
+		}
+		replay(t, histories)
+	})
+	t.Run("synthetic", func(t *testing.T) {
+		histories := replayHistories(t, "synthetic")
+		require.NotEmpty(t, histories)
+		replay(t, histories)
+	})
+}
+
+func replayHistories(t *testing.T, dir string) []string {
+	histories, err := filepath.Glob(filepath.Join("testdata", "histories", dir, "*.json"))
+	require.NoError(t, err)
+	return histories
+}
+
+func replay(t *testing.T, histories []string) {
+	replayer := worker.NewWorkflowReplayer()
+	replayer.RegisterWorkflow(RequestWorkflow)
+	replayer.RegisterWorkflow(MintWorkflow)
+	replayer.RegisterWorkflow(RedeemWorkflow)
+	replayer.RegisterWorkflow(BatchWorkflow)
+	replayer.RegisterWorkflow(ScheduledRequestWorkflow)
+	replayer.RegisterWorkflow(ReconciliationWorkflow)
+	replayer.RegisterWorkflowWithOptions(WebhookDeliveryWorkflow, workflow.RegisterOptions{Name: webhooks.DeliveryWorkflowName})
+
+	for _, history := range histories {
+		t.Run(filepath.Base(history), func(t *testing.T) {
+			require.NoError(t, replayer.ReplayWorkflowHistoryFromJSONFile(zaptest.NewLogger(t), history))
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package workflows
+
+import (
+	"mint-redeem-workflow/activities"
+	"mint-redeem-workflow/operations"
+	"time"
+
+	"go.uber.org/cadence"
+	"go.uber.org/cadence/workflow"
+	"go.uber.org/zap"
+)
+
+// RequestInput starts a RequestWorkflow. Type picks the registered operation.
+type RequestInput struct {
+	Type      string
+	Amount    float64
+	Recipient string
+	Token     string
+	Chain     string
+	RequestID string
+
+	Submitter         string
+	RequiredApprovals int
+	ApprovalTimeout   time.Duration
+
+	// ExecuteAt delays the request until that time. Zero runs it straight away.
+	ExecuteAt time.Time
+}
+
+// acts is how workflows refer to the activities. It stays nil: Cadence only
+// needs the method to look up the name it was registered under.
+var acts *activities.Activities
+
+var activityOptions = workflow.ActivityOptions{
+	ScheduleToStartTimeout: time.Minute,
+	StartToCloseTimeout:    time.Minute,
+	HeartbeatTimeout:       time.Second * 20,
+	RetryPolicy:            requestRetryPolicy,
+}
+
+// RequestWorkflow runs a request of any registered operation: it waits for
+// execute_at, screens the recipient, collects approvals, places the order with
+// Brale and posts the completed request to the ledger.
+func RequestWorkflow(ctx workflow.Context, input RequestInput) error {
+	logger := workflow.GetLogger(ctx)
+	logger.Info("RequestWorkflow started", zap.String("Type", input.Type))
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+
+	op, err := operations.Lookup(input.Type)
+	if err != nil {
+		return failRequest(ctx, input.RequestID, cadence.NewCustomError("unknown_operation", err.Error()))
+	}
+
+	if err := waitForExecuteAt(ctx, input.RequestID, input.ExecuteAt); err != nil {
+		return err
+	}
+
+	if err := screenRecipient(ctx, input.Recipient, input.RequestID); err != nil {
+		return err
+	}
+
+	if err := awaitApprovals(ctx, input.RequestID, input.Submitter, input.RequiredApprovals, input.ApprovalTimeout); err != nil {
+		return err
+	}
+
+	var res activities.OperationActivityResponse
+	res.RequestId = input.RequestID
+
+	opCtx := workflow.WithActivityOptions(ctx, op.ActivityOptions)
+	var future workflow.Future
+	if workflow.GetVersion(ctx, operationActivityChangeID, workflow.DefaultVersion, 1) == workflow.DefaultVersion {
+		future = workflow.ExecuteActivity(opCtx, legacyOperationActivities[input.Type], input.Amount, input.Recipient, input.Token, input.Chain, input.RequestID)
+	} else {
+		future = workflow.ExecuteActivity(opCtx, acts.ExecuteOperationActivity, input.Type, input.Amount, input.Recipient, input.Token, input.Chain, input.RequestID)
+	}
+	if err := future.Get(ctx, &res); err != nil {
+		// A request canceled before its order was sent is already canceled.
+		if errorCode(err) == operations.ReasonCanceled {
+			return err
+		}
+		return failRequest(ctx, res.RequestId, err)
+	}
+
+	// A retry of a request that failed here finds the order Brale already
+	// placed under the same idempotency key.
+	if err := workflow.ExecuteActivity(ctx, acts.CompleteRequestActivity, res.RequestId, res.OrderID, res.TxHash).Get(ctx, nil); err != nil {
+		return failRequest(ctx, res.RequestId, err)
+	}
+	// The request is completed by now, so a posting that fails is left to the
+	// workflow's error rather than failing the request. Refunding the request
+	// posts it as well.
+	if err := workflow.ExecuteActivity(ctx, acts.PostLedgerEntryActivity, res.RequestId).Get(ctx, nil); err != nil {
+		return err
+	}
+
+	logger.Info("Workflow completed.", zap.String("Result", res.RequestId))
+
+	return nil
+}
+
+// MintWorkflow and RedeemWorkflow are the workflow types requests were
+// started as before RequestWorkflow. They stay registered until none of those
+// workflows are open.
+func MintWorkflow(ctx workflow.Context, input RequestInput) error {
+	input.Type = "mint"
+	return RequestWorkflow(ctx, input)
+}
+
+func RedeemWorkflow(ctx workflow.Context, input RequestInput) error {
+	input.Type = "redeem"
+	return RequestWorkflow(ctx, input)
+}
+
+// legacyOperationActivities are the per type activities workflows scheduled
+// before the operationActivityChangeID change.
+var legacyOperationActivities = map[string]interface{}{
+	"mint":   acts.MintActivity,
+	"redeem": acts.RedeemActivity,
+}
+
+// legacyRequestWorkflows are the per type child workflows batches and
+// schedules started before the requestWorkflowChangeID change.
+var legacyRequestWorkflows = map[string]interface{}{
+	"mint":   MintWorkflow,
+	"redeem": RedeemWorkflow,
+}
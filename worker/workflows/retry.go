@@ -0,0 +1,59 @@
+package workflows
+
+import (
+	"errors"
+	"time"
+
+	"go.uber.org/cadence"
+	"go.uber.org/cadence/workflow"
+)
+
+// requestRetryPolicy retries the activities that only touch our own database
+// and the sanctions list.
+var requestRetryPolicy = &cadence.RetryPolicy{
+	InitialInterval:    time.Second,
+	BackoffCoefficient: 2,
+	MaximumInterval:    time.Second * 30,
+	ExpirationInterval: time.Minute * 2,
+	MaximumAttempts:    5,
+}
+
+// failRequest marks the request as failed with the code and detail of the
+// error that ended the workflow and returns that error.
+func failRequest(ctx workflow.Context, requestID string, err error) error {
+	if updateErr := workflow.ExecuteActivity(ctx, acts.FailRequestActivity, requestID, errorCode(err), errorDetail(err)).Get(ctx, nil); updateErr != nil {
+		return updateErr
+	}
+	return err
+}
+
+func errorCode(err error) string {
+	var customErr *cadence.CustomError
+	if errors.As(err, &customErr) {
+		return customErr.Reason()
+	}
+
+	var timeoutErr *workflow.TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return "timeout"
+	}
+
+	var canceledErr *cadence.CanceledError
+	if errors.As(err, &canceledErr) {
+		return "canceled"
+	}
+
+	return "activity_error"
+}
+
+func errorDetail(err error) string {
+	var customErr *cadence.CustomError
+	if errors.As(err, &customErr) && customErr.HasDetails() {
+		var detail string
+		if customErr.Details(&detail) == nil {
+			return detail
+		}
+	}
+
+	return err.Error()
+}
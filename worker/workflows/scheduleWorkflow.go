@@ -0,0 +1,121 @@
+package workflows
+
+import (
+	"mint-redeem-workflow/activities"
+	"time"
+
+	"github.com/robfig/cron"
+	"go.uber.org/cadence/workflow"
+	"go.uber.org/zap"
+)
+
+// ScheduleInput is fixed when the schedule is created, so the approval
+// settings are worked out once from the schedule's amount.
+type ScheduleInput struct {
+	ScheduleID        string
+	RequiredApprovals int
+	ApprovalTimeout   time.Duration
+}
+
+// ScheduleExecutionTimeout is how long each cron run may take: long enough for
+// its request to wait out the approval timeout.
+func ScheduleExecutionTimeout(approvalTimeout time.Duration) time.Duration {
+	return childExecutionTimeout(approvalTimeout) + time.Minute*5
+}
+
+// ScheduledRequestWorkflow is started with a CronSchedule and runs once per
+// firing. Each run creates the occurrence's request, keyed by the time the run
+// was due, and runs it as a RequestWorkflow child. A failed request is left
+// failed on its row and doesn't fail the run, so the schedule keeps firing.
+func ScheduledRequestWorkflow(ctx workflow.Context, input ScheduleInput) error {
+	logger := workflow.GetLogger(ctx)
+	occurrenceID := workflow.GetInfo(ctx).WorkflowExecution.RunID
+	if workflow.GetVersion(ctx, occurrenceIDChangeID, workflow.DefaultVersion, 1) == 1 {
+		occurrenceID = occurrenceKey(workflow.GetInfo(ctx), workflow.Now(ctx))
+	}
+	logger.Info("ScheduledRequestWorkflow started", zap.String("ScheduleID", input.ScheduleID), zap.String("OccurrenceID", occurrenceID))
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+
+	var scheduled activities.CreateScheduledRequestActivityResponse
+	if err := workflow.ExecuteActivity(ctx, acts.CreateScheduledRequestActivity, input.ScheduleID, occurrenceID).Get(ctx, &scheduled); err != nil {
+		return err
+	}
+
+	if scheduled.Skipped {
+		logger.Info("Scheduled request skipped.", zap.String("ScheduleID", input.ScheduleID), zap.String("Reason", scheduled.Reason))
+		return nil
+	}
+
+	childCtx := workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{
+		WorkflowID:                   scheduled.RequestId,
+		ExecutionStartToCloseTimeout: childExecutionTimeout(input.ApprovalTimeout),
+	})
+
+	version := workflow.GetVersion(ctx, requestWorkflowChangeID, workflow.DefaultVersion, 1)
+	future := workflow.ExecuteChildWorkflow(childCtx, requestWorkflow(version, scheduled.Type), RequestInput{
+		Type:              scheduled.Type,
+		Amount:            scheduled.Amount,
+		Recipient:         scheduled.Recipient,
+		Token:             scheduled.Token,
+		Chain:             scheduled.Chain,
+		RequestID:         scheduled.RequestId,
+		Submitter:         scheduled.Submitter,
+		RequiredApprovals: input.RequiredApprovals,
+		ApprovalTimeout:   input.ApprovalTimeout,
+	})
+
+	if err := future.Get(ctx, nil); err != nil {
+		logger.Error("Scheduled request failed.", zap.String("RequestID", scheduled.RequestId), zap.Error(err))
+		return nil
+	}
+
+	logger.Info("Scheduled request completed.", zap.String("RequestID", scheduled.RequestId))
+
+	return nil
+}
+
+// occurrenceKey identifies a cron run by the time it was due, the latest
+// firing of the cron schedule at or before now. Unlike the run ID, it is the
+// same for a run that is reset or started again for the same firing. Without a
+// cron schedule it falls back to the run ID.
+func occurrenceKey(info *workflow.Info, now time.Time) string {
+	if info.CronSchedule == nil {
+		return info.WorkflowExecution.RunID
+	}
+	schedule, err := cron.ParseStandard(*info.CronSchedule)
+	if err != nil {
+		return info.WorkflowExecution.RunID
+	}
+
+	fired, ok := lastFiring(schedule, now.UTC())
+	if !ok {
+		return info.WorkflowExecution.RunID
+	}
+
+	return fired.Format(time.RFC3339)
+}
+
+// maxFiringLookback is as far back as lastFiring looks, which is also as far
+// ahead as the cron parser looks for the next firing.
+const maxFiringLookback = time.Hour * 24 * 366 * 5
+
+// lastFiring returns the latest firing of the schedule at or before now. It
+// looks back over a doubling window until the window holds a firing, then
+// walks forward to the last one.
+func lastFiring(schedule cron.Schedule, now time.Time) (time.Time, bool) {
+	window := time.Minute
+	fired := schedule.Next(now.Add(-window))
+	for (fired.IsZero() || fired.After(now)) && window < maxFiringLookback {
+		window *= 2
+		fired = schedule.Next(now.Add(-window))
+	}
+	if fired.IsZero() || fired.After(now) {
+		return time.Time{}, false
+	}
+
+	for next := schedule.Next(fired); !next.IsZero() && !next.After(now); next = schedule.Next(next) {
+		fired = next
+	}
+
+	return fired, true
+}
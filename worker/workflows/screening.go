@@ -0,0 +1,32 @@
+package workflows
+
+import (
+	"mint-redeem-workflow/activities"
+
+	"go.uber.org/cadence"
+	"go.uber.org/cadence/workflow"
+	"go.uber.org/zap"
+)
+
+// screenRecipient runs the sanctions screening before anything is sent to
+// Brale. A match marks the request as blocked and returns a "blocked" custom
+// error so the workflow fails without calling Brale.
+func screenRecipient(ctx workflow.Context, recipient string, requestID string) error {
+	logger := workflow.GetLogger(ctx)
+
+	var screeningRes activities.ScreeningActivityResponse
+	if err := workflow.ExecuteActivity(ctx, acts.ScreeningActivity, recipient, requestID).Get(ctx, &screeningRes); err != nil {
+		return failRequest(ctx, requestID, err)
+	}
+
+	if !screeningRes.Blocked {
+		return nil
+	}
+
+	logger.Warn("Recipient blocked by sanctions screening.", zap.String("RequestID", requestID), zap.String("Reason", screeningRes.Reason))
+	if err := workflow.ExecuteActivity(ctx, acts.UpdateStatusWithReasonActivity, requestID, "blocked", screeningRes.Reason).Get(ctx, nil); err != nil {
+		return err
+	}
+
+	return cadence.NewCustomError("blocked", screeningRes.Reason)
+}
@@ -0,0 +1,274 @@
+// Command histgen writes the synthetic histories in ../histories/synthetic.
+// They are not recorded from a Cadence cluster: each one lists, event by
+// event, the decisions a workflow made under an earlier version of the code,
+// so the replay test fails if the current code would decide differently. They
+// are only as accurate as the descriptions below and only supplement the
+// histories recorded from a cluster in ../histories/recorded.
+//
+//	go run ./worker/workflows/testdata/histgen worker/workflows/testdata/histories/synthetic
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mint-redeem-workflow/activities"
+	"mint-redeem-workflow/infra/brale"
+	"mint-redeem-workflow/worker/workflows"
+	"os"
+	"time"
+
+	"go.uber.org/cadence/.gen/go/shared"
+	"go.uber.org/cadence/workflow"
+)
+
+const (
+	taskList = "test-worker"
+
+	// identity marks every event as written by this generator rather than a
+	// real worker.
+	identity = "histgen@synthetic"
+)
+
+type builder struct {
+	events     []*shared.HistoryEvent
+	now        time.Time
+	lastDTDone int64
+	nextID     int
+	scheduled  map[string]int64
+}
+
+func enc(values ...interface{}) []byte {
+	var buf bytes.Buffer
+	e := json.NewEncoder(&buf)
+	for _, v := range values {
+		if err := e.Encode(v); err != nil {
+			panic(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+var requestPolicy = &shared.RetryPolicy{InitialIntervalInSeconds: i32(1), BackoffCoefficient: f64(2), MaximumIntervalInSeconds: i32(30), MaximumAttempts: i32(5), ExpirationIntervalInSeconds: i32(120), NonRetriableErrorReasons: []string{}}
+var bralePolicy = &shared.RetryPolicy{InitialIntervalInSeconds: i32(1), BackoffCoefficient: f64(2), MaximumIntervalInSeconds: i32(60), MaximumAttempts: i32(6), ExpirationIntervalInSeconds: i32(180), NonRetriableErrorReasons: brale.NonRetryableReasons}
+
+func f64(v float64) *float64 { return &v }
+func s(v string) *string     { return &v }
+func i32(v int32) *int32     { return &v }
+func i64(v int64) *int64     { return &v }
+
+func (b *builder) add(t shared.EventType, fill func(e *shared.HistoryEvent)) int64 {
+	b.now = b.now.Add(time.Millisecond * 37)
+	id := int64(len(b.events) + 1)
+	e := &shared.HistoryEvent{EventId: i64(id), Timestamp: i64(b.now.UnixNano()), EventType: &t, Version: i64(0), TaskId: i64(1048576 + id)}
+	fill(e)
+	b.events = append(b.events, e)
+	return id
+}
+
+func (b *builder) start(workflowType string, input interface{}, timeout int32) {
+	b.add(shared.EventTypeWorkflowExecutionStarted, func(e *shared.HistoryEvent) {
+		e.WorkflowExecutionStartedEventAttributes = &shared.WorkflowExecutionStartedEventAttributes{
+			WorkflowType:                        &shared.WorkflowType{Name: s(workflowType)},
+			TaskList:                            &shared.TaskList{Name: s(taskList)},
+			Input:                               enc(input),
+			ExecutionStartToCloseTimeoutSeconds: i32(timeout),
+			TaskStartToCloseTimeoutSeconds:      i32(10),
+			OriginalExecutionRunId:              s("8e7a1f0c-3c55-4d2b-9a0e-5b1f3d2c7a41"),
+			Identity:                            s(identity),
+			FirstExecutionRunId:                 s("8e7a1f0c-3c55-4d2b-9a0e-5b1f3d2c7a41"),
+			Attempt:                             i32(0),
+		}
+	})
+	b.decision()
+}
+
+func (b *builder) decision() {
+	sch := b.add(shared.EventTypeDecisionTaskScheduled, func(e *shared.HistoryEvent) {
+		e.DecisionTaskScheduledEventAttributes = &shared.DecisionTaskScheduledEventAttributes{TaskList: &shared.TaskList{Name: s(taskList)}, StartToCloseTimeoutSeconds: i32(10), Attempt: i64(0)}
+	})
+	st := b.add(shared.EventTypeDecisionTaskStarted, func(e *shared.HistoryEvent) {
+		e.DecisionTaskStartedEventAttributes = &shared.DecisionTaskStartedEventAttributes{ScheduledEventId: i64(sch), Identity: s(identity), RequestId: s(fmt.Sprintf("a1b2c3d4-%04d-4e5f-8a9b-0c1d2e3f4a5b", sch))}
+	})
+	b.lastDTDone = b.add(shared.EventTypeDecisionTaskCompleted, func(e *shared.HistoryEvent) {
+		e.DecisionTaskCompletedEventAttributes = &shared.DecisionTaskCompletedEventAttributes{ScheduledEventId: i64(sch), StartedEventId: i64(st), Identity: s(identity), BinaryChecksum: s("synthetic")}
+	})
+}
+
+func (b *builder) schedule(activity string, input ...interface{}) string {
+	id := fmt.Sprint(b.nextID)
+	b.nextID++
+	sched := b.add(shared.EventTypeActivityTaskScheduled, func(e *shared.HistoryEvent) {
+		e.ActivityTaskScheduledEventAttributes = &shared.ActivityTaskScheduledEventAttributes{
+			ActivityId:                    s(id),
+			ActivityType:                  &shared.ActivityType{Name: s("mint-redeem-workflow/activities." + activity)},
+			TaskList:                      &shared.TaskList{Name: s(taskList)},
+			Input:                         enc(input...),
+			ScheduleToCloseTimeoutSeconds: i32(120),
+			ScheduleToStartTimeoutSeconds: i32(60),
+			StartToCloseTimeoutSeconds:    i32(60),
+			HeartbeatTimeoutSeconds:       i32(20),
+			DecisionTaskCompletedEventId:  i64(b.lastDTDone),
+			RetryPolicy:                   requestPolicy,
+		}
+		if activity == "MintActivity" || activity == "RedeemActivity" {
+			e.ActivityTaskScheduledEventAttributes.RetryPolicy = bralePolicy
+		}
+	})
+	b.scheduled[id] = sched
+	return id
+}
+
+func (b *builder) complete(id string, result interface{}) {
+	sched := b.scheduled[id]
+	st := b.add(shared.EventTypeActivityTaskStarted, func(e *shared.HistoryEvent) {
+		e.ActivityTaskStartedEventAttributes = &shared.ActivityTaskStartedEventAttributes{ScheduledEventId: i64(sched), Identity: s(identity), RequestId: s(fmt.Sprintf("b2c3d4e5-%04d-4f60-9bac-1d2e3f4a5b6c", sched)), Attempt: i32(0)}
+	})
+	var out []byte
+	if result != nil {
+		out = enc(result)
+	}
+	b.add(shared.EventTypeActivityTaskCompleted, func(e *shared.HistoryEvent) {
+		e.ActivityTaskCompletedEventAttributes = &shared.ActivityTaskCompletedEventAttributes{Result: out, ScheduledEventId: i64(sched), StartedEventId: i64(st), Identity: s(identity)}
+	})
+	b.decision()
+}
+
+func (b *builder) startTimer(seconds int64) string {
+	id := fmt.Sprint(b.nextID)
+	b.nextID++
+	b.add(shared.EventTypeTimerStarted, func(e *shared.HistoryEvent) {
+		e.TimerStartedEventAttributes = &shared.TimerStartedEventAttributes{TimerId: s(id), StartToFireTimeoutSeconds: i64(seconds), DecisionTaskCompletedEventId: i64(b.lastDTDone)}
+	})
+	return id
+}
+
+func (b *builder) cancelTimer(id string) {
+	started := int64(0)
+	for _, e := range b.events {
+		if e.TimerStartedEventAttributes != nil && e.TimerStartedEventAttributes.GetTimerId() == id {
+			started = e.GetEventId()
+		}
+	}
+	b.add(shared.EventTypeTimerCanceled, func(e *shared.HistoryEvent) {
+		e.TimerCanceledEventAttributes = &shared.TimerCanceledEventAttributes{TimerId: s(id), StartedEventId: i64(started), DecisionTaskCompletedEventId: i64(b.lastDTDone), Identity: s(identity)}
+	})
+}
+
+func (b *builder) signal(name string, input interface{}) {
+	b.now = b.now.Add(time.Minute * 7)
+	b.add(shared.EventTypeWorkflowExecutionSignaled, func(e *shared.HistoryEvent) {
+		e.WorkflowExecutionSignaledEventAttributes = &shared.WorkflowExecutionSignaledEventAttributes{SignalName: s(name), Input: enc(input), Identity: s(identity)}
+	})
+	b.decision()
+}
+
+func (b *builder) version(changeID string, version workflow.Version) {
+	b.add(shared.EventTypeMarkerRecorded, func(e *shared.HistoryEvent) {
+		e.MarkerRecordedEventAttributes = &shared.MarkerRecordedEventAttributes{MarkerName: s("Version"), Details: enc(changeID, version), DecisionTaskCompletedEventId: i64(b.lastDTDone)}
+	})
+}
+
+func (b *builder) finish() {
+	b.add(shared.EventTypeWorkflowExecutionCompleted, func(e *shared.HistoryEvent) {
+		e.WorkflowExecutionCompletedEventAttributes = &shared.WorkflowExecutionCompletedEventAttributes{DecisionTaskCompletedEventId: i64(b.lastDTDone)}
+	})
+}
+
+func (b *builder) write(path string) {
+	out, err := json.MarshalIndent(b.events, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	if err := os.WriteFile(path, append(out, '\n'), 0o644); err != nil {
+		panic(err)
+	}
+}
+
+func newBuilder(at string) *builder {
+	t, _ := time.Parse(time.RFC3339Nano, at)
+	return &builder{now: t, scheduled: map[string]int64{}}
+}
+
+// legacyInput is MintInput and RedeemInput as they were encoded before
+// RequestInput.
+type legacyInput struct {
+	Amount            float64
+	Recipient         string
+	Token             string
+	Chain             string
+	RequestID         string
+	Submitter         string
+	RequiredApprovals int
+	ApprovalTimeout   time.Duration
+	ExecuteAt         time.Time
+}
+
+func main() {
+	dir := os.Args[1]
+
+	// Mint without approvals, completed.
+	{
+		requestID := "5b0d6c1e-2f4a-4c8e-9d3b-7a1e0f2c4b68"
+		b := newBuilder("2026-09-14T09:12:03.418Z")
+		b.start("mint-redeem-workflow/worker/workflows.MintWorkflow", legacyInput{Amount: 2500, Recipient: "0x8f3a7c2e91b4d6a05e1f9c3b7d2a6e4f0c8b1d93", Token: "USDC", Chain: "base", RequestID: requestID, Submitter: "treasury"}, 300)
+		id := b.schedule("ScreeningActivity", "0x8f3a7c2e91b4d6a05e1f9c3b7d2a6e4f0c8b1d93", requestID)
+		b.complete(id, activities.ScreeningActivityResponse{RequestId: requestID})
+		id = b.schedule("MintActivity", 2500.0, "0x8f3a7c2e91b4d6a05e1f9c3b7d2a6e4f0c8b1d93", "USDC", "base", requestID)
+		b.complete(id, activities.OperationActivityResponse{RequestId: requestID, OrderID: "2xN4kQ8vR1mT6pW3yZ9cB5dF7gH", TxHash: "0x4e1c9a7b3d2f8e6a0c5b1d9f7e3a2c8b6d4f0e1a9c7b5d3f2e8a6c4b0d1f9e7a"})
+		id = b.schedule("CompleteRequestActivity", requestID, "2xN4kQ8vR1mT6pW3yZ9cB5dF7gH", "0x4e1c9a7b3d2f8e6a0c5b1d9f7e3a2c8b6d4f0e1a9c7b5d3f2e8a6c4b0d1f9e7a")
+		b.complete(id, nil)
+		id = b.schedule("PostLedgerEntryActivity", requestID)
+		b.complete(id, nil)
+		b.finish()
+		b.write(dir + "/mint_completed.json")
+	}
+
+	// Redeem that needed one approval, approved and completed.
+	{
+		requestID := "c3e81f4a-6b2d-4f09-8a71-2d5e9b0c7f36"
+		recipient := "0x2d7b9e4f1a6c3e8b0d5f2a7c9e1b4d6f8a0c3e5b"
+		b := newBuilder("2026-09-21T14:40:51.902Z")
+		b.start("mint-redeem-workflow/worker/workflows.RedeemWorkflow", legacyInput{Amount: 150000, Recipient: recipient, Token: "USDC", Chain: "ethereum", RequestID: requestID, Submitter: "treasury", RequiredApprovals: 1, ApprovalTimeout: time.Hour * 24}, 86700)
+		id := b.schedule("ScreeningActivity", recipient, requestID)
+		b.complete(id, activities.ScreeningActivityResponse{RequestId: requestID})
+		id = b.schedule("UpdateStatusActivity", requestID, "awaiting_approval")
+		b.complete(id, nil)
+		timer := b.startTimer(86400)
+		b.signal(workflows.ApprovalSignalName, workflows.ApprovalSignal{Approved: true, Approver: "compliance-lead", Signature: "q7Jm2x9Lw4Vb0Rk8Tn3Yc6Hs1Pf5Ge0Da2Zu7Xi4Ol9Mw3Nv6Bt8Cy1Er5Qh0Kj2Fg4Sd6Ap8Wl1Zx3Ic5Vb7Nm9Q=="})
+		id = b.schedule("VerifyApprovalActivity", requestID, "compliance-lead", "q7Jm2x9Lw4Vb0Rk8Tn3Yc6Hs1Pf5Ge0Da2Zu7Xi4Ol9Mw3Nv6Bt8Cy1Er5Qh0Kj2Fg4Sd6Ap8Wl1Zx3Ic5Vb7Nm9Q==")
+		b.complete(id, activities.VerifyApprovalActivityResponse{Valid: true})
+		id = b.schedule("RecordApprovalActivity", requestID, "compliance-lead", true, "", "q7Jm2x9Lw4Vb0Rk8Tn3Yc6Hs1Pf5Ge0Da2Zu7Xi4Ol9Mw3Nv6Bt8Cy1Er5Qh0Kj2Fg4Sd6Ap8Wl1Zx3Ic5Vb7Nm9Q==")
+		b.complete(id, nil)
+		id = b.schedule("UpdateStatusWithReasonActivity", requestID, "approved", "")
+		b.complete(id, nil)
+		b.cancelTimer(timer)
+		id = b.schedule("RedeemActivity", 150000.0, recipient, "USDC", "ethereum", requestID)
+		b.complete(id, activities.OperationActivityResponse{RequestId: requestID, OrderID: "2xP7mS3kV9nR1tY5wB8dG2hJ4fL", TxHash: "0x9b2e7d4a1f8c6e3b0a5d2f9c7e4b1a8d6f3c0e9b7a5d2f8c4e1b6a3d0f7c9e2b"})
+		id = b.schedule("CompleteRequestActivity", requestID, "2xP7mS3kV9nR1tY5wB8dG2hJ4fL", "0x9b2e7d4a1f8c6e3b0a5d2f9c7e4b1a8d6f3c0e9b7a5d2f8c4e1b6a3d0f7c9e2b")
+		b.complete(id, nil)
+		id = b.schedule("PostLedgerEntryActivity", requestID)
+		b.complete(id, nil)
+		b.finish()
+		b.write(dir + "/redeem_approved.json")
+	}
+
+	// Mint run as a RequestWorkflow, placed with ExecuteOperationActivity.
+	{
+		requestID := "8e4b2f61-d93a-4c07-b5e8-1f6a0c3d9b27"
+		recipient := "0x6c1e8a3f5b9d2e7a0c4f8b1d6e3a9c5f2b7d0e48"
+		b := newBuilder("2026-10-06T11:27:44.135Z")
+		b.start("mint-redeem-workflow/worker/workflows.RequestWorkflow", workflows.RequestInput{Type: "mint", Amount: 780, Recipient: recipient, Token: "SBC", Chain: "base", RequestID: requestID, Submitter: "treasury"}, 300)
+		id := b.schedule("ScreeningActivity", recipient, requestID)
+		b.complete(id, activities.ScreeningActivityResponse{RequestId: requestID})
+		b.version("operation-activity", 1)
+		id = b.schedule("ExecuteOperationActivity", "mint", 780.0, recipient, "SBC", "base", requestID)
+		b.complete(id, activities.OperationActivityResponse{RequestId: requestID, OrderID: "2xR5nT8wQ3kM1vY7bD9fH4jL6gS", TxHash: "0x1d7f3b9e5a2c8f4d0b6e1a7c3f9d5b2e8a4c0f6d1b7e3a9c5f2d8b4e0a6c1f7d"})
+		id = b.schedule("CompleteRequestActivity", requestID, "2xR5nT8wQ3kM1vY7bD9fH4jL6gS", "0x1d7f3b9e5a2c8f4d0b6e1a7c3f9d5b2e8a4c0f6d1b7e3a9c5f2d8b4e0a6c1f7d")
+		b.complete(id, nil)
+		id = b.schedule("PostLedgerEntryActivity", requestID)
+		b.complete(id, nil)
+		b.finish()
+		b.write(dir + "/request_mint_completed.json")
+	}
+}
@@ -0,0 +1,41 @@
+package workflows
+
+// Change IDs for workflow.GetVersion. Workflows that were running before a
+// change ID was added replay as workflow.DefaultVersion, so that branch has to
+// keep making exactly the decisions the workflow made before the change. A
+// change to the activities, timers or markers a workflow schedules gets its own
+// change ID, or a new max version of an existing one, and keeps its old
+// branches until no open workflow can replay them. The histories in
+// testdata/histories are replayed against the workflows by the tests.
+const (
+	// operationActivityChangeID places the order with ExecuteOperationActivity
+	// instead of MintActivity or RedeemActivity. Version 1 is the generic
+	// activity.
+	operationActivityChangeID = "operation-activity"
+
+	// requestWorkflowChangeID starts batch and schedule children as
+	// RequestWorkflow instead of MintWorkflow or RedeemWorkflow. Version 1 is
+	// RequestWorkflow.
+	requestWorkflowChangeID = "request-workflow"
+
+	// occurrenceIDChangeID keys a schedule's requests by the time each cron
+	// run was due instead of the run ID. Version 1 is the fire time.
+	occurrenceIDChangeID = "occurrence-id"
+
+	// reconcileActivityChangeID reconciles in ReconcileActivity instead of
+	// returning Brale's orders from FetchBraleOrdersActivity to the workflow.
+	// Version 1 is ReconcileActivity.
+	reconcileActivityChangeID = "reconcile-activity"
+
+	// approvalDecisionChangeID verifies approvals with
+	// VerifyApprovalDecisionActivity, whose signed payload includes the
+	// decision and the approver, instead of VerifyApprovalActivity. Version 1
+	// is VerifyApprovalDecisionActivity.
+	approvalDecisionChangeID = "approval-decision"
+
+	// scheduledStartChangeID starts a future-dated request with
+	// StartScheduledRequestActivity, which leaves a request that was canceled
+	// or rescheduled as its timer fired alone, instead of UpdateStatusActivity.
+	// Version 1 is StartScheduledRequestActivity.
+	scheduledStartChangeID = "scheduled-start"
+)
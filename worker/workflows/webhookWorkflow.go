@@ -0,0 +1,45 @@
+package workflows
+
+import (
+	"time"
+
+	"go.uber.org/cadence"
+	"go.uber.org/cadence/workflow"
+	"go.uber.org/zap"
+)
+
+// webhookRetryPolicy backs off from ten seconds to an hour between attempts
+// and gives up after a day, which the delivery workflow's timeout allows for.
+var webhookRetryPolicy = &cadence.RetryPolicy{
+	InitialInterval:    time.Second * 10,
+	BackoffCoefficient: 2,
+	MaximumInterval:    time.Hour,
+	ExpirationInterval: time.Hour * 24,
+	MaximumAttempts:    20,
+}
+
+var deliverWebhookActivityOptions = workflow.ActivityOptions{
+	ScheduleToStartTimeout: time.Minute,
+	StartToCloseTimeout:    time.Second * 30,
+	RetryPolicy:            webhookRetryPolicy,
+}
+
+// WebhookDeliveryWorkflow posts a webhook delivery until the receiver accepts
+// it or the retries run out, in which case the delivery is marked failed.
+func WebhookDeliveryWorkflow(ctx workflow.Context, deliveryID string) error {
+	logger := workflow.GetLogger(ctx)
+	logger.Info("WebhookDeliveryWorkflow started", zap.String("DeliveryID", deliveryID))
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+
+	deliverCtx := workflow.WithActivityOptions(ctx, deliverWebhookActivityOptions)
+	if err := workflow.ExecuteActivity(deliverCtx, acts.DeliverWebhookActivity, deliveryID).Get(ctx, nil); err != nil {
+		if failErr := workflow.ExecuteActivity(ctx, acts.FailWebhookDeliveryActivity, deliveryID).Get(ctx, nil); failErr != nil {
+			return failErr
+		}
+		return err
+	}
+
+	logger.Info("Webhook delivered.", zap.String("DeliveryID", deliveryID))
+
+	return nil
+}
@@ -2,38 +2,103 @@ package workflows
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/base64"
 	"errors"
 	"mint-redeem-workflow/activities"
+	"mint-redeem-workflow/config"
 	"mint-redeem-workflow/db"
+	"mint-redeem-workflow/events"
+	"mint-redeem-workflow/infra/brale"
+	"mint-redeem-workflow/infra/brale/mocks"
+	"mint-redeem-workflow/infra/sanctions"
 	"mint-redeem-workflow/models"
+	"mint-redeem-workflow/operations"
+	"mint-redeem-workflow/repository"
+	"mint-redeem-workflow/webhooks"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/robfig/cron"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 
 	"go.uber.org/cadence"
 	"go.uber.org/cadence/testsuite"
+	"go.uber.org/zap"
 )
 
 type UnitTestSuite struct {
 	suite.Suite
 	testsuite.WorkflowTestSuite
 
-	env *testsuite.TestWorkflowEnvironment
+	env        *testsuite.TestWorkflowEnvironment
+	activities *activities.Activities
 }
 
 func InitTestDB() {
-	db.InitDB()
+	db.InitMemoryDB()
 	db.Db.Exec("DELETE FROM requests")
+	db.Db.Exec("DELETE FROM approvals")
+	db.Db.Exec("DELETE FROM approver_keys")
+	db.Db.Exec("DELETE FROM request_events")
+	db.Db.Exec("DELETE FROM batches")
+	db.Db.Exec("DELETE FROM schedules")
+	db.Db.Exec("DELETE FROM reconciliation_reports")
+	db.Db.Exec("DELETE FROM reconciliation_items")
+	db.Db.Exec("DELETE FROM webhooks")
+	db.Db.Exec("DELETE FROM webhook_deliveries")
+	db.Db.Exec("DELETE FROM request_attempts")
+}
+
+// registerTestKey registers the approver's key, bound to a client of the same
+// name.
+func registerTestKey(approver string) ed25519.PrivateKey {
+	publicKey, privateKey, _ := ed25519.GenerateKey(nil)
+	db.Db.Create(&models.ApproverKey{Approver: approver, Client: approver, PublicKey: base64.StdEncoding.EncodeToString(publicKey)})
+	return privateKey
+}
+
+func signedApproval(privateKey ed25519.PrivateKey, request models.Request, approver string, approved bool, reason string) ApprovalSignal {
+	return ApprovalSignal{
+		Approved:  approved,
+		Approver:  approver,
+		Client:    approver,
+		Reason:    reason,
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, request.ApprovalDecisionPayload(approver, approved))),
+	}
+}
+
+func requestInput(request models.Request) RequestInput {
+	return RequestInput{
+		Type:      request.Type,
+		Amount:    request.Amount,
+		Recipient: request.Recipient,
+		Token:     request.Token,
+		Chain:     request.Chain,
+		RequestID: request.ID.String(),
+	}
 }
 
 func (s *UnitTestSuite) SetupTest() {
 	s.env = s.NewTestWorkflowEnvironment()
 
-	s.env.RegisterActivity(activities.MintActivity)
-	s.env.RegisterActivity(activities.UpdateStatusActivity)
-	s.env.RegisterActivity(activities.RedeemActivity)
+	s.env.RegisterWorkflow(RequestWorkflow)
+	s.env.RegisterWorkflow(MintWorkflow)
+	s.env.RegisterWorkflow(RedeemWorkflow)
+
+	sdn := sanctions.NewList("../../infra/sanctions/testdata/sdn.csv")
+	s.Require().NoError(sdn.Refresh())
+
+	cfg, err := config.NewServiceConfig()
+	s.Require().NoError(err)
+	db.InitMemoryDB()
+	s.activities = activities.New(brale.NewMockBraleClient(), db.Db, repository.NewGormRequestRepository(db.Db), sdn, zap.NewNop(), cfg)
+	s.env.RegisterActivityWithOptions(s.activities, activities.RegisterOptions)
 
 }
 
@@ -48,12 +113,14 @@ func (s *UnitTestSuite) Test_MintWorkFlow_Success_RequestIsMarkedCompleted() {
 		Type:      "mint",
 		Amount:    10.50,
 		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
 		Status:    "pending",
 	}
 
 	db.Db.Create(&request)
 
-	s.env.ExecuteWorkflow(MintWorkflow, request.Amount, request.Recipient, request.ID.String())
+	s.env.ExecuteWorkflow(RequestWorkflow, requestInput(request))
 
 	s.True(s.env.IsWorkflowCompleted())
 
@@ -62,6 +129,21 @@ func (s *UnitTestSuite) Test_MintWorkFlow_Success_RequestIsMarkedCompleted() {
 	var req models.Request
 	db.Db.First(&req, "id = ?", request.ID)
 	s.Equal("completed", req.Status)
+	s.Equal("2VZvtmVc2j3gQ80CTlcuQXbGrwC", req.ProviderOrderID)
+	s.Equal("0x5b1e8c2b2e4a2a3f8a1f0c9b6e1d2c3b4a5f6e7d8c9b0a1f2e3d4c5b6a7f8e9d", req.TxHash)
+	s.NotNil(req.CompletedAt)
+	s.Nil(req.FailedAt)
+
+	transitions, _, err := events.Transitions(db.Db, events.Filter{})
+	s.NoError(err)
+	s.Require().Len(transitions, 1)
+	s.Equal(events.StatusChange{From: "pending", To: "completed"}, transitions[0].StatusChange)
+
+	var entries []models.JournalEntry
+	db.Db.Preload("Lines").Find(&entries, "request_id = ?", request.ID)
+	s.Require().Len(entries, 1)
+	s.Equal("posting", entries[0].Kind)
+	s.Len(entries[0].Lines, 4)
 }
 
 func (s *UnitTestSuite) Test_MintWorkflow_ActivityParamPassedCorrectly() {
@@ -71,26 +153,30 @@ func (s *UnitTestSuite) Test_MintWorkflow_ActivityParamPassedCorrectly() {
 		Type:      "mint",
 		Amount:    10.50,
 		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
 		Status:    "pending",
 	}
 
 	db.Db.Create(&request)
 
-	s.env.OnActivity(activities.MintActivity, mock.Anything, request.Amount, request.Recipient, request.ID.String()).Return(
-		func(ctx context.Context, amount float64, recepient, requestID string) (activities.MintActivityResponse, error) {
-			s.Equal(request.Recipient, recepient)
+	s.env.OnActivity(acts.ExecuteOperationActivity, mock.Anything, "mint", request.Amount, request.Recipient, request.Token, request.Chain, request.ID.String()).Return(
+		func(ctx context.Context, opType string, amount float64, recipient, token, chain, requestID string) (activities.OperationActivityResponse, error) {
+			s.Equal(request.Recipient, recipient)
+			s.Equal(request.Token, token)
+			s.Equal(request.Chain, chain)
 			s.Equal(request.ID.String(), requestID)
-			return activities.MintActivityResponse{RequestId: requestID}, nil
+			return activities.OperationActivityResponse{RequestId: requestID, OrderID: "order-1", TxHash: "0xhash"}, nil
 		},
 	)
 
-	s.env.OnActivity(activities.UpdateStatusActivity, mock.Anything, request.ID.String(), "completed").Return(
-		func(ctx context.Context, requestID, status string) error {
+	s.env.OnActivity(acts.CompleteRequestActivity, mock.Anything, request.ID.String(), "order-1", "0xhash").Return(
+		func(ctx context.Context, requestID, orderID, txHash string) error {
 			s.Equal(request.ID.String(), requestID)
 			return nil
 		},
 	)
-	s.env.ExecuteWorkflow(MintWorkflow, request.Amount, request.Recipient, request.ID.String())
+	s.env.ExecuteWorkflow(RequestWorkflow, requestInput(request))
 
 	s.True(s.env.IsWorkflowCompleted())
 	s.NoError(s.env.GetWorkflowError())
@@ -103,20 +189,22 @@ func (s *UnitTestSuite) Test_MintWorkflow_MintActivityFails_UpdatesRequestToFail
 		Type:      "mint",
 		Amount:    10.50,
 		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
 		Status:    "pending",
 	}
 
 	db.Db.Create(&request)
 
-	s.env.OnActivity(activities.MintActivity, mock.Anything, request.Amount, request.Recipient, request.ID.String()).Return(
-		func(ctx context.Context, amount float64, recepient, requestID string) (activities.MintActivityResponse, error) {
-			s.Equal(request.Recipient, recepient)
+	s.env.OnActivity(acts.ExecuteOperationActivity, mock.Anything, "mint", request.Amount, request.Recipient, request.Token, request.Chain, request.ID.String()).Return(
+		func(ctx context.Context, opType string, amount float64, recipient, token, chain, requestID string) (activities.OperationActivityResponse, error) {
+			s.Equal(request.Recipient, recipient)
 			s.Equal(request.ID.String(), requestID)
-			return activities.MintActivityResponse{RequestId: requestID}, errors.New("test error")
+			return activities.OperationActivityResponse{RequestId: requestID}, errors.New("test error")
 		},
 	)
 
-	s.env.ExecuteWorkflow(MintWorkflow, request.Amount, request.Recipient, request.ID.String())
+	s.env.ExecuteWorkflow(RequestWorkflow, requestInput(request))
 
 	s.True(s.env.IsWorkflowCompleted())
 
@@ -127,6 +215,10 @@ func (s *UnitTestSuite) Test_MintWorkflow_MintActivityFails_UpdatesRequestToFail
 	var req models.Request
 	db.Db.First(&req, "id = ?", request.ID)
 	s.Equal("failed", req.Status)
+
+	var entries int64
+	db.Db.Model(&models.JournalEntry{}).Where("request_id = ?", request.ID).Count(&entries)
+	s.Equal(int64(0), entries)
 }
 
 func (s *UnitTestSuite) Test_RedeemWorkflow_Success_RequestIsMarkedCompleted() {
@@ -136,12 +228,14 @@ func (s *UnitTestSuite) Test_RedeemWorkflow_Success_RequestIsMarkedCompleted() {
 		Type:      "redeem",
 		Amount:    10.50,
 		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
 		Status:    "pending",
 	}
 
 	db.Db.Create(&request)
 
-	s.env.ExecuteWorkflow(RedeemWorkflow, request.Amount, request.Recipient, request.ID.String())
+	s.env.ExecuteWorkflow(RequestWorkflow, requestInput(request))
 
 	s.True(s.env.IsWorkflowCompleted())
 	s.NoError(s.env.GetWorkflowError())
@@ -151,6 +245,57 @@ func (s *UnitTestSuite) Test_RedeemWorkflow_Success_RequestIsMarkedCompleted() {
 	s.Equal("completed", req.Status)
 }
 
+func (s *UnitTestSuite) Test_LegacyRedeemWorkflow_InputWithoutType_RunsAsRedeem() {
+	InitTestDB()
+	request := models.Request{
+		ID:        uuid.New(),
+		Type:      "redeem",
+		Amount:    10.50,
+		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
+		Status:    "pending",
+	}
+
+	db.Db.Create(&request)
+
+	input := requestInput(request)
+	input.Type = ""
+	s.env.ExecuteWorkflow(RedeemWorkflow, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var req models.Request
+	db.Db.First(&req, "id = ?", request.ID)
+	s.Equal("completed", req.Status)
+}
+
+func (s *UnitTestSuite) Test_RequestWorkflow_UnknownOperation_RequestIsFailed() {
+	InitTestDB()
+	request := models.Request{
+		ID:        uuid.New(),
+		Type:      "burn",
+		Amount:    10.50,
+		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
+		Status:    "pending",
+	}
+
+	db.Db.Create(&request)
+
+	s.env.ExecuteWorkflow(RequestWorkflow, requestInput(request))
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.Error(s.env.GetWorkflowError())
+
+	var req models.Request
+	db.Db.First(&req, "id = ?", request.ID)
+	s.Equal("failed", req.Status)
+	s.Equal("unknown_operation", req.ErrorCode)
+}
+
 func (s *UnitTestSuite) Test_RedeemWorkflow_ActivityParamPassedCorrectly() {
 	InitTestDB()
 	request := models.Request{
@@ -158,28 +303,33 @@ func (s *UnitTestSuite) Test_RedeemWorkflow_ActivityParamPassedCorrectly() {
 		Type:      "redeem",
 		Amount:    10.50,
 		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
 		Status:    "pending",
 	}
 
 	db.Db.Create(&request)
 
-	s.env.OnActivity(activities.RedeemActivity, mock.Anything, request.Amount, request.Recipient, request.ID.String()).Return(
-		func(ctx context.Context, amount float64, recipient, requestID string) (activities.RedeemActivityResponse, error) {
+	s.env.OnActivity(acts.ExecuteOperationActivity, mock.Anything, "redeem", request.Amount, request.Recipient, request.Token, request.Chain, request.ID.String()).Return(
+		func(ctx context.Context, opType string, amount float64, recipient, token, chain, requestID string) (activities.OperationActivityResponse, error) {
 			s.Equal(request.Amount, amount)
 			s.Equal(request.Recipient, recipient)
+			s.Equal(request.Token, token)
+			s.Equal(request.Chain, chain)
 			s.Equal(request.ID.String(), requestID)
-			return activities.RedeemActivityResponse{RequestId: requestID}, nil
+			return activities.OperationActivityResponse{RequestId: requestID, OrderID: "order-1", TxHash: "0xhash"}, nil
 		},
 	)
 
-	s.env.OnActivity(activities.UpdateStatusActivity, mock.Anything, request.ID.String(), "completed").Return(
-		func(ctx context.Context, requestID, status string) error {
+	s.env.OnActivity(acts.CompleteRequestActivity, mock.Anything, request.ID.String(), "order-1", "0xhash").Return(
+		func(ctx context.Context, requestID, orderID, txHash string) error {
 			s.Equal(request.ID.String(), requestID)
-			s.Equal("completed", status)
+			s.Equal("order-1", orderID)
+			s.Equal("0xhash", txHash)
 			return nil
 		},
 	)
-	s.env.ExecuteWorkflow(RedeemWorkflow, request.Amount, request.Recipient, request.ID.String())
+	s.env.ExecuteWorkflow(RequestWorkflow, requestInput(request))
 
 	s.True(s.env.IsWorkflowCompleted())
 	s.NoError(s.env.GetWorkflowError())
@@ -192,20 +342,22 @@ func (s *UnitTestSuite) Test_RedeemWorkflow_RedeemActivityFails_UpdatesRequestTo
 		Type:      "redeem",
 		Amount:    10.50,
 		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
 		Status:    "pending",
 	}
 
 	db.Db.Create(&request)
 
-	s.env.OnActivity(activities.RedeemActivity, mock.Anything, request.Amount, request.Recipient, request.ID.String()).Return(
-		func(ctx context.Context, amount float64, recipient, requestID string) (activities.RedeemActivityResponse, error) {
+	s.env.OnActivity(acts.ExecuteOperationActivity, mock.Anything, "redeem", request.Amount, request.Recipient, request.Token, request.Chain, request.ID.String()).Return(
+		func(ctx context.Context, opType string, amount float64, recipient, token, chain, requestID string) (activities.OperationActivityResponse, error) {
 			s.Equal(request.Recipient, recipient)
 			s.Equal(request.ID.String(), requestID)
-			return activities.RedeemActivityResponse{RequestId: requestID}, errors.New("test error")
+			return activities.OperationActivityResponse{RequestId: requestID}, errors.New("test error")
 		},
 	)
 
-	s.env.ExecuteWorkflow(RedeemWorkflow, request.Amount, request.Recipient, request.ID.String())
+	s.env.ExecuteWorkflow(RequestWorkflow, requestInput(request))
 
 	s.True(s.env.IsWorkflowCompleted())
 	s.NotNil(s.env.GetWorkflowError())
@@ -216,6 +368,763 @@ func (s *UnitTestSuite) Test_RedeemWorkflow_RedeemActivityFails_UpdatesRequestTo
 	s.Equal("failed", req.Status)
 }
 
+func (s *UnitTestSuite) Test_MintWorkflow_SanctionedRecipient_RequestIsBlocked() {
+	InitTestDB()
+	request := models.Request{
+		ID:        uuid.New(),
+		Type:      "mint",
+		Amount:    10.50,
+		Recipient: "0x2F389CE8BD8FF92DE3402FFCE4691D17FC4F6535",
+		Token:     "USDC",
+		Chain:     "ethereum",
+		Status:    "pending",
+	}
+
+	db.Db.Create(&request)
+
+	s.env.ExecuteWorkflow(RequestWorkflow, requestInput(request))
+
+	s.True(s.env.IsWorkflowCompleted())
+
+	var customErr *cadence.CustomError
+	s.ErrorAs(s.env.GetWorkflowError(), &customErr)
+	s.Equal("blocked", customErr.Reason())
+
+	var req models.Request
+	db.Db.First(&req, "id = ?", request.ID)
+	s.Equal("blocked", req.Status)
+	s.Contains(req.Reason, "SUEX OTC, S.R.O.")
+}
+
+func (s *UnitTestSuite) Test_RedeemWorkflow_SanctionedRecipient_RequestIsBlocked() {
+	InitTestDB()
+	request := models.Request{
+		ID:        uuid.New(),
+		Type:      "redeem",
+		Amount:    10.50,
+		Recipient: "12HQDsicffSBaYdJ6BhnE22sfjTESmmzKx",
+		Token:     "USDC",
+		Chain:     "ethereum",
+		Status:    "pending",
+	}
+
+	db.Db.Create(&request)
+
+	s.env.ExecuteWorkflow(RequestWorkflow, requestInput(request))
+
+	s.True(s.env.IsWorkflowCompleted())
+
+	var customErr *cadence.CustomError
+	s.ErrorAs(s.env.GetWorkflowError(), &customErr)
+	s.Equal("blocked", customErr.Reason())
+
+	var req models.Request
+	db.Db.First(&req, "id = ?", request.ID)
+	s.Equal("blocked", req.Status)
+	s.Contains(req.Reason, "XBT")
+}
+
+func (s *UnitTestSuite) Test_MintWorkflow_ApprovalRequired_ApprovedRequestIsCompleted() {
+	InitTestDB()
+	request := models.Request{
+		ID:        uuid.New(),
+		Type:      "mint",
+		Amount:    50000,
+		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
+		Status:    "pending",
+	}
+
+	db.Db.Create(&request)
+
+	makerKey := registerTestKey("maker")
+	aliceKey := registerTestKey("alice")
+	bobKey := registerTestKey("bob")
+	malloryKey := registerTestKey("mallory")
+	danKey := registerTestKey("dan")
+	otherRequest := request
+	otherRequest.Recipient = "0xmallory"
+
+	// dan's key is valid, but the decision was sent by the submitting client.
+	fromSubmitter := signedApproval(danKey, request, "dan", true, "")
+	fromSubmitter.Client = "maker"
+	// bob signed a rejection, which can't be sent as an approval.
+	flipped := signedApproval(bobKey, request, "bob", false, "")
+	flipped.Approved = true
+
+	input := requestInput(request)
+	input.Submitter = "maker"
+	input.RequiredApprovals = 2
+	input.ApprovalTimeout = time.Hour
+
+	s.env.RegisterDelayedCallback(func() {
+		var req models.Request
+		db.Db.First(&req, "id = ?", request.ID)
+		s.Equal("awaiting_approval", req.Status)
+
+		s.env.SignalWorkflow(ApprovalSignalName, signedApproval(makerKey, request, "maker", true, ""))
+		s.env.SignalWorkflow(ApprovalSignalName, fromSubmitter)
+		s.env.SignalWorkflow(ApprovalSignalName, signedApproval(aliceKey, request, "alice", true, ""))
+	}, time.Minute)
+
+	s.env.RegisterDelayedCallback(func() {
+		s.env.SignalWorkflow(ApprovalSignalName, signedApproval(aliceKey, request, "alice", true, ""))
+		s.env.SignalWorkflow(ApprovalSignalName, signedApproval(malloryKey, otherRequest, "mallory", true, ""))
+		s.env.SignalWorkflow(ApprovalSignalName, flipped)
+	}, time.Minute*2)
+
+	s.env.RegisterDelayedCallback(func() {
+		value, err := s.env.QueryWorkflow(ApprovalStateQuery)
+		s.NoError(err)
+
+		var state ApprovalState
+		s.NoError(value.Get(&state))
+		s.Equal("pending", state.Decision)
+		s.Equal(2, state.Required)
+		s.Len(state.Approvals, 1)
+		s.True(state.SignsDecision)
+		s.Len(state.Ignored, 5)
+		s.Equal("dan", state.Ignored[1].Approver)
+		s.Equal("submitter can't approve their own request", state.Ignored[1].Reason)
+		s.Equal("signature does not match the request", state.Ignored[3].Reason)
+		s.Equal("bob", state.Ignored[4].Approver)
+		s.Equal("signature does not match the request", state.Ignored[4].Reason)
+
+		s.env.SignalWorkflow(ApprovalSignalName, signedApproval(bobKey, request, "bob", true, ""))
+	}, time.Minute*3)
+
+	s.env.ExecuteWorkflow(RequestWorkflow, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var req models.Request
+	db.Db.Preload("Approvals").First(&req, "id = ?", request.ID)
+	s.Equal("completed", req.Status)
+	s.Len(req.Approvals, 2)
+	s.Equal("alice", req.Approvals[0].Approver)
+	s.Equal("bob", req.Approvals[1].Approver)
+
+	var events []models.RequestEvent
+	db.Db.Where("request_id = ? AND type = ?", request.ID, "approval_signed").Order("id").Find(&events)
+	s.Len(events, 2)
+	s.Contains(events[0].Data, `"approver":"alice"`)
+	s.Contains(events[0].Data, base64.StdEncoding.EncodeToString(ed25519.Sign(aliceKey, request.ApprovalDecisionPayload("alice", true))))
+	s.Contains(events[0].Data, `\napprove\nalice"`)
+}
+
+func (s *UnitTestSuite) Test_RedeemWorkflow_ApprovalRequired_RejectedRequestIsNotSentToBrale() {
+	InitTestDB()
+	request := models.Request{
+		ID:        uuid.New(),
+		Type:      "redeem",
+		Amount:    50000,
+		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
+		Status:    "pending",
+	}
+
+	db.Db.Create(&request)
+
+	input := requestInput(request)
+	input.RequiredApprovals = 1
+	input.ApprovalTimeout = time.Hour
+
+	bobKey := registerTestKey("bob")
+
+	s.env.RegisterDelayedCallback(func() {
+		s.env.SignalWorkflow(ApprovalSignalName, signedApproval(bobKey, request, "bob", false, "unexpected amount"))
+	}, time.Minute)
+
+	s.env.ExecuteWorkflow(RequestWorkflow, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+
+	var customErr *cadence.CustomError
+	s.ErrorAs(s.env.GetWorkflowError(), &customErr)
+	s.Equal("rejected", customErr.Reason())
+
+	var req models.Request
+	db.Db.Preload("Approvals").First(&req, "id = ?", request.ID)
+	s.Equal("rejected", req.Status)
+	s.Equal("unexpected amount", req.Reason)
+	s.Len(req.Approvals, 1)
+	s.Equal("bob", req.Approvals[0].Approver)
+	s.False(req.Approvals[0].Approved)
+}
+
+func (s *UnitTestSuite) Test_MintWorkflow_ApprovalActivityFails_RequestIsFailed() {
+	InitTestDB()
+	request := models.Request{
+		ID:        uuid.New(),
+		Type:      "mint",
+		Amount:    50000,
+		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
+		Status:    "pending",
+	}
+
+	db.Db.Create(&request)
+
+	input := requestInput(request)
+	input.RequiredApprovals = 1
+	input.ApprovalTimeout = time.Hour
+
+	bobKey := registerTestKey("bob")
+	s.env.OnActivity(acts.VerifyApprovalDecisionActivity, mock.Anything, request.ID.String(), "bob", "bob", true, mock.Anything).Return(
+		activities.VerifyApprovalActivityResponse{}, cadence.NewCustomError("key_store_unavailable", "approver keys can't be read"),
+	)
+
+	s.env.RegisterDelayedCallback(func() {
+		s.env.SignalWorkflow(ApprovalSignalName, signedApproval(bobKey, request, "bob", true, ""))
+	}, time.Minute)
+
+	s.env.ExecuteWorkflow(RequestWorkflow, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.Error(s.env.GetWorkflowError())
+
+	var req models.Request
+	db.Db.First(&req, "id = ?", request.ID)
+	s.Equal("failed", req.Status)
+	s.Equal("key_store_unavailable", req.ErrorCode)
+	s.Equal("approver keys can't be read", req.ErrorDetail)
+}
+
+func (s *UnitTestSuite) Test_MintWorkflow_ApprovalTimesOut_RequestIsAutoRejected() {
+	InitTestDB()
+	request := models.Request{
+		ID:        uuid.New(),
+		Type:      "mint",
+		Amount:    50000,
+		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
+		Status:    "pending",
+	}
+
+	db.Db.Create(&request)
+
+	input := requestInput(request)
+	input.RequiredApprovals = 1
+	input.ApprovalTimeout = time.Hour
+
+	s.env.ExecuteWorkflow(RequestWorkflow, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+
+	var customErr *cadence.CustomError
+	s.ErrorAs(s.env.GetWorkflowError(), &customErr)
+	s.Equal("rejected", customErr.Reason())
+
+	var req models.Request
+	db.Db.Preload("Approvals").First(&req, "id = ?", request.ID)
+	s.Equal("rejected", req.Status)
+	s.Equal("approval timed out", req.Reason)
+	s.Len(req.Approvals, 1)
+	s.Equal("system", req.Approvals[0].Approver)
+}
+
+func (s *UnitTestSuite) Test_RedeemWorkflow_InjectedBraleClient_InsufficientFundsFailsRequest() {
+	InitTestDB()
+	request := models.Request{
+		ID:        uuid.New(),
+		Type:      "redeem",
+		Amount:    10.50,
+		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
+		Status:    "pending",
+	}
+	db.Db.Create(&request)
+
+	braleClient := mocks.NewMockBraleClient(gomock.NewController(s.T()))
+	braleClient.EXPECT().Redeem(request.Amount, request.Recipient, request.Chain, request.Token, request.ID.String()).
+		Return(&brale.APIResponse{Errors: []brale.ErrorDetail{{Code: "InsufficientFunds", Detail: "Not enough USDC."}}}, nil).
+		Times(1)
+	s.activities.BraleClient = braleClient
+
+	s.env.ExecuteWorkflow(RequestWorkflow, requestInput(request))
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.Error(s.env.GetWorkflowError())
+
+	var req models.Request
+	db.Db.First(&req, "id = ?", request.ID)
+	s.Equal("failed", req.Status)
+	s.Equal(brale.ReasonInsufficientFunds, req.ErrorCode)
+}
+
+func (s *UnitTestSuite) Test_MintWorkflow_TerminalBraleError_IsNotRetriedAndErrorCodeIsSaved() {
+	InitTestDB()
+	request := models.Request{
+		ID:        uuid.New(),
+		Type:      "mint",
+		Amount:    10.50,
+		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
+		Status:    "pending",
+	}
+
+	db.Db.Create(&request)
+
+	attempts := 0
+	s.env.OnActivity(acts.ExecuteOperationActivity, mock.Anything, "mint", request.Amount, request.Recipient, request.Token, request.Chain, request.ID.String()).Return(
+		func(ctx context.Context, opType string, amount float64, recipient, token, chain, requestID string) (activities.OperationActivityResponse, error) {
+			attempts++
+			return activities.OperationActivityResponse{RequestId: requestID}, cadence.NewCustomError(brale.ReasonValidation, "An error occurred with the request data.")
+		},
+	)
+
+	s.env.ExecuteWorkflow(RequestWorkflow, requestInput(request))
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.Equal(1, attempts)
+
+	var customErr *cadence.CustomError
+	s.ErrorAs(s.env.GetWorkflowError(), &customErr)
+	s.Equal(brale.ReasonValidation, customErr.Reason())
+
+	var req models.Request
+	db.Db.First(&req, "id = ?", request.ID)
+	s.Equal("failed", req.Status)
+	s.Equal(brale.ReasonValidation, req.ErrorCode)
+	s.Equal("An error occurred with the request data.", req.ErrorDetail)
+	s.NotNil(req.FailedAt)
+	s.Nil(req.CompletedAt)
+}
+
+func (s *UnitTestSuite) Test_RedeemWorkflow_TransientBraleError_IsRetriedUntilSuccess() {
+	InitTestDB()
+	request := models.Request{
+		ID:        uuid.New(),
+		Type:      "redeem",
+		Amount:    10.50,
+		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
+		Status:    "pending",
+	}
+
+	db.Db.Create(&request)
+
+	attempts := 0
+	s.env.OnActivity(acts.ExecuteOperationActivity, mock.Anything, "redeem", request.Amount, request.Recipient, request.Token, request.Chain, request.ID.String()).Return(
+		func(ctx context.Context, opType string, amount float64, recipient, token, chain, requestID string) (activities.OperationActivityResponse, error) {
+			attempts++
+			if attempts < 3 {
+				return activities.OperationActivityResponse{RequestId: requestID}, cadence.NewCustomError(brale.ReasonServerError, "Service Unavailable")
+			}
+			return activities.OperationActivityResponse{RequestId: requestID}, nil
+		},
+	)
+
+	s.env.ExecuteWorkflow(RequestWorkflow, requestInput(request))
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+	s.Equal(3, attempts)
+
+	var req models.Request
+	db.Db.First(&req, "id = ?", request.ID)
+	s.Equal("completed", req.Status)
+}
+
+func (s *UnitTestSuite) Test_BatchWorkflow_OneItemFails_OthersCompleteAndBatchIsPartiallyFailed() {
+	InitTestDB()
+	batch := models.Batch{Status: "started"}
+	db.Db.Create(&batch)
+
+	requests := []models.Request{
+		{Type: "mint", Amount: 10.50, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum", Status: "pending", BatchID: &batch.ID},
+		{Type: "redeem", Amount: 5, Recipient: "0xdeadbeef", Token: "USDC", Chain: "polygon", Status: "pending", BatchID: &batch.ID},
+		{Type: "redeem", Amount: 7, Recipient: "0xnotdeadbeef", Token: "SBC", Chain: "base", Status: "pending", BatchID: &batch.ID},
+	}
+	for i := range requests {
+		db.Db.Create(&requests[i])
+	}
+
+	mint := requestInput(requests[0])
+	failingRedeem := requestInput(requests[1])
+	redeem := requestInput(requests[2])
+	input := BatchInput{
+		BatchID:     batch.ID.String(),
+		Concurrency: 2,
+		Items: []BatchItem{
+			{Request: &mint},
+			{Request: &failingRedeem},
+			{Request: &redeem},
+		},
+	}
+
+	s.env.ExecuteWorkflow(BatchWorkflow, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var saved models.Batch
+	db.Db.Preload("Requests").First(&saved, "id = ?", batch.ID)
+	s.Equal("partially_failed", saved.Status)
+
+	statuses := map[string]string{}
+	for _, request := range saved.Requests {
+		statuses[request.ID.String()] = request.Status
+	}
+	s.Equal("completed", statuses[requests[0].ID.String()])
+	s.Equal("failed", statuses[requests[1].ID.String()])
+	s.Equal("completed", statuses[requests[2].ID.String()])
+}
+
+func (s *UnitTestSuite) Test_ScheduledRequestWorkflow_ActiveSchedule_CreatesOneLinkedRequestPerOccurrence() {
+	InitTestDB()
+	schedule := models.Schedule{Type: "mint", Amount: 25, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", CronSchedule: "0 9 * * 1-5", Status: "active"}
+	db.Db.Create(&schedule)
+
+	s.env.ExecuteWorkflow(ScheduledRequestWorkflow, ScheduleInput{ScheduleID: schedule.ID.String()})
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var requests []models.Request
+	db.Db.Find(&requests, "schedule_id = ?", schedule.ID)
+	s.Require().Len(requests, 1)
+	s.Equal("completed", requests[0].Status)
+	s.Equal(25.0, requests[0].Amount)
+}
+
+func (s *UnitTestSuite) Test_ScheduledRequestWorkflow_KeysRequestByFireTime() {
+	InitTestDB()
+	schedule := models.Schedule{Type: "mint", Amount: 25, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", CronSchedule: "0 9 * * 1-5", Status: "active"}
+	db.Db.Create(&schedule)
+
+	s.env.SetStartTime(time.Date(2026, 10, 19, 9, 0, 30, 0, time.UTC))
+	s.env.SetWorkflowCronSchedule(schedule.CronSchedule)
+	s.env.SetWorkflowCronMaxIterations(1)
+	s.env.ExecuteWorkflow(ScheduledRequestWorkflow, ScheduleInput{ScheduleID: schedule.ID.String()})
+
+	s.True(s.env.IsWorkflowCompleted())
+
+	var requests []models.Request
+	db.Db.Find(&requests, "schedule_id = ?", schedule.ID)
+	s.Require().Len(requests, 1)
+	s.Equal(uuid.NewSHA1(schedule.ID, []byte("2026-10-19T09:00:00Z")), requests[0].ID)
+}
+
+func (s *UnitTestSuite) Test_LastFiring_ReturnsLatestFiringAtOrBeforeNow() {
+	schedule, err := cron.ParseStandard("0 9 * * 1-5")
+	s.Require().NoError(err)
+
+	for now, expected := range map[time.Time]time.Time{
+		time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC):  time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+		time.Date(2026, 10, 19, 9, 0, 42, 0, time.UTC): time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+		time.Date(2026, 10, 19, 8, 59, 0, 0, time.UTC): time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+		time.Date(2026, 10, 25, 12, 0, 0, 0, time.UTC): time.Date(2026, 10, 23, 9, 0, 0, 0, time.UTC),
+	} {
+		fired, ok := lastFiring(schedule, now)
+		s.True(ok)
+		s.Equal(expected, fired, now.String())
+	}
+}
+
+func (s *UnitTestSuite) Test_ExecuteOperationActivity_SavesOrderIDBeforeCompletion() {
+	InitTestDB()
+	request := models.Request{ID: uuid.New(), Type: "mint", Amount: 10.50, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum", Status: "started"}
+	db.Db.Create(&request)
+
+	res, err := s.activities.ExecuteOperationActivity(context.Background(), "mint", request.Amount, request.Recipient, request.Token, request.Chain, request.ID.String())
+	s.NoError(err)
+
+	var req models.Request
+	db.Db.First(&req, "id = ?", request.ID)
+	s.Equal("started", req.Status)
+	s.Equal(res.OrderID, req.ProviderOrderID)
+	s.NotEmpty(req.ProviderOrderID)
+	s.NotNil(req.OrderSentAt)
+}
+
+func (s *UnitTestSuite) Test_ExecuteOperationActivity_CanceledRequest_DoesNotPlaceOrder() {
+	InitTestDB()
+	request := models.Request{ID: uuid.New(), Type: "mint", Amount: 10.50, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum", Status: "canceled"}
+	db.Db.Create(&request)
+
+	_, err := s.activities.ExecuteOperationActivity(context.Background(), "mint", request.Amount, request.Recipient, request.Token, request.Chain, request.ID.String())
+	s.Error(err)
+	s.Equal(operations.ReasonCanceled, errorCode(err))
+
+	var req models.Request
+	db.Db.First(&req, "id = ?", request.ID)
+	s.Equal("canceled", req.Status)
+	s.Empty(req.ProviderOrderID)
+	s.Nil(req.OrderSentAt)
+}
+
+func (s *UnitTestSuite) Test_CreateScheduledRequestActivity_SameOccurrence_ReturnsSameRequest() {
+	InitTestDB()
+	schedule := models.Schedule{Type: "mint", Amount: 25, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", CronSchedule: "0 9 * * 1-5", Status: "active"}
+	db.Db.Create(&schedule)
+
+	first, err := s.activities.CreateScheduledRequestActivity(context.Background(), schedule.ID.String(), "occurrence-1")
+	s.NoError(err)
+	again, err := s.activities.CreateScheduledRequestActivity(context.Background(), schedule.ID.String(), "occurrence-1")
+	s.NoError(err)
+	next, err := s.activities.CreateScheduledRequestActivity(context.Background(), schedule.ID.String(), "occurrence-2")
+	s.NoError(err)
+
+	s.Equal(first.RequestId, again.RequestId)
+	s.NotEqual(first.RequestId, next.RequestId)
+
+	var count int64
+	db.Db.Model(&models.Request{}).Where("schedule_id = ?", schedule.ID).Count(&count)
+	s.Equal(int64(2), count)
+}
+
+func (s *UnitTestSuite) Test_ScheduledRequestWorkflow_PausedSchedule_SkipsOccurrence() {
+	InitTestDB()
+	schedule := models.Schedule{Type: "redeem", Amount: 25, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "base", CronSchedule: "0 9 * * 1-5", Status: "paused"}
+	db.Db.Create(&schedule)
+
+	s.env.ExecuteWorkflow(ScheduledRequestWorkflow, ScheduleInput{ScheduleID: schedule.ID.String()})
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var count int64
+	db.Db.Model(&models.Request{}).Where("schedule_id = ?", schedule.ID).Count(&count)
+	s.Equal(int64(0), count)
+}
+
+func (s *UnitTestSuite) Test_MintWorkflow_ExecuteAtRescheduled_WaitsForNewTimeThenCompletes() {
+	InitTestDB()
+	executeAt := s.env.Now().Add(time.Hour)
+	request := models.Request{
+		ID:        uuid.New(),
+		Type:      "mint",
+		Amount:    10.50,
+		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
+		Status:    "scheduled",
+		ExecuteAt: &executeAt,
+	}
+	db.Db.Create(&request)
+
+	input := requestInput(request)
+	input.ExecuteAt = executeAt
+	rescheduledAt := executeAt.Add(time.Hour * 2)
+
+	s.env.RegisterDelayedCallback(func() {
+		var req models.Request
+		db.Db.First(&req, "id = ?", request.ID)
+		s.Equal("scheduled", req.Status)
+		s.env.SignalWorkflow(RescheduleSignalName, RescheduleSignal{ExecuteAt: rescheduledAt})
+	}, time.Minute*30)
+
+	s.env.ExecuteWorkflow(RequestWorkflow, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+	s.False(s.env.Now().Before(rescheduledAt))
+
+	var req models.Request
+	db.Db.First(&req, "id = ?", request.ID)
+	s.Equal("completed", req.Status)
+	s.True(req.ExecuteAt.Equal(rescheduledAt))
+}
+
+func (s *UnitTestSuite) Test_RedeemWorkflow_ExecuteAtCanceled_RequestIsNotSentToBrale() {
+	InitTestDB()
+	executeAt := s.env.Now().Add(time.Hour)
+	request := models.Request{
+		ID:        uuid.New(),
+		Type:      "redeem",
+		Amount:    10.50,
+		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
+		Status:    "scheduled",
+		ExecuteAt: &executeAt,
+	}
+	db.Db.Create(&request)
+
+	input := requestInput(request)
+	input.ExecuteAt = executeAt
+
+	s.env.RegisterDelayedCallback(func() {
+		s.env.SignalWorkflow(RescheduleSignalName, RescheduleSignal{Cancel: true})
+	}, time.Minute*30)
+
+	s.env.ExecuteWorkflow(RequestWorkflow, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+	var customErr *cadence.CustomError
+	s.ErrorAs(s.env.GetWorkflowError(), &customErr)
+	s.Equal("canceled", customErr.Reason())
+
+	var req models.Request
+	db.Db.First(&req, "id = ?", request.ID)
+	s.Equal("canceled", req.Status)
+	s.Empty(req.ProviderOrderID)
+}
+
+func (s *UnitTestSuite) Test_RedeemWorkflow_ExecuteAtCanceledAsTimerFires_RequestIsNotSentToBrale() {
+	InitTestDB()
+	executeAt := s.env.Now().Add(time.Hour)
+	request := models.Request{
+		ID:        uuid.New(),
+		Type:      "redeem",
+		Amount:    10.50,
+		Recipient: "0xnotdeadbeef",
+		Token:     "USDC",
+		Chain:     "ethereum",
+		Status:    "scheduled",
+		ExecuteAt: &executeAt,
+	}
+	db.Db.Create(&request)
+
+	input := requestInput(request)
+	input.ExecuteAt = executeAt
+
+	// The cancel is saved on the request but its signal never arrives.
+	s.env.RegisterDelayedCallback(func() {
+		db.Db.Model(&models.Request{}).Where("id = ?", request.ID).Update("status", "canceled")
+	}, time.Minute*30)
+
+	s.env.ExecuteWorkflow(RequestWorkflow, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+	var customErr *cadence.CustomError
+	s.ErrorAs(s.env.GetWorkflowError(), &customErr)
+	s.Equal("canceled", customErr.Reason())
+
+	var req models.Request
+	db.Db.First(&req, "id = ?", request.ID)
+	s.Equal("canceled", req.Status)
+	s.Empty(req.ProviderOrderID)
+	s.Nil(req.OrderSentAt)
+}
+
+func (s *UnitTestSuite) Test_ReconciliationWorkflow_StoresClassifiedReport() {
+	InitTestDB()
+	completed := models.Request{Type: "mint", Amount: 10.50, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum", Status: "completed", ProviderOrderID: "local-order", TxHash: "0xlocal"}
+	db.Db.Create(&completed)
+	failed := models.Request{Type: "mint", Amount: 10.50, Recipient: "0xdeadbeef", Token: "USDC", Chain: "ethereum", Status: "failed"}
+	db.Db.Create(&failed)
+
+	report := models.ReconciliationReport{From: time.Now().Add(-time.Hour), To: time.Now().Add(time.Hour), Status: "started"}
+	db.Db.Create(&report)
+
+	s.env.ExecuteWorkflow(ReconciliationWorkflow, ReconciliationInput{ReportID: report.ID.String(), From: report.From, To: report.To})
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var saved models.ReconciliationReport
+	db.Db.Preload("Items").First(&saved, "id = ?", report.ID)
+	s.Equal("completed", saved.Status)
+	s.NotNil(saved.CompletedAt)
+	s.Equal(0, saved.Matched)
+	s.Equal(2, saved.MissingRemotely)
+	s.Equal(2, saved.MissingLocally)
+	s.Len(saved.Items, 4)
+
+	kinds := map[string]int{}
+	for _, item := range saved.Items {
+		kinds[item.Kind]++
+	}
+	s.Equal(map[string]int{"order": 2, "transfer": 2}, kinds)
+}
+
+func (s *UnitTestSuite) Test_ReconciliationWorkflow_BraleUnavailable_MarksReportFailed() {
+	InitTestDB()
+	report := models.ReconciliationReport{From: time.Now().Add(-time.Hour), To: time.Now(), Status: "started"}
+	db.Db.Create(&report)
+
+	s.env.OnActivity(acts.ReconcileActivity, mock.Anything, mock.Anything).
+		Return(activities.ReconcileActivityResponse{}, cadence.NewCustomError(brale.ReasonAuth, "token expired"))
+
+	s.env.ExecuteWorkflow(ReconciliationWorkflow, ReconciliationInput{ReportID: report.ID.String(), From: report.From, To: report.To})
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.Error(s.env.GetWorkflowError())
+
+	var saved models.ReconciliationReport
+	db.Db.First(&saved, "id = ?", report.ID)
+	s.Equal("failed", saved.Status)
+	s.Equal("token expired", saved.Error)
+}
+
+func createTestDelivery(url string) (models.Webhook, models.WebhookDelivery) {
+	request := models.Request{Type: "mint", Amount: 10.50, Recipient: "0xnotdeadbeef", Token: "USDC", Chain: "ethereum", Status: "completed", Submitter: "acme"}
+	db.Db.Create(&request)
+	hook := models.Webhook{Client: "acme", URL: url, EventTypes: "*", Secret: "secret"}
+	db.Db.Create(&hook)
+
+	change := events.Transition{EventID: 1, RequestID: request.ID, Submitter: "acme", StatusChange: events.StatusChange{To: "completed"}, CreatedAt: time.Now()}
+	deliveries, _ := webhooks.Enqueue(db.Db, change, request)
+	return hook, deliveries[0]
+}
+
+func (s *UnitTestSuite) Test_WebhookDeliveryWorkflow_ReceiverErrorsOnce_RetriesUntilDelivered() {
+	InitTestDB()
+	var received []*http.Request
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r)
+		if len(received) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+	// The receiver listens on loopback, which the worker's client refuses.
+	s.activities.HTTPClient = receiver.Client()
+
+	_, delivery := createTestDelivery(receiver.URL)
+
+	s.env.ExecuteWorkflow(WebhookDeliveryWorkflow, delivery.ID.String())
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+	s.Len(received, 2)
+	s.Equal(delivery.ID.String(), received[1].Header.Get(webhooks.EventIDHeader))
+
+	var saved models.WebhookDelivery
+	db.Db.First(&saved, "id = ?", delivery.ID)
+	s.Equal("delivered", saved.Status)
+	s.Equal(2, saved.Attempts)
+	s.Equal(http.StatusNoContent, saved.LastStatusCode)
+	s.Empty(saved.LastError)
+	s.NotNil(saved.DeliveredAt)
+}
+
+func (s *UnitTestSuite) Test_WebhookDeliveryWorkflow_ReceiverKeepsFailing_MarksDeliveryFailed() {
+	InitTestDB()
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+	// The receiver listens on loopback, which the worker's client refuses.
+	s.activities.HTTPClient = receiver.Client()
+
+	_, delivery := createTestDelivery(receiver.URL)
+
+	s.env.ExecuteWorkflow(WebhookDeliveryWorkflow, delivery.ID.String())
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.Error(s.env.GetWorkflowError())
+
+	var saved models.WebhookDelivery
+	db.Db.First(&saved, "id = ?", delivery.ID)
+	s.Equal("failed", saved.Status)
+	s.Greater(saved.Attempts, 1)
+	s.Equal(http.StatusInternalServerError, saved.LastStatusCode)
+	s.Nil(saved.DeliveredAt)
+}
+
 func TestUnitTestSuite(t *testing.T) {
 	suite.Run(t, new(UnitTestSuite))
 }